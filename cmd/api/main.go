@@ -9,6 +9,7 @@ import (
 	"Cornerstone/internal/pkg/logger"
 	"Cornerstone/internal/pkg/minio"
 	"Cornerstone/internal/pkg/mongo"
+	"Cornerstone/internal/pkg/recommend"
 	"Cornerstone/internal/pkg/redis"
 	"Cornerstone/internal/wire"
 	"context"
@@ -79,6 +80,9 @@ func main() {
 		panic(err)
 	}
 
+	// 推荐实验初始化
+	recommend.Init()
+
 	// 依赖注入
 	app, err := wire.BuildApplication(db, elasticClient, mongoConn, cfg)
 	if err != nil {
@@ -103,6 +107,20 @@ func main() {
 		return nil
 	})
 
+	// 屏蔽词与图片指纹库加载及热更新，加载失败时仅依赖模型审核
+	if err = app.Blocklist.Reload(ctx); err != nil {
+		log.Error("failed to load content blocklist", "err", err)
+	}
+	if err = app.MediaHashes.Reload(ctx); err != nil {
+		log.Error("failed to load media hash blocklist", "err", err)
+	}
+	g.Go(func() error {
+		return app.Blocklist.Watch(ctx)
+	})
+	g.Go(func() error {
+		return app.MediaHashes.Watch(ctx)
+	})
+
 	// Kafka 消费者
 	g.Go(func() error {
 		log.Info("Kafka Consumers starting...")
@@ -146,5 +164,8 @@ func main() {
 	if err = g.Wait(); err != nil && !errors.Is(err, context.Canceled) {
 		log.Error("App exited with error", "err", err)
 	}
+	if err = app.PostRedriver.Close(); err != nil {
+		log.Error("Post redriver close failed", "err", err)
+	}
 	log.Info("App exited successfully.")
 }
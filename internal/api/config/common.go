@@ -2,26 +2,28 @@ package config
 
 // Config 配置主体
 type Config struct {
-	Server                   ServerConfig   `mapstructure:"server"`
-	DB                       DBConfig       `mapstructure:"database"`
-	Redis                    RedisConfig    `mapstructure:"redis"`
-	Mongo                    MongoConfig    `mapstructure:"mongo"`
-	SMS                      SMSConfig      `mapstructure:"sms"`
-	LLM                      LLMConfig      `mapstructure:"llm"`
-	MinIO                    MinIOConfig    `mapstructure:"minio"`
-	Elastic                  ElasticConfig  `mapstructure:"elastic"`
-	Logstash                 LogstashConfig `mapstructure:"logstash"`
-	LibPath                  LibPathConfig  `mapstructure:"lib_path"`
-	Kafka                    KafkaConfig    `mapstructure:"kafka"`
-	KafkaUserConsumer        KafkaConsumer  `mapstructure:"kafka_user_consumer"`
-	KafkaUserDetailConsumer  KafkaConsumer  `mapstructure:"kafka_user_detail_consumer"`
-	KafkaUserFollowsConsumer KafkaConsumer  `mapstructure:"kafka_user_follow_consumer"`
-	KafkaPostConsumer        KafkaConsumer  `mapstructure:"kafka_post_consumer"`
-	KafkaCommentConsumer     KafkaConsumer  `mapstructure:"kafka_comment_consumer"`
-	KafkaLikeConsumer        KafkaConsumer  `mapstructure:"kafka_like_consumer"`
-	KafkaCollectionConsumer  KafkaConsumer  `mapstructure:"kafka_collection_consumer"`
-	KafkaCommentLikeConsumer KafkaConsumer  `mapstructure:"kafka_comment_like_consumer"`
-	KafkaViewConsumer        KafkaConsumer  `mapstructure:"kafka_view_consumer"`
+	Server                   ServerConfig    `mapstructure:"server"`
+	DB                       DBConfig        `mapstructure:"database"`
+	Redis                    RedisConfig     `mapstructure:"redis"`
+	Mongo                    MongoConfig     `mapstructure:"mongo"`
+	SMS                      SMSConfig       `mapstructure:"sms"`
+	LLM                      LLMConfig       `mapstructure:"llm"`
+	MinIO                    MinIOConfig     `mapstructure:"minio"`
+	Elastic                  ElasticConfig   `mapstructure:"elastic"`
+	Logstash                 LogstashConfig  `mapstructure:"logstash"`
+	LibPath                  LibPathConfig   `mapstructure:"lib_path"`
+	Recommend                RecommendConfig `mapstructure:"recommend"`
+	Reaction                 ReactionConfig  `mapstructure:"reaction"`
+	Kafka                    KafkaConfig     `mapstructure:"kafka"`
+	KafkaUserConsumer        KafkaConsumer   `mapstructure:"kafka_user_consumer"`
+	KafkaUserDetailConsumer  KafkaConsumer   `mapstructure:"kafka_user_detail_consumer"`
+	KafkaUserFollowsConsumer KafkaConsumer   `mapstructure:"kafka_user_follow_consumer"`
+	KafkaPostConsumer        KafkaConsumer   `mapstructure:"kafka_post_consumer"`
+	KafkaCommentConsumer     KafkaConsumer   `mapstructure:"kafka_comment_consumer"`
+	KafkaLikeConsumer        KafkaConsumer   `mapstructure:"kafka_like_consumer"`
+	KafkaCollectionConsumer  KafkaConsumer   `mapstructure:"kafka_collection_consumer"`
+	KafkaCommentLikeConsumer KafkaConsumer   `mapstructure:"kafka_comment_like_consumer"`
+	KafkaViewConsumer        KafkaConsumer   `mapstructure:"kafka_view_consumer"`
 }
 
 // ServerConfig Server配置
@@ -69,13 +71,14 @@ type LLMConfig struct {
 }
 
 type PromptPathConfig struct {
-	AggressiveTag    string `mapstructure:"aggressive_tag"`
-	Chat             string `mapstructure:"chat"`
-	ContentProcess   string `mapstructure:"content_process"`
-	ContentAuditOnly string `mapstructure:"content_audit_only"`
-	ImageProcess     string `mapstructure:"image_process"`
-	ImageAuditOnly   string `mapstructure:"image_audit_only"`
-	Search           string `mapstructure:"search"`
+	AggressiveTag     string `mapstructure:"aggressive_tag"`
+	Chat              string `mapstructure:"chat"`
+	ContentProcess    string `mapstructure:"content_process"`
+	ContentAuditOnly  string `mapstructure:"content_audit_only"`
+	ImageProcess      string `mapstructure:"image_process"`
+	ImageAuditOnly    string `mapstructure:"image_audit_only"`
+	Search            string `mapstructure:"search"`
+	DiscussionSummary string `mapstructure:"discussion_summary"`
 }
 
 // MinIOConfig MinIO配置
@@ -96,6 +99,8 @@ type ElasticConfig struct {
 	Username string         `mapstructure:"username"`
 	Password string         `mapstructure:"password"`
 	Indices  ElasticIndices `mapstructure:"indices"`
+	// MappingDir 索引 mapping 文件所在目录，默认 migrations
+	MappingDir string `mapstructure:"mapping_dir"`
 }
 
 // ElasticIndices Elastic索引
@@ -118,6 +123,34 @@ type LibPathConfig struct {
 	WhisperModel string `mapstructure:"whisper_model"`
 }
 
+// ReactionConfig 表情回应配置
+type ReactionConfig struct {
+	// Emojis 可用的表情代码，客户端负责渲染，未配置时使用默认集合
+	Emojis []string `mapstructure:"emojis"`
+}
+
+// RecommendConfig 推荐实验配置
+type RecommendConfig struct {
+	Experiments []ExperimentConfig `mapstructure:"experiments"`
+}
+
+// ExperimentConfig 单个实验配置，同一时间仅第一个启用的实验生效
+type ExperimentConfig struct {
+	ID       string          `mapstructure:"id"`
+	Enable   bool            `mapstructure:"enable"`
+	Variants []VariantConfig `mapstructure:"variants"`
+}
+
+// VariantConfig 实验分组，Weight 为流量百分比，未配置的排序参数沿用策略默认值
+type VariantConfig struct {
+	Name         string  `mapstructure:"name"`
+	Weight       int     `mapstructure:"weight"`
+	Strategy     string  `mapstructure:"strategy"`
+	KnnBoost     float32 `mapstructure:"knn_boost"`
+	TextBoost    float32 `mapstructure:"text_boost"`
+	RandomWeight float64 `mapstructure:"random_weight"`
+}
+
 type KafkaConfig struct {
 	Brokers  []string       `mapstructure:"brokers"`
 	Sasl     SaslConfig     `mapstructure:"sasl"`
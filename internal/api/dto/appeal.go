@@ -0,0 +1,52 @@
+package dto
+
+// AppealCreateDTO 申诉请求
+type AppealCreateDTO struct {
+	TargetType int8   `json:"target_type" binding:"required" validate:"oneof=1 2"` // 1:笔记 2:评论
+	TargetID   uint64 `json:"target_id" binding:"required"`
+	Message    string `json:"message" binding:"required" validate:"min=1,max=500"`
+}
+
+// AppealQueueQueryDTO 申诉队列查询
+type AppealQueueQueryDTO struct {
+	Status     int8 `form:"status" validate:"oneof=0 1 2"`
+	TargetType int8 `form:"target_type" validate:"omitempty,oneof=1 2"`
+	Page       int  `form:"page,default=1" validate:"min=1"`
+	PageSize   int  `form:"page_size,default=20" validate:"min=1,max=50"`
+}
+
+// AppealHandleDTO 申诉处理请求
+type AppealHandleDTO struct {
+	Note      string   `json:"note" validate:"max=500"`
+	MediaURLs []string `json:"media_urls" validate:"max=20"` // 驳回时审核员指定加入指纹库的媒体，为空时按审核记录判定
+}
+
+// AppealDTO 申诉详情
+type AppealDTO struct {
+	ID          uint64                 `json:"id"`
+	TargetType  int8                   `json:"target_type"`
+	TargetID    uint64                 `json:"target_id"`
+	UserID      uint64                 `json:"user_id"`
+	Message     string                 `json:"message"`
+	Snapshot    string                 `json:"snapshot"`
+	LLMDecision *ModerationDecisionDTO `json:"llm_decision"` // 模型审核结论，过期或人工下架时为空
+	Status      int8                   `json:"status"`       // 0:待处理 1:已通过 2:已驳回
+	HandleNote  string                 `json:"handle_note"`
+	HandledAt   string                 `json:"handled_at,omitempty"`
+	CreatedAt   string                 `json:"created_at"`
+}
+
+// ModerationDecisionDTO 模型审核结论
+type ModerationDecisionDTO struct {
+	Status    int      `json:"status"`
+	Reasons   []string `json:"reasons"`
+	Tags      []string `json:"tags"`
+	Summaries []string `json:"summaries"`
+	AuditedAt string   `json:"audited_at"`
+}
+
+// AppealListDTO 申诉列表
+type AppealListDTO struct {
+	List  []*AppealDTO `json:"list"`
+	Total int64        `json:"total"`
+}
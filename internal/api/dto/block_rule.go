@@ -0,0 +1,38 @@
+package dto
+
+// BlockRuleSaveDTO 屏蔽规则 - 新增或修改
+type BlockRuleSaveDTO struct {
+	Pattern    string `json:"pattern" binding:"required" validate:"min=1,max=200"`
+	MatchType  int8   `json:"match_type" binding:"required" validate:"oneof=1 2"` // 1:关键词 2:正则
+	Action     int8   `json:"action" binding:"required" validate:"oneof=2 3"`     // 2:转人工复核 3:直接拒绝
+	FoldPinyin bool   `json:"fold_pinyin"`                                        // 关键词按拼音匹配谐音变体
+	Enabled    *bool  `json:"enabled" binding:"required"`
+	Remark     string `json:"remark" validate:"max=200"`
+}
+
+// BlockRuleQueryDTO 屏蔽规则列表查询
+type BlockRuleQueryDTO struct {
+	MatchType int8   `form:"match_type" validate:"omitempty,oneof=1 2"`
+	Keyword   string `form:"keyword" validate:"max=50"`
+	Page      int    `form:"page,default=1" validate:"min=1"`
+	PageSize  int    `form:"page_size,default=20" validate:"min=1,max=100"`
+}
+
+// BlockRuleDTO 屏蔽规则详情
+type BlockRuleDTO struct {
+	ID         uint64 `json:"id"`
+	Pattern    string `json:"pattern"`
+	MatchType  int8   `json:"match_type"`
+	Action     int8   `json:"action"`
+	FoldPinyin bool   `json:"fold_pinyin"`
+	Enabled    bool   `json:"enabled"`
+	Remark     string `json:"remark"`
+	CreatedBy  uint64 `json:"created_by"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// BlockRuleListDTO 屏蔽规则列表
+type BlockRuleListDTO struct {
+	List  []*BlockRuleDTO `json:"list"`
+	Total int64           `json:"total"`
+}
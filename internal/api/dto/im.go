@@ -13,14 +13,15 @@ type SendMessageReq struct {
 
 // MessageDTO 消息明细响应
 type MessageDTO struct {
-	ID             string          `json:"id,omitempty"`
-	ConversationID uint64          `json:"conversation_id"`
-	SenderID       uint64          `json:"sender_id"`
-	MsgType        int             `json:"msg_type"`
-	Content        string          `json:"content"`
-	Payload        []MediasBaseDTO `json:"payload"`
-	Seq            uint64          `json:"seq"`
-	CreatedAt      time.Time       `json:"createdAt"`
+	ID             string            `json:"id,omitempty"`
+	ConversationID uint64            `json:"conversation_id"`
+	SenderID       uint64            `json:"sender_id"`
+	MsgType        int               `json:"msg_type"`
+	Content        string            `json:"content"`
+	Payload        []MediasBaseDTO   `json:"payload"`
+	Seq            uint64            `json:"seq"`
+	Reactions      map[string]string `json:"reactions,omitempty"` // 用户ID -> 表情代码
+	CreatedAt      time.Time         `json:"createdAt"`
 }
 
 // ConversationDTO 会话列表项响应
@@ -58,3 +59,20 @@ type MarkAsReadReq struct {
 	ConversationID uint64 `json:"conversation_id" binding:"required"`
 	Sequence       uint64 `json:"sequence" binding:"required"` // 客户端当前看到的最后一条消息序号
 }
+
+// MessageReactionReq 消息表情回应请求
+type MessageReactionReq struct {
+	ConversationID uint64 `json:"conversation_id" binding:"required"`
+	Seq            uint64 `json:"seq" binding:"required"`
+	Action         int    `json:"action" binding:"required,oneof=1 2"` // 1:回应, 2:取消
+	Emoji          string `json:"emoji" binding:"required_if=Action 1"`
+}
+
+// MessageReactionDTO 消息表情回应推送，取消回应时 Emoji 为空
+type MessageReactionDTO struct {
+	ConversationID uint64 `json:"conversation_id"`
+	Seq            uint64 `json:"seq"`
+	UserID         uint64 `json:"user_id"`
+	Emoji          string `json:"emoji"`
+	Type           string `json:"type"`
+}
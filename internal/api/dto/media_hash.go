@@ -0,0 +1,35 @@
+package dto
+
+// MediaHashBlockCreateDTO 将媒体加入违规图片指纹库
+type MediaHashBlockCreateDTO struct {
+	MediaURL string `json:"media_url" binding:"required" validate:"min=1,max=512"` // 图片或视频地址，视频加入已留存的全部抽帧
+	Remark   string `json:"remark" validate:"max=200"`
+}
+
+// MediaHashBlockQueryDTO 违规图片指纹库查询
+type MediaHashBlockQueryDTO struct {
+	Source   int8 `form:"source" validate:"omitempty,oneof=1 2"` // 1:审核员添加 2:拒绝确认后自动添加
+	Page     int  `form:"page,default=1" validate:"min=1"`
+	PageSize int  `form:"page_size,default=20" validate:"min=1,max=100"`
+}
+
+// MediaHashBlockDTO 违规图片指纹
+type MediaHashBlockDTO struct {
+	ID         uint64 `json:"id"`
+	PHash      string `json:"phash"` // 十六进制
+	DHash      string `json:"dhash"`
+	Source     int8   `json:"source"`
+	MediaURL   string `json:"media_url"`
+	Frame      int    `json:"frame"`
+	TargetType int8   `json:"target_type"`
+	TargetID   uint64 `json:"target_id"`
+	Remark     string `json:"remark"`
+	CreatedBy  uint64 `json:"created_by"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// MediaHashBlockListDTO 违规图片指纹列表
+type MediaHashBlockListDTO struct {
+	List  []*MediaHashBlockDTO `json:"list"`
+	Total int64                `json:"total"`
+}
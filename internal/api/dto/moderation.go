@@ -0,0 +1,29 @@
+package dto
+
+// ModerationVerdictQueryDTO 查询内容审核记录
+type ModerationVerdictQueryDTO struct {
+	TargetType int8   `form:"target_type" validate:"required,oneof=1 2"` // 1:笔记 2:评论
+	TargetID   uint64 `form:"target_id" validate:"required"`
+}
+
+// ModerationAuditDTO 一次完整审核，包含各子项结论
+type ModerationAuditDTO struct {
+	AuditID   string                  `json:"audit_id"`
+	Status    int8                    `json:"status"` // 各子项中最严重的结论
+	CreatedAt string                  `json:"created_at"`
+	Verdicts  []*ModerationVerdictDTO `json:"verdicts"`
+}
+
+// ModerationVerdictDTO 单次模型调用的审核结论
+type ModerationVerdictDTO struct {
+	Source        string   `json:"source"` // text / image_batch / video_frame / audio
+	Refs          []string `json:"refs"`
+	Input         string   `json:"input"`
+	Status        int8     `json:"status"`
+	Reason        string   `json:"reason"`
+	Confidence    float64  `json:"confidence"`
+	Model         string   `json:"model"`
+	PromptVersion string   `json:"prompt_version"`
+	LatencyMs     int64    `json:"latency_ms"`
+	Raw           string   `json:"raw"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+// OnboardingTagDTO 兴趣引导标签
+type OnboardingTagDTO struct {
+	TagID       uint64   `json:"tag_id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Covers      []string `json:"covers"` // 该标签下热门帖子的示例封面
+}
+
+// OnboardingStatusDTO 是否需要展示兴趣引导
+type OnboardingStatusDTO struct {
+	NeedOnboarding bool `json:"need_onboarding"`
+}
+
+// OnboardingInterestDTO 提交兴趣选择
+type OnboardingInterestDTO struct {
+	TagIDs []uint64 `json:"tag_ids" binding:"required" validate:"min=1,max=10"`
+}
+
+// OnboardingTagAdminDTO 兴趣引导标签 - 管理端
+type OnboardingTagAdminDTO struct {
+	TagID     uint64 `json:"tag_id"`
+	Name      string `json:"name"`
+	SortOrder int    `json:"sort_order"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// OnboardingTagSaveDTO 兴趣引导标签 - 新增或修改
+type OnboardingTagSaveDTO struct {
+	TagID     uint64 `json:"tag_id" binding:"required"`
+	SortOrder int    `json:"sort_order" validate:"min=0,max=9999"`
+	Enabled   *bool  `json:"enabled" binding:"required"`
+}
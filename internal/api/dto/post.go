@@ -17,13 +17,67 @@ type PostDTO struct {
 	UserID    uint64 `json:"user_id"`
 	Nickname  string `json:"nickname"`
 	AvatarURL string `json:"avatar_url"`
+
+	// 推荐理由，仅推荐流返回
+	Reason *RecommendReasonDTO `json:"reason,omitempty"`
+
+	// 命中片段，仅搜索返回
+	Highlight *PostHighlightDTO `json:"highlight,omitempty"`
+
+	// 评论区讨论总结，仅详情页且评论足够多时返回
+	DiscussionSummary *DiscussionSummaryDTO `json:"discussion_summary,omitempty"`
+}
+
+// DiscussionSummaryDTO AI 生成的评论区讨论总结
+type DiscussionSummaryDTO struct {
+	Overview     string                    `json:"overview"`
+	Viewpoints   []*DiscussionViewpointDTO `json:"viewpoints"`
+	CommentCount int64                     `json:"comment_count"` // 生成时的评论数
+	UpdatedAt    string                    `json:"updated_at"`
+}
+
+// DiscussionViewpointDTO 主要观点及其代表评论
+type DiscussionViewpointDTO struct {
+	Viewpoint  string   `json:"viewpoint"`
+	CommentIDs []uint64 `json:"comment_ids"`
+}
+
+// PostHighlightDTO 搜索命中片段，已做 HTML 转义，命中词以 <em> 包裹
+type PostHighlightDTO struct {
+	Title    string   `json:"title,omitempty"`
+	Snippets []string `json:"snippets,omitempty"`
+}
+
+// RecommendReasonDTO 推荐理由
+type RecommendReasonDTO struct {
+	Code   string `json:"code"`             // followed_author, interest_tag, vector_similarity, trending, latest, explore
+	Detail string `json:"detail,omitempty"` // 命中的标签或作者昵称
 }
 
 // PostWaterfallDTO 帖子瀑布流
 type PostWaterfallDTO struct {
-	List       []*PostDTO `json:"list"`
-	NextCursor string     `json:"next_cursor,omitempty"`
-	HasMore    bool       `json:"has_more"`
+	List         []*PostDTO `json:"list"`
+	NextCursor   string     `json:"next_cursor,omitempty"`
+	HasMore      bool       `json:"has_more"`
+	ExperimentID string     `json:"experiment_id,omitempty"`
+	Variant      string     `json:"variant,omitempty"`
+
+	// 搜索聚合，仅搜索首页且请求聚合时返回
+	Facets *SearchFacetsDTO `json:"facets,omitempty"`
+}
+
+// SearchFacetBucketDTO 搜索聚合桶
+type SearchFacetBucketDTO struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacetsDTO 搜索聚合结果
+type SearchFacetsDTO struct {
+	MainTags   []*SearchFacetBucketDTO `json:"main_tags"`
+	AITags     []*SearchFacetBucketDTO `json:"ai_tags"`
+	MediaTypes []*SearchFacetBucketDTO `json:"media_types"`
+	Dates      []*SearchFacetBucketDTO `json:"dates"`
 }
 
 // PostBaseDTO 帖子 - 新增或修改
@@ -50,16 +104,112 @@ type PostListDTO struct {
 	PageSize int    `form:"page_size,default=20"`
 }
 
+// PostSearchDTO 帖子搜索 - 过滤、聚合与排序
+type PostSearchDTO struct {
+	Keyword   string   `form:"keyword" validate:"max=100"`
+	MainTag   string   `form:"main_tag" validate:"max=50"`
+	AITags    []string `form:"ai_tags" validate:"max=5"`
+	AuthorID  uint64   `form:"author_id"`
+	StartDate string   `form:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string   `form:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	MediaType string   `form:"media_type" validate:"omitempty,oneof=image video audio text"`
+	MinLikes  int      `form:"min_likes" validate:"min=0"`
+	Sort      string   `form:"sort,default=relevance" validate:"oneof=relevance newest most_liked"`
+	Facets    bool     `form:"facets"`
+	Page      int      `form:"page,default=1" validate:"min=1"`
+	PageSize  int      `form:"page_size,default=20" validate:"min=1,max=50"`
+}
+
+// SearchSuggestionDTO 搜索建议
+type SearchSuggestionDTO struct {
+	Keyword string `json:"keyword"`
+	Source  string `json:"source"` // keyword, history, hot, search
+}
+
+// HotSearchDTO 热搜词
+type HotSearchDTO struct {
+	Keyword string `json:"keyword"`
+	Pinned  bool   `json:"pinned"`
+}
+
+// HotSearchScoreDTO 管理端热搜词及热度
+type HotSearchScoreDTO struct {
+	Keyword string  `json:"keyword"`
+	Score   float64 `json:"score"`
+}
+
+// HotSearchPinnedDTO 管理端置顶热搜词
+type HotSearchPinnedDTO struct {
+	Keyword  string `json:"keyword"`
+	Position int    `json:"position"`
+}
+
+// HotSearchAdminDTO 管理端热搜概览
+type HotSearchAdminDTO struct {
+	Hot        []*HotSearchScoreDTO  `json:"hot"`
+	Pinned     []*HotSearchPinnedDTO `json:"pinned"`
+	Suppressed []string              `json:"suppressed"`
+}
+
+// HotSearchPinDTO 置顶热搜词
+type HotSearchPinDTO struct {
+	Keyword  string `json:"keyword" binding:"required" validate:"min=1,max=30"`
+	Position int    `json:"position" binding:"required" validate:"min=1,max=10"`
+}
+
+// HotSearchKeywordDTO 屏蔽热搜词
+type HotSearchKeywordDTO struct {
+	Keyword string `json:"keyword" binding:"required" validate:"min=1,max=30"`
+}
+
 type RecommendPostReq struct {
 	Cursor    string `form:"cursor"`
 	PageSize  int    `form:"page_size,default=10"`
 	SessionID string `form:"session_id"`
 }
 
+// RecommendDebugReq 推荐打分复现请求
+type RecommendDebugReq struct {
+	UserID uint64 `form:"user_id" binding:"required"`
+	PostID uint64 `form:"post_id" binding:"required"`
+}
+
+// RecommendDebugDTO 推荐打分复现结果
+type RecommendDebugDTO struct {
+	UserID           uint64              `json:"user_id"`
+	PostID           uint64              `json:"post_id"`
+	ExperimentID     string              `json:"experiment_id"`
+	Variant          string              `json:"variant"`
+	KnnBoost         float32             `json:"knn_boost"`
+	TextBoost        float32             `json:"text_boost"`
+	RandomWeight     float64             `json:"random_weight"`
+	InterestTags     []string            `json:"interest_tags"`
+	PostMainTag      string              `json:"post_main_tag"`
+	PostAITags       []string            `json:"post_ai_tags"`
+	MatchedTag       string              `json:"matched_tag,omitempty"`
+	FollowedAuthor   bool                `json:"followed_author"`
+	LikesCount       int                 `json:"likes_count"`
+	Trending         bool                `json:"trending"`
+	Viewed           bool                `json:"viewed"` // 已曝光过，推荐流会过滤
+	TextScore        float64             `json:"text_score"`
+	TextExplanation  string              `json:"text_explanation,omitempty"`
+	VectorSimilarity float64             `json:"vector_similarity"`
+	KnnScore         float64             `json:"knn_score"`
+	RandomScoreMax   float64             `json:"random_score_max"`
+	Reason           *RecommendReasonDTO `json:"reason"`
+}
+
+// RecommendDwellDTO 推荐帖子停留时长上报
+type RecommendDwellDTO struct {
+	PostID  uint64 `json:"post_id" binding:"required"`
+	DwellMs int    `json:"dwell_ms" binding:"required" validate:"min=1,max=3600000"`
+}
+
 type PostDeleteDTO struct {
 	ID uint64 `json:"id" binding:"required"`
 }
 
 type PostUpdateDTO struct {
-	Status int `json:"status" binding:"required" validate:"oneof=1 3"`
+	Status    int      `json:"status" binding:"required" validate:"oneof=1 3"`
+	MediaURLs []string `json:"media_urls" validate:"max=20"` // 拒绝时审核员指定加入指纹库的媒体，为空时按审核记录判定
 }
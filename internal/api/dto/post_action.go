@@ -8,35 +8,82 @@ type CommentCreateDTO struct {
 	MediaInfo []*MediasBaseDTO `json:"media_info"`
 }
 
+// CommentEditDTO 编辑评论请求，仅可修改文字内容
+type CommentEditDTO struct {
+	Content string `json:"content" binding:"required,max=1000"`
+}
+
+// CommentEditHistoryDTO 评论编辑记录
+type CommentEditHistoryDTO struct {
+	ID              uint64  `json:"id"`
+	PreviousContent string  `json:"previous_content"`
+	Content         string  `json:"content"`
+	Status          int8    `json:"status"` // 0:待审核 1:已生效 2:未通过
+	CreatedAt       string  `json:"created_at"`
+	ReviewedAt      *string `json:"reviewed_at"`
+}
+
 // CommentDTO 评论返回详情
 type CommentDTO struct {
-	ID              uint64           `json:"id"`
-	PostID          uint64           `json:"post_id"`
-	UserID          uint64           `json:"user_id"`
-	Nickname        string           `json:"nickname"`
-	AvatarURL       string           `json:"avatar_url"`
-	Content         string           `json:"content"`
-	MediaInfo       []*MediasBaseDTO `json:"media_info"`
-	RootID          uint64           `json:"root_id"`
-	ParentID        uint64           `json:"parent_id"`
-	ReplyToUserID   uint64           `json:"reply_to_user_id"`
-	ReplyToNickname string           `json:"reply_to_nickname"`
-	LikesCount      int              `json:"likes_count"`
-	IsLiked         bool             `json:"is_liked"`
-	CreatedAt       string           `json:"created_at"`
+	ID              uint64              `json:"id"`
+	PostID          uint64              `json:"post_id"`
+	UserID          uint64              `json:"user_id"`
+	Nickname        string              `json:"nickname"`
+	AvatarURL       string              `json:"avatar_url"`
+	Content         string              `json:"content"`
+	MediaInfo       []*MediasBaseDTO    `json:"media_info"`
+	RootID          uint64              `json:"root_id"`
+	ParentID        uint64              `json:"parent_id"`
+	ReplyToUserID   uint64              `json:"reply_to_user_id"`
+	ReplyToNickname string              `json:"reply_to_nickname"`
+	LikesCount      int                 `json:"likes_count"`
+	IsLiked         bool                `json:"is_liked"`
+	IsPinned        bool                `json:"is_pinned"`
+	IsEdited        bool                `json:"is_edited"`
+	EditedAt        string              `json:"edited_at,omitempty"`
+	Reactions       *ReactionSummaryDTO `json:"reactions"`
+	CreatedAt       string              `json:"created_at"`
 
 	SubComments     []*CommentDTO `json:"sub_comments"`
 	SubCommentCount int64         `json:"sub_comment_count"`
 }
 
+// CommentQueryDTO 帖子评论列表查询
+type CommentQueryDTO struct {
+	Sort     string `form:"sort,default=hot" validate:"oneof=hot newest oldest"` // hot:热度 newest:最新 oldest:最早
+	Cursor   string `form:"cursor"`                                              // 上一页返回的 next_cursor，首页留空
+	PageSize int    `form:"page_size,default=20" validate:"min=1,max=50"`
+}
+
+// CommentListDTO 帖子评论列表，根评论内嵌热门回复预览
+type CommentListDTO struct {
+	List       []*CommentDTO `json:"list"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	HasMore    bool          `json:"has_more"`
+}
+
+// CommentSettingsUpdateDTO 作者修改笔记评论权限
+type CommentSettingsUpdateDTO struct {
+	CommentMode int8 `json:"comment_mode" validate:"oneof=0 1 2"` // 0:所有人 1:仅粉丝 2:关闭评论
+}
+
+// CommentSettingsDTO 笔记评论设置及作者置顶、隐藏的评论
+type CommentSettingsDTO struct {
+	CommentMode      int8     `json:"comment_mode"`
+	PinnedCommentIDs []uint64 `json:"pinned_comment_ids"`
+	HiddenCommentIDs []uint64 `json:"hidden_comment_ids"`
+}
+
 // PostActionStateDTO 帖子交互状态数据
 type PostActionStateDTO struct {
-	LikeCount    int64 `json:"like_count"`
-	CollectCount int64 `json:"collect_count"`
-	CommentCount int64 `json:"comment_count"`
-	ViewCount    int64 `json:"view_count"`
-	IsLiked      bool  `json:"is_liked"`
-	IsCollected  bool  `json:"is_collected"`
+	LikeCount    int64               `json:"like_count"`
+	CollectCount int64               `json:"collect_count"`
+	CommentCount int64               `json:"comment_count"`
+	ViewCount    int64               `json:"view_count"`
+	IsLiked      bool                `json:"is_liked"`
+	IsCollected  bool                `json:"is_collected"`
+	CommentMode  int8                `json:"comment_mode"` // 0:所有人可评论 1:仅粉丝 2:已关闭
+	Reactions    *ReactionSummaryDTO `json:"reactions"`
 }
 
 // PostBatchLikesReq 批量获取点赞数请求
@@ -46,8 +93,9 @@ type PostBatchLikesReq struct {
 
 // PostLikeStateDTO 批量获取点赞数响应
 type PostLikeStateDTO struct {
-	LikeCount int64 `json:"like_count"`
-	IsLiked   bool  `json:"is_liked"`
+	LikeCount int64               `json:"like_count"`
+	IsLiked   bool                `json:"is_liked"`
+	Reactions *ReactionSummaryDTO `json:"reactions"`
 }
 
 // PostActionReq 点赞/收藏通用请求
@@ -55,6 +103,19 @@ type PostActionReq struct {
 	Action int `json:"action" binding:"required,oneof=1 2"` // 1:执行, 2:取消
 }
 
+// ReactionReq 表情回应/取消回应请求
+type ReactionReq struct {
+	Action int    `json:"action" binding:"required,oneof=1 2"` // 1:回应, 2:取消
+	Emoji  string `json:"emoji" binding:"required_if=Action 1"`
+}
+
+// ReactionSummaryDTO 表情回应汇总，仅包含回应数大于 0 的表情
+type ReactionSummaryDTO struct {
+	Counts     map[string]int64 `json:"counts"`
+	Total      int64            `json:"total"`
+	MyReaction string           `json:"my_reaction,omitempty"`
+}
+
 // PostReport 举报帖子请求
 type PostReport struct {
 	Reason string `json:"reason" binding:"required"`
@@ -15,3 +15,21 @@ type PostTrendDTO struct {
 	Collects []*PostMetricDTO `json:"collects"`
 	Views    []*PostMetricDTO `json:"views"`
 }
+
+// ExperimentVariantMetricDTO 推荐实验分组指标
+type ExperimentVariantMetricDTO struct {
+	Variant    string  `json:"variant"`
+	Exposures  int64   `json:"exposures"`
+	Clicks     int64   `json:"clicks"`
+	Likes      int64   `json:"likes"`
+	CTR        float64 `json:"ctr"`          // 点击数 / 曝光数
+	LikeRate   float64 `json:"like_rate"`    // 点赞数 / 曝光数
+	AvgDwellMs float64 `json:"avg_dwell_ms"` // 平均停留时长
+}
+
+// ExperimentMetricDTO 推荐实验指标对比
+type ExperimentMetricDTO struct {
+	ExperimentID string                        `json:"experiment_id"`
+	Days         int                           `json:"days"`
+	Variants     []*ExperimentVariantMetricDTO `json:"variants"`
+}
@@ -0,0 +1,81 @@
+package dto
+
+import "time"
+
+// ReauditCreateDTO 创建复审任务
+type ReauditCreateDTO struct {
+	TargetType    int8       `json:"target_type" binding:"required" validate:"oneof=1 2"` // 1:笔记 2:评论
+	Tag           string     `json:"tag" validate:"max=50"`                               // 仅笔记有效
+	ContentStatus int8       `json:"content_status" validate:"omitempty,oneof=1 2 3"`     // 为空时复审通过与警告的内容
+	StartAt       *time.Time `json:"start_at"`
+	EndAt         *time.Time `json:"end_at"`
+	MaxItems      int        `json:"max_items" validate:"min=0,max=1000000"` // 0 表示不限
+	Concurrency   int        `json:"concurrency" validate:"omitempty,min=1,max=4"`
+	Remark        string     `json:"remark" validate:"max=200"`
+}
+
+// ReauditQueryDTO 复审任务列表查询
+type ReauditQueryDTO struct {
+	Page     int `form:"page,default=1" validate:"min=1"`
+	PageSize int `form:"page_size,default=20" validate:"min=1,max=100"`
+}
+
+// ReauditItemQueryDTO 复审变更明细查询
+type ReauditItemQueryDTO struct {
+	ApplyState *int8 `form:"apply_state" validate:"omitempty,oneof=0 1 2"`
+	Page       int   `form:"page,default=1" validate:"min=1"`
+	PageSize   int   `form:"page_size,default=20" validate:"min=1,max=100"`
+}
+
+// ReauditTransitionDTO 复审状态变化统计
+type ReauditTransitionDTO struct {
+	OldStatus int8  `json:"old_status"`
+	NewStatus int8  `json:"new_status"`
+	Count     int64 `json:"count"`
+}
+
+// ReauditCampaignDTO 复审任务
+type ReauditCampaignDTO struct {
+	ID            uint64                  `json:"id"`
+	TargetType    int8                    `json:"target_type"`
+	Tag           string                  `json:"tag"`
+	ContentStatus int8                    `json:"content_status"`
+	StartAt       *time.Time              `json:"start_at"`
+	EndAt         *time.Time              `json:"end_at"`
+	MaxItems      int                     `json:"max_items"`
+	Concurrency   int                     `json:"concurrency"`
+	State         int8                    `json:"state"` // 1:运行中 2:已暂停 3:已完成 4:应用中 5:已应用 6:失败
+	Scanned       int                     `json:"scanned"`
+	Changed       int                     `json:"changed"`
+	Failed        int                     `json:"failed"`
+	Applied       int                     `json:"applied"`
+	Remark        string                  `json:"remark"`
+	Error         string                  `json:"error,omitempty"`
+	CreatedBy     uint64                  `json:"created_by"`
+	CreatedAt     time.Time               `json:"created_at"`
+	FinishedAt    *time.Time              `json:"finished_at"`
+	Transitions   []*ReauditTransitionDTO `json:"transitions,omitempty"` // 预演差异汇总，仅详情返回
+}
+
+// ReauditCampaignListDTO 复审任务列表
+type ReauditCampaignListDTO struct {
+	List  []*ReauditCampaignDTO `json:"list"`
+	Total int64                 `json:"total"`
+}
+
+// ReauditItemDTO 复审状态变更明细
+type ReauditItemDTO struct {
+	ID         uint64    `json:"id"`
+	TargetID   uint64    `json:"target_id"`
+	OldStatus  int8      `json:"old_status"`
+	NewStatus  int8      `json:"new_status"`
+	Reason     string    `json:"reason"`
+	ApplyState int8      `json:"apply_state"` // 0:待应用 1:已应用 2:已跳过
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReauditItemListDTO 复审变更明细列表
+type ReauditItemListDTO struct {
+	List  []*ReauditItemDTO `json:"list"`
+	Total int64             `json:"total"`
+}
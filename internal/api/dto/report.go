@@ -0,0 +1,61 @@
+package dto
+
+// ReportCreateDTO 举报请求
+type ReportCreateDTO struct {
+	TargetType int8   `json:"target_type" binding:"required" validate:"oneof=1 2 3 4"` // 1:笔记 2:评论 3:用户 4:私信
+	TargetID   uint64 `json:"target_id" binding:"required"`                            // 私信为会话ID
+	TargetSeq  uint64 `json:"target_seq"`                                              // 私信序号，仅私信举报需要
+	Reason     int8   `json:"reason" binding:"required" validate:"oneof=1 2 3 4 5 6 7 99"`
+	Detail     string `json:"detail" validate:"max=500"`
+}
+
+// ReportQueueQueryDTO 举报工单队列查询
+type ReportQueueQueryDTO struct {
+	Status     *int8 `form:"status" validate:"omitempty,oneof=0 1 2 3"` // 为空时返回未结案工单
+	TargetType int8  `form:"target_type" validate:"omitempty,oneof=1 2 3 4"`
+	Mine       bool  `form:"mine"` // 仅看自己认领的
+	Page       int   `form:"page,default=1" validate:"min=1"`
+	PageSize   int   `form:"page_size,default=20" validate:"min=1,max=50"`
+}
+
+// ReportHandleDTO 工单处置请求
+type ReportHandleDTO struct {
+	Note      string   `json:"note" validate:"max=500"`
+	Takedown  bool     `json:"takedown"`                     // 处置时是否下架内容或封禁用户
+	MediaURLs []string `json:"media_urls" validate:"max=20"` // 下架时审核员指定加入指纹库的媒体，为空时按审核记录判定
+}
+
+// ReportCaseDTO 举报工单
+type ReportCaseDTO struct {
+	ID           uint64           `json:"id"`
+	TargetType   int8             `json:"target_type"`
+	TargetID     uint64           `json:"target_id"`
+	TargetSeq    uint64           `json:"target_seq"`
+	OwnerID      uint64           `json:"owner_id"`
+	Snapshot     string           `json:"snapshot"`
+	ReportCount  int              `json:"report_count"`
+	Priority     int              `json:"priority"`
+	Status       int8             `json:"status"` // 0:待处理 1:处理中 2:已处置 3:已驳回
+	ClaimedBy    uint64           `json:"claimed_by"`
+	ClaimedAt    string           `json:"claimed_at,omitempty"`
+	HandledBy    uint64           `json:"handled_by"`
+	HandleNote   string           `json:"handle_note"`
+	HandledAt    string           `json:"handled_at,omitempty"`
+	CreatedAt    string           `json:"created_at"`
+	ReasonCounts map[int8]int     `json:"reason_counts,omitempty"`
+	Reports      []*ReportItemDTO `json:"reports,omitempty"`
+}
+
+// ReportItemDTO 单条举报明细
+type ReportItemDTO struct {
+	ReporterID uint64 `json:"reporter_id"`
+	Reason     int8   `json:"reason"`
+	Detail     string `json:"detail"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// ReportCaseListDTO 举报工单列表
+type ReportCaseListDTO struct {
+	List  []*ReportCaseDTO `json:"list"`
+	Total int64            `json:"total"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+// ReindexProgressDTO 索引重建进度
+type ReindexProgressDTO struct {
+	Target     string  `json:"target"`
+	Alias      string  `json:"alias"`
+	OldIndex   string  `json:"old_index"`
+	NewIndex   string  `json:"new_index"`
+	Status     string  `json:"status"` // running, succeeded, failed
+	Reembed    bool    `json:"reembed"`
+	Total      int64   `json:"total"`
+	Processed  int64   `json:"processed"`
+	Indexed    int64   `json:"indexed"`
+	Skipped    int64   `json:"skipped"`
+	Reembedded int64   `json:"reembedded"`
+	Failed     int64   `json:"failed"`
+	Percent    float64 `json:"percent"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt string  `json:"finished_at,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// ReconcileReportDTO 数据库与索引对账报告
+type ReconcileReportDTO struct {
+	Status     string              `json:"status"` // running, succeeded, failed
+	Posts      *ReconcileTargetDTO `json:"posts"`
+	Users      *ReconcileTargetDTO `json:"users"`
+	StartedAt  string              `json:"started_at"`
+	FinishedAt string              `json:"finished_at,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// ReconcileTargetDTO 单个索引的对账结果
+type ReconcileTargetDTO struct {
+	Scanned  int64                `json:"scanned"`
+	Drifted  int64                `json:"drifted"`
+	Repaired int64                `json:"repaired"`
+	Skipped  int64                `json:"skipped"`
+	Failed   int64                `json:"failed"`
+	Kinds    map[string]int64     `json:"kinds"` // 按差异类型计数
+	Samples  []*ReconcileDriftDTO `json:"samples"`
+}
+
+// ReconcileDriftDTO 单条差异
+type ReconcileDriftDTO struct {
+	ID     uint64   `json:"id"`
+	Kinds  []string `json:"kinds"`  // missing, orphan, status, counts, nickname, avatar, content, updated_at, pending
+	Action string   `json:"action"` // index, delete, skip, redrive
+	Error  string   `json:"error,omitempty"`
+}
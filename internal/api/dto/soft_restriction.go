@@ -0,0 +1,33 @@
+package dto
+
+// SoftRestrictionCreateDTO 影子封禁用户或限流笔记
+type SoftRestrictionCreateDTO struct {
+	ExpiresIn int    `json:"expires_in" binding:"required" validate:"min=1,max=8760"` // 有效时长（小时），重复处置时覆盖原到期时间
+	Reason    string `json:"reason" validate:"max=200"`
+}
+
+// SoftRestrictionQueryDTO 软处置记录查询
+type SoftRestrictionQueryDTO struct {
+	Kind     int8 `form:"kind" validate:"omitempty,oneof=1 2"` // 1:影子封禁用户 2:限流笔记
+	Active   bool `form:"active"`                              // 仅查询生效中的处置
+	Page     int  `form:"page,default=1" validate:"min=1"`
+	PageSize int  `form:"page_size,default=20" validate:"min=1,max=100"`
+}
+
+// SoftRestrictionDTO 软处置记录
+type SoftRestrictionDTO struct {
+	ID        uint64  `json:"id"`
+	Kind      int8    `json:"kind"`
+	TargetID  uint64  `json:"target_id"`
+	Reason    string  `json:"reason"`
+	CreatedBy uint64  `json:"created_by"`
+	ExpiresAt string  `json:"expires_at"`
+	LiftedAt  *string `json:"lifted_at"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// SoftRestrictionListDTO 软处置记录列表
+type SoftRestrictionListDTO struct {
+	List  []*SoftRestrictionDTO `json:"list"`
+	Total int64                 `json:"total"`
+}
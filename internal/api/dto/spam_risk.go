@@ -0,0 +1,32 @@
+package dto
+
+// SpamRiskQueryDTO 垃圾行为复核列表查询
+type SpamRiskQueryDTO struct {
+	State    int8 `form:"state" validate:"omitempty,oneof=1 2 3"` // 1:待复核 2:误判已放行 3:确认垃圾行为
+	MinScore int  `form:"min_score" validate:"min=0,max=100"`
+	Page     int  `form:"page,default=1" validate:"min=1"`
+	PageSize int  `form:"page_size,default=20" validate:"min=1,max=100"`
+}
+
+// SpamRiskDTO 垃圾行为风险记录
+type SpamRiskDTO struct {
+	UserID            uint64  `json:"user_id"`
+	Score             int     `json:"score"`
+	PeakScore         int     `json:"peak_score"`
+	BurstLikes        int     `json:"burst_likes"`
+	FollowChurn       int     `json:"follow_churn"`
+	DuplicateComments int     `json:"duplicate_comments"`
+	NewAccountLinks   int     `json:"new_account_links"`
+	ThrottleLevel     int8    `json:"throttle_level"`
+	State             int8    `json:"state"`
+	ReviewedBy        uint64  `json:"reviewed_by"`
+	ReviewedAt        *string `json:"reviewed_at"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
+
+// SpamRiskListDTO 垃圾行为复核列表
+type SpamRiskListDTO struct {
+	List  []*SpamRiskDTO `json:"list"`
+	Total int64          `json:"total"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// UserStrikeDTO 违规记录
+type UserStrikeDTO struct {
+	ID        uint64    `json:"id"`
+	Source    int8      `json:"source"`
+	TargetID  uint64    `json:"target_id"`
+	Reason    string    `json:"reason"`
+	Revoked   bool      `json:"revoked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserPenaltyDTO 当前生效的处罚
+type UserPenaltyDTO struct {
+	Level     int8       `json:"level"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// UserStrikeSummaryDTO 用户违规概览
+type UserStrikeSummaryDTO struct {
+	ActiveCount int64            `json:"active_count"` // 统计窗口内未撤销的违规次数
+	Penalty     *UserPenaltyDTO  `json:"penalty"`
+	Strikes     []*UserStrikeDTO `json:"strikes"`
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AppealHandler struct {
+	appealSvc service.AppealService
+}
+
+func NewAppealHandler(appealSvc service.AppealService) *AppealHandler {
+	return &AppealHandler{
+		appealSvc: appealSvc,
+	}
+}
+
+// CreateAppeal 对被拒绝的笔记或评论发起申诉
+func (h *AppealHandler) CreateAppeal(c *gin.Context) {
+	var req dto.AppealCreateDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.appealSvc.CreateAppeal(c.Request.Context(), c.GetUint64("user_id"), &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetMyAppeals 获取自己的申诉记录
+func (h *AppealHandler) GetMyAppeals(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 50 {
+		pageSize = 20
+	}
+
+	res, err := h.appealSvc.GetMyAppeals(c.Request.Context(), c.GetUint64("user_id"), page, pageSize)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// GetAppealQueue 审核端获取申诉队列
+func (h *AppealHandler) GetAppealQueue(c *gin.Context) {
+	var req dto.AppealQueueQueryDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.appealSvc.GetAppealQueue(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// ApproveAppeal 申诉通过
+func (h *AppealHandler) ApproveAppeal(c *gin.Context) {
+	appealID, req, ok := bindAppealHandle(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appealSvc.ApproveAppeal(c.Request.Context(), c.GetUint64("user_id"), appealID, req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// RejectAppeal 驳回申诉
+func (h *AppealHandler) RejectAppeal(c *gin.Context) {
+	appealID, req, ok := bindAppealHandle(c)
+	if !ok {
+		return
+	}
+
+	if err := h.appealSvc.RejectAppeal(c.Request.Context(), c.GetUint64("user_id"), appealID, req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func bindAppealHandle(c *gin.Context) (uint64, *dto.AppealHandleDTO, bool) {
+	appealID, err := strconv.ParseUint(c.Param("appeal_id"), 10, 64)
+	if err != nil || appealID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return 0, nil, false
+	}
+	var req dto.AppealHandleDTO
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return 0, nil, false
+	}
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return 0, nil, false
+	}
+	return appealID, &req, true
+}
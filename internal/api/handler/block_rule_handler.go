@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlockRuleHandler struct {
+	blockRuleSvc service.BlockRuleService
+}
+
+func NewBlockRuleHandler(blockRuleSvc service.BlockRuleService) *BlockRuleHandler {
+	return &BlockRuleHandler{
+		blockRuleSvc: blockRuleSvc,
+	}
+}
+
+// GetBlockRules 管理端获取屏蔽规则列表
+func (h *BlockRuleHandler) GetBlockRules(c *gin.Context) {
+	var req dto.BlockRuleQueryDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.blockRuleSvc.GetBlockRules(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// CreateBlockRule 新增屏蔽规则
+func (h *BlockRuleHandler) CreateBlockRule(c *gin.Context) {
+	var req dto.BlockRuleSaveDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.blockRuleSvc.CreateBlockRule(c.Request.Context(), c.GetUint64("user_id"), &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// UpdateBlockRule 修改屏蔽规则
+func (h *BlockRuleHandler) UpdateBlockRule(c *gin.Context) {
+	ruleID, err := strconv.ParseUint(c.Param("rule_id"), 10, 64)
+	if err != nil || ruleID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.BlockRuleSaveDTO
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err = h.blockRuleSvc.UpdateBlockRule(c.Request.Context(), ruleID, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// DeleteBlockRule 删除屏蔽规则
+func (h *BlockRuleHandler) DeleteBlockRule(c *gin.Context) {
+	ruleID, err := strconv.ParseUint(c.Param("rule_id"), 10, 64)
+	if err != nil || ruleID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = h.blockRuleSvc.DeleteBlockRule(c.Request.Context(), ruleID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
@@ -55,6 +55,25 @@ func (s *IMHandler) MarkAsRead(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// ReactMessage 消息表情回应接口
+func (s *IMHandler) ReactMessage(c *gin.Context) {
+	var req dto.MessageReactionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	userID := c.GetUint64("user_id")
+
+	err := s.imService.ReactToMessage(c, userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil)
+}
+
 // GetChatHistory 获取历史消息
 func (s *IMHandler) GetChatHistory(c *gin.Context) {
 	var err error
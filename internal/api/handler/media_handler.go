@@ -18,10 +18,14 @@ import (
 	"github.com/google/uuid"
 )
 
-type MediaHandler struct{}
+type MediaHandler struct {
+	mediaHashSvc service.MediaHashService
+}
 
-func NewMediaHandler() *MediaHandler {
-	return &MediaHandler{}
+func NewMediaHandler(mediaHashSvc service.MediaHashService) *MediaHandler {
+	return &MediaHandler{
+		mediaHashSvc: mediaHashSvc,
+	}
 }
 
 func (s *MediaHandler) Upload(c *gin.Context) {
@@ -39,7 +43,11 @@ func (s *MediaHandler) Upload(c *gin.Context) {
 	defer func() { _ = reader.Close() }()
 
 	contentType, err := util.GetSafeContentType(reader)
-	log.InfoContext(c.Request.Context(), "contentType", contentType)
+	if err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	log.InfoContext(c.Request.Context(), "media content type detected", "contentType", contentType)
 
 	isImage := strings.HasPrefix(contentType, consts.MimePrefixImage)
 	isVideo := strings.HasPrefix(contentType, consts.MimePrefixVideo)
@@ -49,6 +57,16 @@ func (s *MediaHandler) Upload(c *gin.Context) {
 		return
 	}
 
+	// 图片先匹配违规指纹库，已知违规图片直接拒绝上传
+	var imageHash *util.ImageHash
+	if isImage {
+		imageHash, err = s.mediaHashSvc.CheckUpload(c.Request.Context(), reader)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+	}
+
 	ext := path.Ext(file.Filename)
 	objectName := time.Now().Format("2006/01/02/") + uuid.NewString() + ext
 
@@ -59,6 +77,8 @@ func (s *MediaHandler) Upload(c *gin.Context) {
 		return
 	}
 
+	s.mediaHashSvc.SaveUploadHash(c.Request.Context(), fileKey, imageHash)
+
 	publicUrl := minio.GetPublicURL(fileKey)
 	var width, height int
 	var duration float64
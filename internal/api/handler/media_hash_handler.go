@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHashHandler struct {
+	mediaHashSvc service.MediaHashService
+}
+
+func NewMediaHashHandler(mediaHashSvc service.MediaHashService) *MediaHashHandler {
+	return &MediaHashHandler{
+		mediaHashSvc: mediaHashSvc,
+	}
+}
+
+// GetHashBlocks 审核端获取违规图片指纹库
+func (h *MediaHashHandler) GetHashBlocks(c *gin.Context) {
+	var req dto.MediaHashBlockQueryDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.mediaHashSvc.GetHashBlocks(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// AddHashBlock 将图片或视频加入违规图片指纹库
+func (h *MediaHashHandler) AddHashBlock(c *gin.Context) {
+	var req dto.MediaHashBlockCreateDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.mediaHashSvc.AddHashBlock(c.Request.Context(), c.GetUint64("user_id"), &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// DeleteHashBlock 将指纹移出违规图片指纹库
+func (h *MediaHashHandler) DeleteHashBlock(c *gin.Context) {
+	blockID, err := strconv.ParseUint(c.Param("hash_id"), 10, 64)
+	if err != nil || blockID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = h.mediaHashSvc.DeleteHashBlock(c.Request.Context(), blockID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
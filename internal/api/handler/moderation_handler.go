@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	moderationSvc service.ModerationService
+}
+
+func NewModerationHandler(moderationSvc service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationSvc: moderationSvc,
+	}
+}
+
+// GetVerdicts 审核端查看笔记或评论的模型审核记录
+func (h *ModerationHandler) GetVerdicts(c *gin.Context) {
+	var req dto.ModerationVerdictQueryDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.moderationSvc.GetVerdicts(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
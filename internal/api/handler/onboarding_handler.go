@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OnboardingHandler struct {
+	onboardingSvc service.OnboardingService
+}
+
+func NewOnboardingHandler(onboardingSvc service.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboardingSvc: onboardingSvc,
+	}
+}
+
+// GetOnboardingTags 获取兴趣引导标签
+func (h *OnboardingHandler) GetOnboardingTags(c *gin.Context) {
+	tags, err := h.onboardingSvc.GetOnboardingTags(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, tags)
+}
+
+// GetOnboardingStatus 获取当前用户是否需要兴趣引导
+func (h *OnboardingHandler) GetOnboardingStatus(c *gin.Context) {
+	userID := c.GetUint64("user_id")
+	status, err := h.onboardingSvc.GetOnboardingStatus(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, status)
+}
+
+// SubmitInterests 提交兴趣选择
+func (h *OnboardingHandler) SubmitInterests(c *gin.Context) {
+	userID := c.GetUint64("user_id")
+
+	var req dto.OnboardingInterestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.onboardingSvc.SubmitInterests(c.Request.Context(), userID, req.TagIDs); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetOnboardingTagsAdmin 管理端获取引导标签列表
+func (h *OnboardingHandler) GetOnboardingTagsAdmin(c *gin.Context) {
+	tags, err := h.onboardingSvc.GetOnboardingTagsAdmin(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, tags)
+}
+
+// SaveOnboardingTag 管理端新增或修改引导标签
+func (h *OnboardingHandler) SaveOnboardingTag(c *gin.Context) {
+	var req dto.OnboardingTagSaveDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.onboardingSvc.SaveOnboardingTag(c.Request.Context(), &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// DeleteOnboardingTag 管理端移除引导标签
+func (h *OnboardingHandler) DeleteOnboardingTag(c *gin.Context) {
+	tagID, err := strconv.ParseUint(c.Param("tag_id"), 10, 64)
+	if err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = h.onboardingSvc.DeleteOnboardingTag(c.Request.Context(), tagID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
 	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
 	"Cornerstone/internal/service"
 	"strconv"
 
@@ -11,14 +13,16 @@ import (
 )
 
 type PostActionHandler struct {
-	postSvc   service.PostService
-	actionSvc service.PostActionService
+	postSvc     service.PostService
+	actionSvc   service.PostActionService
+	reactionSvc service.ReactionService
 }
 
-func NewPostActionHandler(postSvc service.PostService, actionSvc service.PostActionService) *PostActionHandler {
+func NewPostActionHandler(postSvc service.PostService, actionSvc service.PostActionService, reactionSvc service.ReactionService) *PostActionHandler {
 	return &PostActionHandler{
-		postSvc:   postSvc,
-		actionSvc: actionSvc,
+		postSvc:     postSvc,
+		actionSvc:   actionSvc,
+		reactionSvc: reactionSvc,
 	}
 }
 
@@ -76,6 +80,53 @@ func (s *PostActionHandler) CollectPost(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// ReactPost 表情回应/取消回应帖子
+func (s *PostActionHandler) ReactPost(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil || postID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	s.react(c, model.ReactionTargetPost, postID)
+}
+
+// ReactComment 表情回应/取消回应评论
+func (s *PostActionHandler) ReactComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil || commentID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	s.react(c, model.ReactionTargetComment, commentID)
+}
+
+// GetReactionSet 获取可用的表情集合
+func (s *PostActionHandler) GetReactionSet(c *gin.Context) {
+	response.Success(c, s.reactionSvc.GetReactionSet())
+}
+
+func (s *PostActionHandler) react(c *gin.Context, targetType int8, targetID uint64) {
+	userID := c.GetUint64("user_id")
+	var req dto.ReactionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	var err error
+	if req.Action == 1 {
+		err = s.reactionSvc.React(c.Request.Context(), userID, targetType, targetID, req.Emoji)
+	} else {
+		err = s.reactionSvc.Unreact(c.Request.Context(), userID, targetType, targetID)
+	}
+
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
 // GetPostActionState 获取帖子详情页的全量交互状态并选择性上报浏览
 func (s *PostActionHandler) GetPostActionState(c *gin.Context) {
 	userID := c.GetUint64("user_id")
@@ -116,6 +167,14 @@ func (s *PostActionHandler) GetPostActionState(c *gin.Context) {
 		state.ViewCount, err = s.actionSvc.GetPostViewCount(gCtx, req.PostID)
 		return err
 	})
+	g.Go(func() error {
+		state.CommentMode, err = s.actionSvc.GetCommentMode(gCtx, req.PostID)
+		return err
+	})
+	g.Go(func() error {
+		state.Reactions = s.reactionSvc.GetReactionSummaries(gCtx, userID, model.ReactionTargetPost, []uint64{req.PostID})[req.PostID]
+		return nil
+	})
 
 	if userID > 0 {
 		g.Go(func() error {
@@ -188,6 +247,43 @@ func (s *PostActionHandler) DeleteComment(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// EditComment 编辑评论
+func (s *PostActionHandler) EditComment(c *gin.Context) {
+	userID := c.GetUint64("user_id")
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil || commentID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.CommentEditDTO
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = s.actionSvc.EditComment(c.Request.Context(), userID, commentID, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetCommentEdits 获取评论的编辑历史
+func (s *PostActionHandler) GetCommentEdits(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil || commentID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	edits, err := s.actionSvc.GetCommentEdits(c.Request.Context(), commentID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, edits)
+}
+
 // LikeComment 点赞/取消点赞评论
 func (s *PostActionHandler) LikeComment(c *gin.Context) {
 	userID := c.GetUint64("user_id")
@@ -215,6 +311,100 @@ func (s *PostActionHandler) LikeComment(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// PinComment 作者置顶/取消置顶评论
+func (s *PostActionHandler) PinComment(c *gin.Context) {
+	userID := c.GetUint64("user_id")
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil || commentID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.PostActionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if req.Action == 1 {
+		err = s.actionSvc.PinComment(c.Request.Context(), userID, commentID)
+	} else {
+		err = s.actionSvc.UnpinComment(c.Request.Context(), userID, commentID)
+	}
+
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// HideComment 作者隐藏/取消隐藏评论
+func (s *PostActionHandler) HideComment(c *gin.Context) {
+	userID := c.GetUint64("user_id")
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil || commentID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.PostActionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if req.Action == 1 {
+		err = s.actionSvc.HideComment(c.Request.Context(), userID, commentID)
+	} else {
+		err = s.actionSvc.UnhideComment(c.Request.Context(), userID, commentID)
+	}
+
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetCommentSettings 作者查看笔记的评论设置
+func (s *PostActionHandler) GetCommentSettings(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil || postID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	res, err := s.actionSvc.GetCommentSettings(c.Request.Context(), c.GetUint64("user_id"), postID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// UpdateCommentSettings 作者修改笔记的评论权限
+func (s *PostActionHandler) UpdateCommentSettings(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil || postID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.CommentSettingsUpdateDTO
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err = s.actionSvc.UpdateCommentSettings(c.Request.Context(), c.GetUint64("user_id"), postID, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
 // GetComments 获取帖子的评论列表
 func (s *PostActionHandler) GetComments(c *gin.Context) {
 	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
@@ -222,16 +412,17 @@ func (s *PostActionHandler) GetComments(c *gin.Context) {
 		response.Error(c, service.ErrParamInvalid)
 		return
 	}
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil {
-		page = 1
+	var req dto.CommentQueryDTO
+	if err = c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
 	}
-	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	if err != nil {
-		pageSize = 20
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
 	}
 
-	comments, err := s.actionSvc.GetCommentsByPostID(c.Request.Context(), postID, page, pageSize)
+	comments, err := s.actionSvc.GetCommentsByPostID(c.Request.Context(), postID, &req)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -310,21 +501,3 @@ func (s *PostActionHandler) GetUserCollections(c *gin.Context) {
 	}
 	response.Success(c, posts)
 }
-
-func (s *PostActionHandler) ReportPost(c *gin.Context) {
-	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
-	if err != nil || postID == 0 {
-		response.Error(c, service.ErrParamInvalid)
-		return
-	}
-	var req dto.PostReport
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.Error(c, service.ErrParamInvalid)
-		return
-	}
-	if err := s.actionSvc.ReportPost(c.Request.Context(), c.GetUint64("user_id"), postID); err != nil {
-		response.Error(c, err)
-		return
-	}
-	response.Success(c, nil)
-}
@@ -2,9 +2,12 @@ package handler
 
 import (
 	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
 	"Cornerstone/internal/pkg/response"
 	"Cornerstone/internal/pkg/util"
 	"Cornerstone/internal/service"
+	log "log/slog"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -12,12 +15,16 @@ import (
 )
 
 type PostHandler struct {
-	postSvc service.PostService
+	postSvc      service.PostService
+	mediaHashSvc service.MediaHashService
+	strikeSvc    service.StrikeService
 }
 
-func NewPostHandler(postSvc service.PostService) *PostHandler {
+func NewPostHandler(postSvc service.PostService, mediaHashSvc service.MediaHashService, strikeSvc service.StrikeService) *PostHandler {
 	return &PostHandler{
-		postSvc: postSvc,
+		postSvc:      postSvc,
+		mediaHashSvc: mediaHashSvc,
+		strikeSvc:    strikeSvc,
 	}
 }
 
@@ -43,14 +50,18 @@ func (s *PostHandler) RecommendPost(c *gin.Context) {
 }
 
 func (s *PostHandler) SearchPost(c *gin.Context) {
-	var searchDTO dto.PostListDTO
+	var searchDTO dto.PostSearchDTO
 
 	if err := c.ShouldBindQuery(&searchDTO); err != nil {
 		response.Error(c, err)
 		return
 	}
+	if err := util.ValidateDTO(&searchDTO); err != nil {
+		response.Error(c, err)
+		return
+	}
 
-	posts, err := s.postSvc.SearchPost(c.Request.Context(), searchDTO.Keyword, searchDTO.Page, searchDTO.PageSize)
+	posts, err := s.postSvc.SearchPost(c.Request.Context(), &searchDTO)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -60,9 +71,10 @@ func (s *PostHandler) SearchPost(c *gin.Context) {
 }
 
 func (s *PostHandler) Suggestion(c *gin.Context) {
+	userID := c.GetUint64("user_id")
 	keyword := c.Query("keyword")
 
-	suggestions, err := s.postSvc.Suggestion(c.Request.Context(), keyword)
+	suggestions, err := s.postSvc.Suggestion(c.Request.Context(), userID, keyword)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -71,6 +83,117 @@ func (s *PostHandler) Suggestion(c *gin.Context) {
 	response.Success(c, suggestions)
 }
 
+// GetSearchHistory 获取搜索历史
+func (s *PostHandler) GetSearchHistory(c *gin.Context) {
+	userID := c.GetUint64("user_id")
+
+	history, err := s.postSvc.GetSearchHistory(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, history)
+}
+
+// DeleteSearchHistory 删除搜索历史，不传关键词时清空
+func (s *PostHandler) DeleteSearchHistory(c *gin.Context) {
+	userID := c.GetUint64("user_id")
+
+	if err := s.postSvc.DeleteSearchHistory(c.Request.Context(), userID, c.Query("keyword")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetHotSearches 获取热搜榜
+func (s *PostHandler) GetHotSearches(c *gin.Context) {
+	hot, err := s.postSvc.GetHotSearches(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, hot)
+}
+
+// GetHotSearchesAdmin 管理端获取热搜概览
+func (s *PostHandler) GetHotSearchesAdmin(c *gin.Context) {
+	res, err := s.postSvc.GetHotSearchesAdmin(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// PinHotSearch 管理端置顶热搜词
+func (s *PostHandler) PinHotSearch(c *gin.Context) {
+	var req dto.HotSearchPinDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := s.postSvc.PinHotSearch(c.Request.Context(), req.Keyword, req.Position); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// UnpinHotSearch 管理端取消置顶
+func (s *PostHandler) UnpinHotSearch(c *gin.Context) {
+	var req dto.HotSearchKeywordDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err := s.postSvc.UnpinHotSearch(c.Request.Context(), req.Keyword); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// SuppressHotSearch 管理端屏蔽热搜词
+func (s *PostHandler) SuppressHotSearch(c *gin.Context) {
+	var req dto.HotSearchKeywordDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := s.postSvc.SuppressHotSearch(c.Request.Context(), req.Keyword); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// UnsuppressHotSearch 管理端解除屏蔽
+func (s *PostHandler) UnsuppressHotSearch(c *gin.Context) {
+	var req dto.HotSearchKeywordDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err := s.postSvc.UnsuppressHotSearch(c.Request.Context(), req.Keyword); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
 func (s *PostHandler) SearchPostMe(c *gin.Context) {
 	userID := c.GetUint64("user_id")
 	var searchDTO dto.PostListDTO
@@ -321,5 +444,49 @@ func (s *PostHandler) UpdatePostStatus(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	// 审核员确认拒绝，笔记图片加入违规指纹库并为作者记录违规
+	if req.Status == consts.PostStatusDeny {
+		s.mediaHashSvc.BlockConfirmedMedia(c.Request.Context(), c.GetUint64("user_id"), model.ModerationTargetPost, uint64(postID), req.MediaURLs)
+		if err := s.strikeSvc.StrikeDeniedPost(c.Request.Context(), uint64(postID)); err != nil {
+			log.ErrorContext(c.Request.Context(), "add post strike failed", "postId", postID, "err", err)
+		}
+	}
 	response.Success(c, nil)
 }
+
+// ReportRecommendDwell 上报推荐帖子停留时长
+func (s *PostHandler) ReportRecommendDwell(c *gin.Context) {
+	userID := c.GetUint64("user_id")
+
+	var req dto.RecommendDwellDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := s.postSvc.ReportRecommendDwell(c.Request.Context(), userID, req.PostID, req.DwellMs); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// DebugRecommendScore 复现用户与帖子的推荐打分
+func (s *PostHandler) DebugRecommendScore(c *gin.Context) {
+	var req dto.RecommendDebugReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	res, err := s.postSvc.DebugRecommendScore(c.Request.Context(), req.UserID, req.PostID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
@@ -53,3 +53,20 @@ func (h *PostMetricHandler) GetMetrics30Days(c *gin.Context) {
 	}
 	response.Success(c, metricData)
 }
+
+// GetExperimentMetrics 获取推荐实验分组指标对比
+func (h *PostMetricHandler) GetExperimentMetrics(c *gin.Context) {
+	experimentID := c.Param("experiment_id")
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	metricData, err := h.postMetricSvc.GetExperimentMetrics(c.Request.Context(), experimentID, days)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, metricData)
+}
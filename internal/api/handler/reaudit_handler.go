@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReauditHandler struct {
+	reauditSvc service.ReauditService
+}
+
+func NewReauditHandler(reauditSvc service.ReauditService) *ReauditHandler {
+	return &ReauditHandler{
+		reauditSvc: reauditSvc,
+	}
+}
+
+// CreateCampaign 创建复审任务，先预演生成状态差异
+func (h *ReauditHandler) CreateCampaign(c *gin.Context) {
+	var req dto.ReauditCreateDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.reauditSvc.CreateCampaign(c.Request.Context(), c.GetUint64("user_id"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// GetCampaigns 获取复审任务列表
+func (h *ReauditHandler) GetCampaigns(c *gin.Context) {
+	var req dto.ReauditQueryDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.reauditSvc.GetCampaigns(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// GetCampaign 获取复审任务进度与预演差异汇总
+func (h *ReauditHandler) GetCampaign(c *gin.Context) {
+	campaignID, err := strconv.ParseUint(c.Param("campaign_id"), 10, 64)
+	if err != nil || campaignID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	res, err := h.reauditSvc.GetCampaign(c.Request.Context(), campaignID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// GetCampaignItems 获取复审状态变更明细
+func (h *ReauditHandler) GetCampaignItems(c *gin.Context) {
+	campaignID, err := strconv.ParseUint(c.Param("campaign_id"), 10, 64)
+	if err != nil || campaignID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.ReauditItemQueryDTO
+	if err = c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.reauditSvc.GetCampaignItems(c.Request.Context(), campaignID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// PauseCampaign 暂停复审任务
+func (h *ReauditHandler) PauseCampaign(c *gin.Context) {
+	h.handleCampaign(c, h.reauditSvc.PauseCampaign)
+}
+
+// ResumeCampaign 继续复审任务
+func (h *ReauditHandler) ResumeCampaign(c *gin.Context) {
+	h.handleCampaign(c, h.reauditSvc.ResumeCampaign)
+}
+
+// ApplyCampaign 确认并应用预演出的状态变更
+func (h *ReauditHandler) ApplyCampaign(c *gin.Context) {
+	h.handleCampaign(c, h.reauditSvc.ApplyCampaign)
+}
+
+func (h *ReauditHandler) handleCampaign(c *gin.Context, fn func(ctx context.Context, id uint64) error) {
+	campaignID, err := strconv.ParseUint(c.Param("campaign_id"), 10, 64)
+	if err != nil || campaignID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = fn(c.Request.Context(), campaignID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportSvc service.ReportService
+}
+
+func NewReportHandler(reportSvc service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportSvc: reportSvc,
+	}
+}
+
+// CreateReport 举报笔记、评论、用户或私信
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	var req dto.ReportCreateDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.reportSvc.CreateReport(c.Request.Context(), c.GetUint64("user_id"), &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// ReportPost 兼容旧版笔记举报接口，原因文本记为其他
+func (h *ReportHandler) ReportPost(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil || postID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.PostReport
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	err = h.reportSvc.CreateReport(c.Request.Context(), c.GetUint64("user_id"), &dto.ReportCreateDTO{
+		TargetType: model.ReportTargetPost,
+		TargetID:   postID,
+		Reason:     model.ReportReasonOther,
+		Detail:     util.TruncateRunes(req.Reason, 450),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetReportQueue 审核端获取举报工单队列
+func (h *ReportHandler) GetReportQueue(c *gin.Context) {
+	var req dto.ReportQueueQueryDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.reportSvc.GetReportQueue(c.Request.Context(), c.GetUint64("user_id"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// GetReportCase 审核端获取工单详情
+func (h *ReportHandler) GetReportCase(c *gin.Context) {
+	caseID, err := strconv.ParseUint(c.Param("case_id"), 10, 64)
+	if err != nil || caseID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	res, err := h.reportSvc.GetReportCase(c.Request.Context(), caseID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// ClaimReportCase 认领工单
+func (h *ReportHandler) ClaimReportCase(c *gin.Context) {
+	caseID, err := strconv.ParseUint(c.Param("case_id"), 10, 64)
+	if err != nil || caseID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = h.reportSvc.ClaimReportCase(c.Request.Context(), c.GetUint64("user_id"), caseID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// ResolveReportCase 举报成立
+func (h *ReportHandler) ResolveReportCase(c *gin.Context) {
+	caseID, req, ok := bindReportHandle(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reportSvc.ResolveReportCase(c.Request.Context(), c.GetUint64("user_id"), caseID, req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// DismissReportCase 驳回举报
+func (h *ReportHandler) DismissReportCase(c *gin.Context) {
+	caseID, req, ok := bindReportHandle(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reportSvc.DismissReportCase(c.Request.Context(), c.GetUint64("user_id"), caseID, req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+func bindReportHandle(c *gin.Context) (uint64, *dto.ReportHandleDTO, bool) {
+	caseID, err := strconv.ParseUint(c.Param("case_id"), 10, 64)
+	if err != nil || caseID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return 0, nil, false
+	}
+	var req dto.ReportHandleDTO
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return 0, nil, false
+	}
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return 0, nil, false
+	}
+	return caseID, &req, true
+}
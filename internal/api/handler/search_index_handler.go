@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchIndexHandler struct {
+	searchIndexSvc service.SearchIndexService
+}
+
+func NewSearchIndexHandler(searchIndexSvc service.SearchIndexService) *SearchIndexHandler {
+	return &SearchIndexHandler{
+		searchIndexSvc: searchIndexSvc,
+	}
+}
+
+// StartReindex 发起索引重建（posts / users），后台回填后切换别名
+func (h *SearchIndexHandler) StartReindex(c *gin.Context) {
+	progress, err := h.searchIndexSvc.StartReindex(c.Request.Context(), c.Param("target"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, progress)
+}
+
+// GetReindexProgress 查询最近一次索引重建进度
+func (h *SearchIndexHandler) GetReindexProgress(c *gin.Context) {
+	progress, err := h.searchIndexSvc.GetReindexProgress(c.Request.Context(), c.Param("target"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, progress)
+}
+
+// StartReconcile 手动发起 MySQL 与 ES 对账
+func (h *SearchIndexHandler) StartReconcile(c *gin.Context) {
+	report, err := h.searchIndexSvc.StartReconcile(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, report)
+}
+
+// GetReconcileReport 查询最近一次对账报告
+func (h *SearchIndexHandler) GetReconcileReport(c *gin.Context) {
+	report, err := h.searchIndexSvc.GetReconcileReport(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, report)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SoftRestrictionHandler struct {
+	softRestrictionSvc service.SoftRestrictionService
+}
+
+func NewSoftRestrictionHandler(softRestrictionSvc service.SoftRestrictionService) *SoftRestrictionHandler {
+	return &SoftRestrictionHandler{
+		softRestrictionSvc: softRestrictionSvc,
+	}
+}
+
+// GetRestrictions 审核端查看影子封禁与限流记录
+func (h *SoftRestrictionHandler) GetRestrictions(c *gin.Context) {
+	var req dto.SoftRestrictionQueryDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.softRestrictionSvc.GetRestrictions(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// ShadowBanUser 影子封禁用户
+func (h *SoftRestrictionHandler) ShadowBanUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil || userID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.SoftRestrictionCreateDTO
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err = h.softRestrictionSvc.ShadowBanUser(c.Request.Context(), c.GetUint64("user_id"), userID, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// LiftShadowBan 提前解除影子封禁
+func (h *SoftRestrictionHandler) LiftShadowBan(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil || userID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = h.softRestrictionSvc.LiftShadowBan(c.Request.Context(), userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// LimitPost 限流笔记
+func (h *SoftRestrictionHandler) LimitPost(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil || postID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.SoftRestrictionCreateDTO
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err = h.softRestrictionSvc.LimitPost(c.Request.Context(), c.GetUint64("user_id"), postID, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// LiftPostLimit 提前解除笔记限流
+func (h *SoftRestrictionHandler) LiftPostLimit(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 64)
+	if err != nil || postID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = h.softRestrictionSvc.LiftPostLimit(c.Request.Context(), postID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
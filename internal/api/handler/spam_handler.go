@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SpamHandler struct {
+	spamSvc service.SpamService
+}
+
+func NewSpamHandler(spamSvc service.SpamService) *SpamHandler {
+	return &SpamHandler{
+		spamSvc: spamSvc,
+	}
+}
+
+// GetRisks 审核端获取垃圾行为复核列表
+func (h *SpamHandler) GetRisks(c *gin.Context) {
+	var req dto.SpamRiskQueryDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err := util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.spamSvc.GetRisks(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
+
+// ClearRisk 复核为误判并解除限频
+func (h *SpamHandler) ClearRisk(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil || userID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	if err = h.spamSvc.ClearRisk(c.Request.Context(), c.GetUint64("user_id"), userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// ConfirmRisk 复核为垃圾行为并影子封禁用户
+func (h *SpamHandler) ConfirmRisk(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil || userID == 0 {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	var req dto.SoftRestrictionCreateDTO
+	if err = c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	if err = util.ValidateDTO(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err = h.spamSvc.ConfirmRisk(c.Request.Context(), c.GetUint64("user_id"), userID, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"Cornerstone/internal/pkg/response"
+	"Cornerstone/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StrikeHandler struct {
+	strikeSvc service.StrikeService
+}
+
+func NewStrikeHandler(strikeSvc service.StrikeService) *StrikeHandler {
+	return &StrikeHandler{
+		strikeSvc: strikeSvc,
+	}
+}
+
+// GetMyStrikes 查看自己的违规记录与当前处罚
+func (h *StrikeHandler) GetMyStrikes(c *gin.Context) {
+	res, err := h.strikeSvc.GetMyStrikes(c.Request.Context(), c.GetUint64("user_id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, res)
+}
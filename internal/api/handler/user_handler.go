@@ -436,6 +436,26 @@ func (s *UserHandler) GetHomeInfo(c *gin.Context) {
 	response.Success(c, user)
 }
 
+// GetSimilarCreators 与指定用户相似的创作者
+func (s *UserHandler) GetSimilarCreators(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
+	if size <= 0 || size > 50 {
+		size = 10
+	}
+
+	users, err := s.userSvc.GetSimilarCreators(c.Request.Context(), userID, size)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, users)
+}
+
 func (s *UserHandler) GetUserSimpleInfoById(c *gin.Context) {
 	query := c.Param("user_id")
 	userID, err := strconv.ParseUint(query, 10, 64)
@@ -520,6 +540,22 @@ func (s *UserHandler) UploadAvatar(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// SuggestUser 搜人输入补全
+func (s *UserHandler) SuggestUser(c *gin.Context) {
+	keyword := c.Query("keyword")
+	if keyword == "" {
+		response.Error(c, service.ErrParamInvalid)
+		return
+	}
+
+	suggestions, err := s.userSvc.SuggestUser(c.Request.Context(), keyword)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, suggestions)
+}
+
 // SearchUser 普通用户搜人 (脱敏数据)
 func (s *UserHandler) SearchUser(c *gin.Context) {
 	keyword := c.Query("keyword")
@@ -16,4 +16,15 @@ type HandlersGroup struct {
 	WSHandler                *handler.WsHandler
 	SysBoxHandler            *handler.SysBoxHandler
 	MediaHandler             *handler.MediaHandler
+	OnboardingHandler        *handler.OnboardingHandler
+	SearchIndexHandler       *handler.SearchIndexHandler
+	ReportHandler            *handler.ReportHandler
+	AppealHandler            *handler.AppealHandler
+	ModerationHandler        *handler.ModerationHandler
+	BlockRuleHandler         *handler.BlockRuleHandler
+	MediaHashHandler         *handler.MediaHashHandler
+	StrikeHandler            *handler.StrikeHandler
+	ReauditHandler           *handler.ReauditHandler
+	SoftRestrictionHandler   *handler.SoftRestrictionHandler
+	SpamHandler              *handler.SpamHandler
 }
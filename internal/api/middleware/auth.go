@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"Cornerstone/internal/model"
 	"Cornerstone/internal/pkg/consts"
 	"Cornerstone/internal/pkg/redis"
 	"Cornerstone/internal/pkg/response"
@@ -66,8 +67,19 @@ func AuthMiddleware() gin.HandlerFunc {
 			}
 		}
 
+		// 检查用户是否处于违规处罚期
+		restriction := getUserRestriction(c.Request.Context(), claims.UserID)
+		if restriction != nil && restriction.Level >= model.PenaltyBan {
+			response.Fail(c, response.Forbidden, restrictionMessage(restriction))
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("roles", claims.Roles)
+		if restriction != nil {
+			c.Set("restriction", restriction)
+		}
 
 		newCtx := context.WithValue(c.Request.Context(), "user_id", claims.UserID)
 		c.Request = c.Request.WithContext(newCtx)
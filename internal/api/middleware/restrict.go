@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/response"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+)
+
+// writeCooldownInterval 发布冷却期间两次发布之间的最小间隔
+const writeCooldownInterval = 10 * time.Minute
+
+// RestrictWrite 对禁言与发布冷却中的用户限制发布笔记、评论和私信，需在 AuthMiddleware 之后使用
+func RestrictWrite() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get("restriction")
+		if !ok {
+			c.Next()
+			return
+		}
+		restriction := value.(*model.UserRestriction)
+
+		switch restriction.Level {
+		case model.PenaltyMute:
+			response.Fail(c, response.Forbidden, restrictionMessage(restriction))
+			c.Abort()
+			return
+		case model.PenaltyCooldown:
+			key := consts.UserWriteCooldownKey + strconv.FormatUint(c.GetUint64("user_id"), 10)
+			ok, err := redis.GetRdbClient().SetNX(c.Request.Context(), key, time.Now().Unix(), writeCooldownInterval).Result()
+			if err == nil && !ok {
+				response.Fail(c, response.Forbidden, restrictionMessage(restriction))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// getUserRestriction 读取用户当前生效的处罚，无处罚或已到期时返回 nil
+func getUserRestriction(ctx context.Context, userID uint64) *model.UserRestriction {
+	value, _ := redis.GetValue(ctx, consts.UserRestrictionKey+strconv.FormatUint(userID, 10))
+	if value == "" {
+		return nil
+	}
+	var restriction model.UserRestriction
+	if err := json.Unmarshal([]byte(value), &restriction); err != nil {
+		return nil
+	}
+	if restriction.ExpiresAt > 0 && restriction.ExpiresAt <= time.Now().Unix() {
+		return nil
+	}
+	return &restriction
+}
+
+func restrictionMessage(r *model.UserRestriction) string {
+	var until string
+	if r.ExpiresAt > 0 {
+		until = time.Unix(r.ExpiresAt, 0).Format("2006-01-02 15:04")
+	}
+	switch r.Level {
+	case model.PenaltyCooldown:
+		return "账号处于发布冷却期，每 10 分钟仅可发布一次，" + until + " 解除"
+	case model.PenaltyMute:
+		return "账号已被禁言，" + until + " 解除"
+	case model.PenaltyBan:
+		return "账号已被封禁，" + until + " 解封"
+	default:
+		return "账号已被永久封禁"
+	}
+}
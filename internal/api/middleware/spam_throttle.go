@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/response"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// spamThrottleIntervals 各限频等级下同类操作的最小间隔
+var spamThrottleIntervals = map[int8]time.Duration{
+	model.SpamThrottleLight: 10 * time.Second,
+	model.SpamThrottleHeavy: time.Minute,
+}
+
+// SpamThrottle 对垃圾行为检测命中的用户按等级限制点赞、关注、评论频率，需在 AuthMiddleware 之后使用
+func SpamThrottle(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		uid := strconv.FormatUint(c.GetUint64("user_id"), 10)
+
+		value, _ := redis.GetValue(ctx, consts.SpamThrottleKey+uid)
+		level, _ := strconv.Atoi(value)
+		interval, ok := spamThrottleIntervals[int8(level)]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		key := consts.SpamThrottleGateKey + action + ":" + uid
+		ok, err := redis.GetRdbClient().SetNX(ctx, key, time.Now().Unix(), interval).Result()
+		if err == nil && !ok {
+			response.Fail(c, response.Forbidden, "操作过于频繁，请稍后再试")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redisv9.NewClient(&redisv9.Options{Addr: mr.Addr()})
+
+	prev := redis.Rdb
+	redis.Rdb = client
+	t.Cleanup(func() {
+		redis.Rdb = prev
+		_ = client.Close()
+	})
+	return mr
+}
+
+// newThrottleRouter 返回各操作经过限频后实际执行的次数
+func newThrottleRouter(userID uint64) (*gin.Engine, map[string]int) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", userID) })
+
+	handled := make(map[string]int)
+	for _, action := range []string{"like", "comment"} {
+		r.POST("/"+action, SpamThrottle(action), func(c *gin.Context) {
+			handled[action]++
+		})
+	}
+	return r, handled
+}
+
+func doPost(r *gin.Engine, path string) {
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+}
+
+func TestSpamThrottle(t *testing.T) {
+	mr := newTestRedis(t)
+	const userID = 5
+	r, handled := newThrottleRouter(userID)
+
+	// 未命中限频的用户不受影响
+	for i := 0; i < 3; i++ {
+		doPost(r, "/like")
+	}
+	if handled["like"] != 3 {
+		t.Fatalf("unthrottled likes handled = %d, want 3", handled["like"])
+	}
+
+	if err := mr.Set(consts.SpamThrottleKey+strconv.FormatUint(userID, 10), strconv.Itoa(int(model.SpamThrottleLight))); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		doPost(r, "/like")
+	}
+	if handled["like"] != 4 {
+		t.Fatalf("throttled likes handled = %d, want 4", handled["like"])
+	}
+	// 不同操作分别限频
+	doPost(r, "/comment")
+	if handled["comment"] != 1 {
+		t.Fatalf("comment handled = %d, want 1", handled["comment"])
+	}
+
+	// 间隔过后恢复
+	mr.FastForward(spamThrottleIntervals[model.SpamThrottleLight])
+	doPost(r, "/like")
+	if handled["like"] != 5 {
+		t.Fatalf("likes handled after interval = %d, want 5", handled["like"])
+	}
+}
@@ -51,8 +51,10 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 			userGroup.PUT("/password/forget", group.UserHandler.ForgetPassword)
 			userGroup.GET("/:user_id/home", group.UserHandler.GetHomeInfo)
 			userGroup.GET("/:user_id/simple", group.UserHandler.GetUserSimpleInfoById)
+			userGroup.GET("/:user_id/similar", group.UserHandler.GetSimilarCreators)
 			userGroup.GET("/batch/simple", group.UserHandler.GetUserSimpleInfoByIds)
 			userGroup.GET("/search", group.UserHandler.SearchUser)
+			userGroup.GET("/suggestion", group.UserHandler.SuggestUser)
 
 			authGroup := userGroup.Group("")
 			authGroup.Use(middleware.AuthMiddleware())
@@ -66,6 +68,7 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 				authGroup.POST("/avatar", group.UserHandler.UploadAvatar)
 				authGroup.POST("/cancel", group.UserHandler.CancelUser)
 				authGroup.GET("/roles", group.UserHandler.GetUserRole)
+				authGroup.GET("/strikes/me", group.StrikeHandler.GetMyStrikes)
 			}
 
 			// 需要登录 & 拥有 admin 角色
@@ -99,11 +102,31 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 				authGroup.GET("/followers/count", group.UserFollowHandler.GetUserFollowersCount)
 				authGroup.GET("/followings", group.UserFollowHandler.GetUserFollowings)
 				authGroup.GET("/followings/count", group.UserFollowHandler.GetUserFollowingCount)
-				authGroup.POST("/follow/:following_id", group.UserFollowHandler.Follow)
+				authGroup.POST("/follow/:following_id", middleware.SpamThrottle("follow"), group.UserFollowHandler.Follow)
 				authGroup.DELETE("/follow/:following_id", group.UserFollowHandler.Unfollow)
 			}
 		}
 
+		onboardingGroup := apiGroup.Group("/onboarding")
+		{
+			onboardingGroup.GET("/tags", group.OnboardingHandler.GetOnboardingTags)
+
+			authGroup := onboardingGroup.Group("")
+			authGroup.Use(middleware.AuthMiddleware())
+			{
+				authGroup.GET("/status", group.OnboardingHandler.GetOnboardingStatus)
+				authGroup.POST("/interests", group.OnboardingHandler.SubmitInterests)
+			}
+
+			adminGroup := authGroup.Group("/admin")
+			adminGroup.Use(middleware.CheckRoles("ADMIN"))
+			{
+				adminGroup.GET("/tags", group.OnboardingHandler.GetOnboardingTagsAdmin)
+				adminGroup.PUT("/tags", group.OnboardingHandler.SaveOnboardingTag)
+				adminGroup.DELETE("/tags/:tag_id", group.OnboardingHandler.DeleteOnboardingTag)
+			}
+		}
+
 		metricsGroup := apiGroup.Group("/metrics")
 		{
 			metricsGroup.Use(middleware.AuthMiddleware())
@@ -115,6 +138,12 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 				metricsGroup.GET("/post/7d/:post_id", group.PostMetricHandler.GetMetrics7Days)
 				metricsGroup.GET("/post/30d/:post_id", group.PostMetricHandler.GetMetrics30Days)
 			}
+
+			adminGroup := metricsGroup.Group("")
+			adminGroup.Use(middleware.CheckRoles("ADMIN"))
+			{
+				adminGroup.GET("/experiment/:experiment_id", group.PostMetricHandler.GetExperimentMetrics)
+			}
 		}
 
 		postGroup := apiGroup.Group("/posts")
@@ -125,6 +154,7 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 				authOptGroup.GET("/recommend", group.PostHandler.RecommendPost)
 				authOptGroup.GET("/search", group.PostHandler.SearchPost)
 				authOptGroup.GET("/suggestion", group.PostHandler.Suggestion)
+				authOptGroup.GET("/search/hot", group.PostHandler.GetHotSearches)
 				authOptGroup.GET("/lastest", group.PostHandler.LastestPost)
 				authOptGroup.GET("/detail/:post_id", group.PostHandler.GetPost)
 				authOptGroup.GET("/list/:user_id", group.PostHandler.GetPostByUserId)
@@ -134,19 +164,71 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 			authGroup := postGroup.Group("")
 			authGroup.Use(middleware.AuthMiddleware())
 			{
-				authGroup.POST("", group.PostHandler.CreatePost)
-				authGroup.PUT("/:post_id", group.PostHandler.UpdatePostContent)
+				authGroup.POST("", middleware.RestrictWrite(), group.PostHandler.CreatePost)
+				authGroup.PUT("/:post_id", middleware.RestrictWrite(), group.PostHandler.UpdatePostContent)
 				authGroup.DELETE("/:post_id", group.PostHandler.DeletePost)
 				authGroup.GET("/search/me", group.PostHandler.SearchPostMe)
+				authGroup.GET("/search/history", group.PostHandler.GetSearchHistory)
+				authGroup.DELETE("/search/history", group.PostHandler.DeleteSearchHistory)
 				authGroup.GET("/count/me", group.PostHandler.CountPostMe)
 				authGroup.GET("/self", group.PostHandler.GetPostSelf)
+				authGroup.POST("/recommend/dwell", group.PostHandler.ReportRecommendDwell)
 			}
 
 			auditGroup := authGroup.Group("/audit")
 			auditGroup.Use(middleware.AuthMiddleware(), middleware.CheckRoles("AUDIT", "ADMIN"))
 			{
 				auditGroup.GET("/list", group.PostHandler.GetWarningPosts)
+				auditGroup.GET("/verdicts", group.ModerationHandler.GetVerdicts)
 				auditGroup.PUT("/:post_id/status", group.PostHandler.UpdatePostStatus)
+
+				auditGroup.GET("/reports", group.ReportHandler.GetReportQueue)
+				auditGroup.GET("/reports/:case_id", group.ReportHandler.GetReportCase)
+				auditGroup.POST("/reports/:case_id/claim", group.ReportHandler.ClaimReportCase)
+				auditGroup.POST("/reports/:case_id/resolve", group.ReportHandler.ResolveReportCase)
+				auditGroup.POST("/reports/:case_id/dismiss", group.ReportHandler.DismissReportCase)
+
+				auditGroup.GET("/appeals", group.AppealHandler.GetAppealQueue)
+				auditGroup.POST("/appeals/:appeal_id/approve", group.AppealHandler.ApproveAppeal)
+				auditGroup.POST("/appeals/:appeal_id/reject", group.AppealHandler.RejectAppeal)
+
+				auditGroup.GET("/media-hashes", group.MediaHashHandler.GetHashBlocks)
+				auditGroup.POST("/media-hashes", group.MediaHashHandler.AddHashBlock)
+				auditGroup.DELETE("/media-hashes/:hash_id", group.MediaHashHandler.DeleteHashBlock)
+
+				auditGroup.GET("/restrictions", group.SoftRestrictionHandler.GetRestrictions)
+				auditGroup.POST("/restrictions/users/:user_id/shadow-ban", group.SoftRestrictionHandler.ShadowBanUser)
+				auditGroup.DELETE("/restrictions/users/:user_id/shadow-ban", group.SoftRestrictionHandler.LiftShadowBan)
+				auditGroup.POST("/restrictions/posts/:post_id/limit", group.SoftRestrictionHandler.LimitPost)
+				auditGroup.DELETE("/restrictions/posts/:post_id/limit", group.SoftRestrictionHandler.LiftPostLimit)
+
+				auditGroup.GET("/spam", group.SpamHandler.GetRisks)
+				auditGroup.POST("/spam/:user_id/clear", group.SpamHandler.ClearRisk)
+				auditGroup.POST("/spam/:user_id/confirm", group.SpamHandler.ConfirmRisk)
+			}
+
+			adminGroup := authGroup.Group("/admin")
+			adminGroup.Use(middleware.CheckRoles("ADMIN"))
+			{
+				adminGroup.GET("/recommend/debug", group.PostHandler.DebugRecommendScore)
+				adminGroup.GET("/search/hot", group.PostHandler.GetHotSearchesAdmin)
+				adminGroup.PUT("/search/hot/pin", group.PostHandler.PinHotSearch)
+				adminGroup.DELETE("/search/hot/pin", group.PostHandler.UnpinHotSearch)
+				adminGroup.PUT("/search/hot/suppress", group.PostHandler.SuppressHotSearch)
+				adminGroup.DELETE("/search/hot/suppress", group.PostHandler.UnsuppressHotSearch)
+
+				adminGroup.GET("/blocklist", group.BlockRuleHandler.GetBlockRules)
+				adminGroup.POST("/blocklist", group.BlockRuleHandler.CreateBlockRule)
+				adminGroup.PUT("/blocklist/:rule_id", group.BlockRuleHandler.UpdateBlockRule)
+				adminGroup.DELETE("/blocklist/:rule_id", group.BlockRuleHandler.DeleteBlockRule)
+
+				adminGroup.GET("/reaudit", group.ReauditHandler.GetCampaigns)
+				adminGroup.POST("/reaudit", group.ReauditHandler.CreateCampaign)
+				adminGroup.GET("/reaudit/:campaign_id", group.ReauditHandler.GetCampaign)
+				adminGroup.GET("/reaudit/:campaign_id/items", group.ReauditHandler.GetCampaignItems)
+				adminGroup.POST("/reaudit/:campaign_id/pause", group.ReauditHandler.PauseCampaign)
+				adminGroup.POST("/reaudit/:campaign_id/resume", group.ReauditHandler.ResumeCampaign)
+				adminGroup.POST("/reaudit/:campaign_id/apply", group.ReauditHandler.ApplyCampaign)
 			}
 		}
 
@@ -157,24 +239,33 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 			{
 				authOptActionGroup.GET("/comments/:post_id", group.PostActionHandler.GetComments)
 				authOptActionGroup.GET("/sub-comments/:root_id", group.PostActionHandler.GetSubComments)
+				authOptActionGroup.GET("/comment-edits/:comment_id", group.PostActionHandler.GetCommentEdits)
 				authOptActionGroup.POST("/batch/likes", group.PostActionHandler.GetBatchLikes)
 				authOptActionGroup.GET("/state", group.PostActionHandler.GetPostActionState)
+				authOptActionGroup.GET("/reactions", group.PostActionHandler.GetReactionSet)
 			}
 
 			authActionGroup := postActionGroup.Group("")
 			authActionGroup.Use(middleware.AuthMiddleware())
 			{
-				authActionGroup.POST("/likes/:post_id", group.PostActionHandler.LikePost)
+				authActionGroup.POST("/likes/:post_id", middleware.SpamThrottle("like"), group.PostActionHandler.LikePost)
 				authActionGroup.POST("/collects/:post_id", group.PostActionHandler.CollectPost)
 
-				authActionGroup.POST("/comments", group.PostActionHandler.CreateComment)
+				authActionGroup.POST("/comments", middleware.RestrictWrite(), middleware.SpamThrottle("comment"), group.PostActionHandler.CreateComment)
+				authActionGroup.PUT("/comments/:comment_id", middleware.RestrictWrite(), middleware.SpamThrottle("comment"), group.PostActionHandler.EditComment)
 				authActionGroup.DELETE("/comments/:comment_id", group.PostActionHandler.DeleteComment)
-				authActionGroup.POST("/comments/:comment_id/like", group.PostActionHandler.LikeComment)
+				authActionGroup.POST("/comments/:comment_id/like", middleware.SpamThrottle("like"), group.PostActionHandler.LikeComment)
+				authActionGroup.POST("/reactions/posts/:post_id", middleware.SpamThrottle("like"), group.PostActionHandler.ReactPost)
+				authActionGroup.POST("/reactions/comments/:comment_id", middleware.SpamThrottle("like"), group.PostActionHandler.ReactComment)
+				authActionGroup.POST("/comments/:comment_id/pin", group.PostActionHandler.PinComment)
+				authActionGroup.POST("/comments/:comment_id/hide", group.PostActionHandler.HideComment)
+				authActionGroup.GET("/comment-settings/:post_id", group.PostActionHandler.GetCommentSettings)
+				authActionGroup.PUT("/comment-settings/:post_id", group.PostActionHandler.UpdateCommentSettings)
 
 				authActionGroup.GET("/liked", group.PostActionHandler.GetUserLikes)
 				authActionGroup.GET("/collections", group.PostActionHandler.GetUserCollections)
 
-				authActionGroup.POST("/reports/:post_id", group.PostActionHandler.ReportPost)
+				authActionGroup.POST("/reports/:post_id", group.ReportHandler.ReportPost)
 			}
 		}
 
@@ -185,11 +276,12 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 			authGroup.Use(middleware.AuthMiddleware())
 			{
 				authGroup.GET("/ticket", group.WSHandler.GetWSTicket)
-				authGroup.POST("/send", group.IMHandler.SendMessage)
+				authGroup.POST("/send", middleware.RestrictWrite(), group.IMHandler.SendMessage)
 				authGroup.GET("/history", group.IMHandler.GetChatHistory)
 				authGroup.GET("/sync", group.IMHandler.GetNewMessages)
 				authGroup.GET("/list", group.IMHandler.GetConversationList)
 				authGroup.POST("/read", group.IMHandler.MarkAsRead)
+				authGroup.POST("/reaction", group.IMHandler.ReactMessage)
 			}
 		}
 
@@ -202,6 +294,28 @@ func SetupRouter(group *HandlersGroup) *gin.Engine {
 			sysbox.POST("/read/all", group.SysBoxHandler.MarkAllRead)
 		}
 
+		reportGroup := apiGroup.Group("/reports")
+		reportGroup.Use(middleware.AuthMiddleware())
+		{
+			reportGroup.POST("", group.ReportHandler.CreateReport)
+		}
+
+		appealGroup := apiGroup.Group("/appeals")
+		appealGroup.Use(middleware.AuthMiddleware())
+		{
+			appealGroup.POST("", group.AppealHandler.CreateAppeal)
+			appealGroup.GET("/me", group.AppealHandler.GetMyAppeals)
+		}
+
+		searchGroup := apiGroup.Group("/search")
+		searchGroup.Use(middleware.AuthMiddleware(), middleware.CheckRoles("ADMIN"))
+		{
+			searchGroup.POST("/admin/reindex/:target", group.SearchIndexHandler.StartReindex)
+			searchGroup.GET("/admin/reindex/:target", group.SearchIndexHandler.GetReindexProgress)
+			searchGroup.POST("/admin/reconcile", group.SearchIndexHandler.StartReconcile)
+			searchGroup.GET("/admin/reconcile", group.SearchIndexHandler.GetReconcileReport)
+		}
+
 		mediaGroup := apiGroup.Group("/media")
 		{
 			mediaGroup.Use(middleware.AuthMiddleware())
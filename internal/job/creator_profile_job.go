@@ -0,0 +1,67 @@
+package job
+
+import (
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/logger"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/service"
+	"context"
+	log "log/slog"
+
+	"github.com/google/uuid"
+)
+
+type CreatorProfileJob struct {
+	userSvc service.UserService
+}
+
+func NewCreatorProfileJob(userSvc service.UserService) *CreatorProfileJob {
+	return &CreatorProfileJob{
+		userSvc: userSvc,
+	}
+}
+
+// Run 刷新笔记或简介发生变更的创作者画像
+func (s *CreatorProfileJob) Run() {
+	traceID := "job-creator-profile-" + uuid.NewString()
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, traceID)
+
+	processingKey := consts.UserCreatorDirtyKey + ":processing"
+	err := redis.Rename(ctx, consts.UserCreatorDirtyKey, processingKey)
+	if err != nil {
+		return
+	}
+
+	tempSet, err := redis.GetSet(ctx, processingKey)
+	if err != nil {
+		log.ErrorContext(ctx, "get creator dirty set error", "err", err)
+		return
+	}
+
+	userIDs, err := util.StrSliceToUInt64Slice(tempSet)
+	if err != nil {
+		log.ErrorContext(ctx, "convert creator set to int slice error", "err", err)
+		return
+	}
+
+	successCount := 0
+	for _, uid := range userIDs {
+		if err = s.userSvc.RefreshCreatorProfile(ctx, uid); err != nil {
+			log.ErrorContext(ctx, "refresh creator profile error", "uid", uid, "err", err)
+			// 失败的用户放回脏集合，下一轮重试
+			_ = redis.SAdd(ctx, consts.UserCreatorDirtyKey, uid)
+			continue
+		}
+		successCount++
+	}
+
+	err = redis.DeleteKey(ctx, processingKey)
+	if err != nil {
+		log.ErrorContext(ctx, "delete creator processing set error", "err", err)
+	}
+
+	log.InfoContext(ctx, "refresh creator profile success",
+		"total_count", len(userIDs),
+		"success_count", successCount)
+}
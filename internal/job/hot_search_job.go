@@ -0,0 +1,30 @@
+package job
+
+import (
+	"Cornerstone/internal/pkg/logger"
+	"Cornerstone/internal/service"
+	"context"
+	log "log/slog"
+
+	"github.com/google/uuid"
+)
+
+type HotSearchJob struct {
+	postSvc service.PostService
+}
+
+func NewHotSearchJob(postSvc service.PostService) *HotSearchJob {
+	return &HotSearchJob{
+		postSvc: postSvc,
+	}
+}
+
+// Run 热搜词热度按小时衰减
+func (s *HotSearchJob) Run() {
+	traceID := "job-hot-search-" + uuid.NewString()
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, traceID)
+
+	if err := s.postSvc.DecayHotSearches(ctx); err != nil {
+		log.ErrorContext(ctx, "decay hot search error", "err", err)
+	}
+}
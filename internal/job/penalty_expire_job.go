@@ -0,0 +1,30 @@
+package job
+
+import (
+	"Cornerstone/internal/pkg/logger"
+	"Cornerstone/internal/service"
+	"context"
+	log "log/slog"
+
+	"github.com/google/uuid"
+)
+
+type PenaltyExpireJob struct {
+	strikeSvc service.StrikeService
+}
+
+func NewPenaltyExpireJob(strikeSvc service.StrikeService) *PenaltyExpireJob {
+	return &PenaltyExpireJob{
+		strikeSvc: strikeSvc,
+	}
+}
+
+// Run 解除已到期的违规处罚
+func (s *PenaltyExpireJob) Run() {
+	traceID := "job-penalty-expire-" + uuid.NewString()
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, traceID)
+
+	if err := s.strikeSvc.ExpirePenalties(ctx); err != nil {
+		log.ErrorContext(ctx, "expire penalties error", "err", err)
+	}
+}
@@ -0,0 +1,78 @@
+package job
+
+import (
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/logger"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/service"
+	"context"
+	log "log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ReactionJob 将 Redis 中变动过的表情回应计数回写 MySQL
+type ReactionJob struct {
+	reactionSvc service.ReactionService
+}
+
+func NewReactionJob(reactionSvc service.ReactionService) *ReactionJob {
+	return &ReactionJob{
+		reactionSvc: reactionSvc,
+	}
+}
+
+func (s *ReactionJob) Run() {
+	traceID := "job-reaction-" + uuid.NewString()
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, traceID)
+
+	processingKey := consts.ReactionDirtyKey + ":processing"
+	err := redis.Rename(ctx, consts.ReactionDirtyKey, processingKey)
+	if err != nil {
+		return
+	}
+
+	members, err := redis.GetSet(ctx, processingKey)
+	if err != nil {
+		log.ErrorContext(ctx, "get reaction dirty set error", "err", err)
+		return
+	}
+
+	log.InfoContext(ctx, "start syncing reaction counts", "count", len(members))
+
+	successCount := 0
+	for _, m := range members {
+		// 成员格式为 对象类型:对象ID
+		typeStr, idStr, _ := strings.Cut(m, ":")
+		targetType, err1 := strconv.ParseInt(typeStr, 10, 8)
+		targetID, err2 := strconv.ParseUint(idStr, 10, 64)
+		if err1 != nil || err2 != nil {
+			log.ErrorContext(ctx, "invalid reaction dirty member", "member", m)
+			continue
+		}
+
+		counts, err := s.reactionSvc.GetReactionCounts(ctx, int8(targetType), targetID)
+		if err != nil {
+			log.ErrorContext(ctx, "get reaction counts error", "member", m, "err", err)
+			continue
+		}
+
+		err = s.reactionSvc.SyncReactionCounts(ctx, int8(targetType), targetID, counts)
+		if err != nil {
+			log.ErrorContext(ctx, "sync reaction counts to mysql error", "member", m, "err", err)
+			continue
+		}
+		successCount++
+	}
+
+	err = redis.DeleteKey(ctx, processingKey)
+	if err != nil {
+		log.ErrorContext(ctx, "delete reaction processing set error", "err", err)
+	}
+
+	log.InfoContext(ctx, "sync reaction counts success",
+		"total_count", len(members),
+		"success_count", successCount)
+}
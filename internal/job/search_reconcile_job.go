@@ -0,0 +1,35 @@
+package job
+
+import (
+	"Cornerstone/internal/pkg/logger"
+	"Cornerstone/internal/service"
+	"context"
+	"errors"
+	log "log/slog"
+
+	"github.com/google/uuid"
+)
+
+type SearchReconcileJob struct {
+	searchIndexSvc service.SearchIndexService
+}
+
+func NewSearchReconcileJob(searchIndexSvc service.SearchIndexService) *SearchReconcileJob {
+	return &SearchReconcileJob{
+		searchIndexSvc: searchIndexSvc,
+	}
+}
+
+// Run 对账 MySQL 与 ES，修复 Kafka 丢消息导致的索引漂移
+func (s *SearchReconcileJob) Run() {
+	traceID := "job-search-reconcile-" + uuid.NewString()
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, traceID)
+
+	if _, err := s.searchIndexSvc.Reconcile(ctx); err != nil {
+		if errors.Is(err, service.ErrReconcileRunning) {
+			log.InfoContext(ctx, "search reconcile already running")
+			return
+		}
+		log.ErrorContext(ctx, "search reconcile error", "err", err)
+	}
+}
@@ -0,0 +1,30 @@
+package job
+
+import (
+	"Cornerstone/internal/pkg/logger"
+	"Cornerstone/internal/service"
+	"context"
+	log "log/slog"
+
+	"github.com/google/uuid"
+)
+
+type SoftRestrictionSyncJob struct {
+	softRestrictionSvc service.SoftRestrictionService
+}
+
+func NewSoftRestrictionSyncJob(softRestrictionSvc service.SoftRestrictionService) *SoftRestrictionSyncJob {
+	return &SoftRestrictionSyncJob{
+		softRestrictionSvc: softRestrictionSvc,
+	}
+}
+
+// Run 按数据库重建影子封禁与限流缓存
+func (s *SoftRestrictionSyncJob) Run() {
+	traceID := "job-soft-restriction-sync-" + uuid.NewString()
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, traceID)
+
+	if err := s.softRestrictionSvc.SyncCache(ctx); err != nil {
+		log.ErrorContext(ctx, "sync soft restrictions error", "err", err)
+	}
+}
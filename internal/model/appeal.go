@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// 申诉对象类型
+const (
+	AppealTargetPost    int8 = 1 // 笔记
+	AppealTargetComment int8 = 2 // 评论
+)
+
+// 申诉状态
+const (
+	AppealPending  int8 = 0 // 待处理
+	AppealApproved int8 = 1 // 已通过
+	AppealRejected int8 = 2 // 已驳回
+)
+
+// Appeal 作者对被拒绝内容的申诉
+type Appeal struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	TargetType  int8       `gorm:"not null;index:idx_target" json:"targetType"`
+	TargetID    uint64     `gorm:"not null;index:idx_target" json:"targetId"`
+	UserID      uint64     `gorm:"not null;index:idx_user" json:"userId"`
+	Message     string     `gorm:"type:varchar(500);not null" json:"message"`
+	Snapshot    string     `gorm:"type:varchar(1000);not null;default:''" json:"snapshot"`
+	LLMDecision string     `gorm:"column:llm_decision;type:varchar(2000);not null;default:''" json:"llmDecision"`
+	Status      int8       `gorm:"not null;default:0;index:idx_status_created" json:"status"`
+	HandledBy   uint64     `gorm:"not null;default:0" json:"handledBy"`
+	HandleNote  string     `gorm:"type:varchar(500);not null;default:''" json:"handleNote"`
+	HandledAt   *time.Time `json:"handledAt"`
+	CreatedAt   time.Time  `gorm:"index:idx_user;index:idx_status_created" json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+func (Appeal) TableName() string {
+	return "appeals"
+}
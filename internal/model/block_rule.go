@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// 屏蔽规则匹配方式
+const (
+	BlockMatchWord  int8 = 1 // 关键词
+	BlockMatchRegex int8 = 2 // 正则
+)
+
+// 屏蔽规则命中处理，取值与内容审核状态一致
+const (
+	BlockActionReview int8 = 2 // 转人工复核
+	BlockActionDeny   int8 = 3 // 直接拒绝
+)
+
+// BlockRule 内容审核屏蔽规则
+type BlockRule struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	Pattern    string    `gorm:"type:varchar(200);not null;uniqueIndex:uk_type_pattern" json:"pattern"`
+	MatchType  int8      `gorm:"not null;default:1;uniqueIndex:uk_type_pattern" json:"matchType"`
+	Action     int8      `gorm:"not null;default:3" json:"action"`
+	FoldPinyin bool      `gorm:"not null;default:false" json:"foldPinyin"`
+	Enabled    bool      `gorm:"not null;default:true" json:"enabled"`
+	Remark     string    `gorm:"type:varchar(200);not null;default:''" json:"remark"`
+	CreatedBy  uint64    `gorm:"not null;default:0" json:"createdBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func (BlockRule) TableName() string {
+	return "content_blocklist"
+}
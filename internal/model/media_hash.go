@@ -0,0 +1,53 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// 图片指纹黑名单来源
+const (
+	MediaHashSourceManual  int8 = 1 // 审核员添加
+	MediaHashSourceConfirm int8 = 2 // 拒绝确认后自动添加
+)
+
+// MediaHash 上传图片或视频抽帧的感知哈希
+type MediaHash struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	MediaURL  string    `gorm:"type:varchar(512);not null;uniqueIndex:uk_media_frame" json:"mediaUrl"`
+	Frame     int       `gorm:"not null;default:0;uniqueIndex:uk_media_frame" json:"frame"`
+	PHash     uint64    `gorm:"column:phash;not null" json:"phash"`
+	DHash     uint64    `gorm:"column:dhash;not null" json:"dhash"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (MediaHash) TableName() string {
+	return "media_hashes"
+}
+
+// Ref 媒体引用，与审核记录中的 refs 保持一致，视频帧为 "视频地址#帧序号"
+func (m *MediaHash) Ref() string {
+	if m.Frame == 0 {
+		return m.MediaURL
+	}
+	return m.MediaURL + "#" + strconv.Itoa(m.Frame-1)
+}
+
+// MediaHashBlock 违规图片感知哈希黑名单
+type MediaHashBlock struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	PHash      uint64    `gorm:"column:phash;not null;uniqueIndex:uk_hash" json:"phash"`
+	DHash      uint64    `gorm:"column:dhash;not null;uniqueIndex:uk_hash" json:"dhash"`
+	Source     int8      `gorm:"not null;default:1" json:"source"`
+	MediaURL   string    `gorm:"type:varchar(512);not null;default:''" json:"mediaUrl"`
+	Frame      int       `gorm:"not null;default:0" json:"frame"`
+	TargetType int8      `gorm:"not null;default:0" json:"targetType"`
+	TargetID   uint64    `gorm:"not null;default:0" json:"targetId"`
+	Remark     string    `gorm:"type:varchar(200);not null;default:''" json:"remark"`
+	CreatedBy  uint64    `gorm:"not null;default:0" json:"createdBy"`
+	CreatedAt  time.Time `gorm:"index:idx_created" json:"createdAt"`
+}
+
+func (MediaHashBlock) TableName() string {
+	return "media_hash_blocklist"
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// 审核对象类型
+const (
+	ModerationTargetPost    int8 = 1 // 笔记
+	ModerationTargetComment int8 = 2 // 评论
+)
+
+// ModerationVerdict 模型审核记录，一次模型调用对应一条
+type ModerationVerdict struct {
+	ID            uint64     `gorm:"primaryKey" json:"id"`
+	TargetType    int8       `gorm:"not null;index:idx_target" json:"targetType"`
+	TargetID      uint64     `gorm:"not null;index:idx_target" json:"targetId"`
+	AuditID       string     `gorm:"type:varchar(36);not null;index:idx_audit" json:"auditId"`
+	Source        string     `gorm:"type:varchar(20);not null" json:"source"`
+	Refs          StringList `gorm:"type:json" json:"refs"`
+	Input         string     `gorm:"type:varchar(1000);not null;default:''" json:"input"`
+	Status        int8       `gorm:"not null" json:"status"`
+	Reason        string     `gorm:"type:varchar(500);not null;default:''" json:"reason"`
+	Confidence    float64    `gorm:"not null;default:0" json:"confidence"`
+	Model         string     `gorm:"type:varchar(64);not null;default:''" json:"model"`
+	PromptVersion string     `gorm:"type:varchar(64);not null;default:''" json:"promptVersion"`
+	LatencyMs     int64      `gorm:"not null;default:0" json:"latencyMs"`
+	Raw           string     `gorm:"type:text" json:"raw"`
+	CreatedAt     time.Time  `gorm:"index:idx_target" json:"createdAt"`
+}
+
+func (ModerationVerdict) TableName() string {
+	return "moderation_verdicts"
+}
+
+// StringList 字符串数组
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, l)
+}
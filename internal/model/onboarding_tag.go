@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type OnboardingTag struct {
+	TagID     uint64    `gorm:"primaryKey" json:"tagId"`
+	SortOrder int       `gorm:"not null;default:0;index:idx_sort_order" json:"sortOrder"`
+	Enabled   bool      `gorm:"not null;default:true" json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Tag *Tag `gorm:"foreignKey:TagID;references:ID" json:"tag,omitempty"`
+}
+
+func (OnboardingTag) TableName() string {
+	return "onboarding_tags"
+}
@@ -16,6 +16,7 @@ type PostComment struct {
 	LikesCount    int       `gorm:"not null;default:0" json:"likesCount"`
 	Status        int8      `gorm:"not null;default:0" json:"status"`
 	IsDeleted     bool      `gorm:"type:tinyint(1);not null;default:0" json:"isDeleted"`
+	EditPending   bool      `gorm:"type:tinyint(1);not null;default:0" json:"editPending"` // 有待审核的编辑，评论状态不变
 	CreatedAt     time.Time `json:"createdAt"`
 	UpdatedAt     time.Time `json:"updatedAt"`
 
@@ -29,3 +30,11 @@ type PostComment struct {
 func (PostComment) TableName() string {
 	return "post_comments"
 }
+
+// CommentHotStat 根评论热度计算所需的统计
+type CommentHotStat struct {
+	ID         uint64
+	CreatedAt  time.Time
+	LikesCount int
+	ReplyCount int
+}
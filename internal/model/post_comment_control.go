@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+// 笔记评论权限
+const (
+	CommentModeAll       int8 = 0 // 所有人可评论
+	CommentModeFollowers int8 = 1 // 仅作者的粉丝可评论
+	CommentModeOff       int8 = 2 // 关闭评论
+)
+
+// 作者对评论的标记类型
+const (
+	CommentMarkPinned int8 = 1 // 置顶：首页评论列表优先展示
+	CommentMarkHidden int8 = 2 // 隐藏：仅评论者本人可见
+)
+
+// PostCommentSetting 笔记作者的评论设置，未设置时视为所有人可评论
+type PostCommentSetting struct {
+	PostID      uint64    `gorm:"primaryKey" json:"postId"`
+	CommentMode int8      `gorm:"not null;default:0" json:"commentMode"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (PostCommentSetting) TableName() string {
+	return "post_comment_settings"
+}
+
+// PostCommentMark 笔记作者对自己笔记下评论的置顶、隐藏标记
+type PostCommentMark struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	PostID    uint64    `gorm:"not null;index:idx_post_kind" json:"postId"`
+	CommentID uint64    `gorm:"not null;uniqueIndex:uk_comment_kind" json:"commentId"`
+	UserID    uint64    `gorm:"not null" json:"userId"`
+	Kind      int8      `gorm:"not null;uniqueIndex:uk_comment_kind;index:idx_post_kind" json:"kind"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (PostCommentMark) TableName() string {
+	return "post_comment_marks"
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// 评论编辑审核状态
+const (
+	CommentEditPending  int8 = 0 // 待审核，评论仍展示编辑前内容
+	CommentEditApproved int8 = 1 // 审核通过，新内容已生效
+	CommentEditRejected int8 = 2 // 审核未通过，保留编辑前内容
+)
+
+// PostCommentEdit 评论的一次编辑记录
+type PostCommentEdit struct {
+	ID              uint64     `gorm:"primaryKey" json:"id"`
+	CommentID       uint64     `gorm:"not null;index:idx_comment_status" json:"commentId"`
+	UserID          uint64     `gorm:"not null" json:"userId"`
+	PreviousContent string     `gorm:"type:varchar(1000);not null" json:"previousContent"`
+	Content         string     `gorm:"type:varchar(1000);not null" json:"content"`
+	Status          int8       `gorm:"not null;default:0;index:idx_comment_status" json:"status"`
+	ReviewedAt      *time.Time `json:"reviewedAt"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+func (PostCommentEdit) TableName() string {
+	return "post_comment_edits"
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// PostDiscussionSummary 笔记评论区的 AI 讨论总结，评论数增长到阈值后重新生成
+type PostDiscussionSummary struct {
+	PostID       uint64               `gorm:"primaryKey" json:"postId"`
+	Overview     string               `gorm:"type:varchar(500);not null" json:"overview"`
+	Viewpoints   DiscussionViewpoints `gorm:"type:json" json:"viewpoints"`
+	CommentCount int64                `gorm:"not null;default:0" json:"commentCount"`
+	Model        string               `gorm:"type:varchar(64);not null" json:"model"`
+	CreatedAt    time.Time            `json:"createdAt"`
+	UpdatedAt    time.Time            `json:"updatedAt"`
+}
+
+func (PostDiscussionSummary) TableName() string {
+	return "post_discussion_summaries"
+}
+
+// DiscussionViewpoint 主要观点及其代表评论
+type DiscussionViewpoint struct {
+	Viewpoint  string   `json:"viewpoint"`
+	CommentIDs []uint64 `json:"comment_ids"`
+}
+
+type DiscussionViewpoints []*DiscussionViewpoint
+
+func (v DiscussionViewpoints) Value() (driver.Value, error) {
+	return json.Marshal(v)
+}
+
+func (v *DiscussionViewpoints) Scan(value interface{}) error {
+	if value == nil {
+		*v = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, v)
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+// 表情回应对象类型
+const (
+	ReactionTargetPost    int8 = 1
+	ReactionTargetComment int8 = 2
+)
+
+// Reaction 用户对笔记或评论的表情回应，每个用户对同一对象只保留一个表情
+type Reaction struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	TargetType int8      `gorm:"not null;uniqueIndex:uk_target_user" json:"targetType"`
+	TargetID   uint64    `gorm:"not null;uniqueIndex:uk_target_user" json:"targetId"`
+	UserID     uint64    `gorm:"not null;uniqueIndex:uk_target_user" json:"userId"`
+	Emoji      string    `gorm:"type:varchar(32);not null" json:"emoji"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func (Reaction) TableName() string {
+	return "reactions"
+}
+
+// ReactionCount 各表情的回应数，由定时任务从 Redis 回写
+type ReactionCount struct {
+	TargetType int8      `gorm:"primaryKey" json:"targetType"`
+	TargetID   uint64    `gorm:"primaryKey" json:"targetId"`
+	Emoji      string    `gorm:"primaryKey;type:varchar(32)" json:"emoji"`
+	Count      int64     `gorm:"not null;default:0" json:"count"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func (ReactionCount) TableName() string {
+	return "reaction_counts"
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+)
+
+// 复审对象
+const (
+	ReauditTargetPost    int8 = 1
+	ReauditTargetComment int8 = 2
+)
+
+// 复审任务状态
+const (
+	ReauditRunning   int8 = 1 // 运行中
+	ReauditPaused    int8 = 2 // 已暂停
+	ReauditCompleted int8 = 3 // 已完成，等待确认变更
+	ReauditApplying  int8 = 4 // 正在应用变更
+	ReauditApplied   int8 = 5 // 已应用
+	ReauditFailed    int8 = 6 // 失败
+)
+
+// 复审变更应用状态
+const (
+	ReauditItemPending int8 = 0 // 待应用
+	ReauditItemApplied int8 = 1 // 已应用
+	ReauditItemSkipped int8 = 2 // 内容状态在复审后已变化，跳过
+)
+
+// ReauditCampaign 策略或提示词变更后的批量复审任务
+type ReauditCampaign struct {
+	ID            uint64     `gorm:"primaryKey" json:"id"`
+	TargetType    int8       `gorm:"not null" json:"targetType"`
+	Tag           string     `gorm:"type:varchar(50);not null;default:''" json:"tag"`
+	ContentStatus int8       `gorm:"not null;default:0" json:"contentStatus"`
+	StartAt       *time.Time `json:"startAt"`
+	EndAt         *time.Time `json:"endAt"`
+	MaxItems      int        `gorm:"not null;default:0" json:"maxItems"`
+	Concurrency   int        `gorm:"not null;default:2" json:"concurrency"`
+	State         int8       `gorm:"not null;default:1;index:idx_state" json:"state"`
+	CursorID      uint64     `gorm:"not null;default:0" json:"cursorId"`
+	Scanned       int        `gorm:"not null;default:0" json:"scanned"`
+	Changed       int        `gorm:"not null;default:0" json:"changed"`
+	Failed        int        `gorm:"not null;default:0" json:"failed"`
+	Applied       int        `gorm:"not null;default:0" json:"applied"`
+	Remark        string     `gorm:"type:varchar(200);not null;default:''" json:"remark"`
+	Error         string     `gorm:"type:varchar(500);not null;default:''" json:"error"`
+	CreatedBy     uint64     `gorm:"not null;default:0" json:"createdBy"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	FinishedAt    *time.Time `json:"finishedAt"`
+}
+
+func (ReauditCampaign) TableName() string {
+	return "reaudit_campaigns"
+}
+
+// ReauditItem 复审结果与原状态不一致的内容
+type ReauditItem struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	CampaignID uint64    `gorm:"not null;uniqueIndex:uk_campaign_target" json:"campaignId"`
+	TargetID   uint64    `gorm:"not null;uniqueIndex:uk_campaign_target" json:"targetId"`
+	OldStatus  int8      `gorm:"not null" json:"oldStatus"`
+	NewStatus  int8      `gorm:"not null" json:"newStatus"`
+	Reason     string    `gorm:"type:varchar(200);not null;default:''" json:"reason"`
+	ApplyState int8      `gorm:"not null;default:0" json:"applyState"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (ReauditItem) TableName() string {
+	return "reaudit_items"
+}
+
+// ReauditSubject 待复审的笔记或评论
+type ReauditSubject struct {
+	ID      uint64
+	Title   string
+	Content string
+	Media   MediaList
+	Status  int8
+}
+
+// ReauditTransition 复审状态变化统计
+type ReauditTransition struct {
+	OldStatus int8  `json:"oldStatus"`
+	NewStatus int8  `json:"newStatus"`
+	Count     int64 `json:"count"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	RecommendEventExposure int8 = 1 // 曝光
+	RecommendEventClick    int8 = 2 // 点击
+	RecommendEventLike     int8 = 3 // 点赞
+	RecommendEventDwell    int8 = 4 // 停留
+)
+
+type RecommendEvent struct {
+	ID           uint64    `gorm:"primaryKey"`
+	ExperimentID string    `gorm:"not null;default:'';index:idx_experiment_created" json:"experimentId"`
+	Variant      string    `gorm:"not null;default:'default'" json:"variant"`
+	UserID       uint64    `gorm:"not null;index:idx_user_post" json:"userId"`
+	PostID       uint64    `gorm:"not null;index:idx_user_post" json:"postId"`
+	EventType    int8      `gorm:"not null" json:"eventType"`
+	DwellMs      int       `gorm:"not null;default:0" json:"dwellMs"`
+	CreatedAt    time.Time `gorm:"index:idx_experiment_created" json:"createdAt"`
+}
+
+func (RecommendEvent) TableName() string {
+	return "recommend_events"
+}
+
+// RecommendVariantStat 实验分组聚合统计
+type RecommendVariantStat struct {
+	Variant    string
+	Exposures  int64
+	Clicks     int64
+	Likes      int64
+	DwellCount int64
+	DwellTotal int64
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+)
+
+// 举报对象类型
+const (
+	ReportTargetPost    int8 = 1 // 笔记
+	ReportTargetComment int8 = 2 // 评论
+	ReportTargetUser    int8 = 3 // 用户
+	ReportTargetMessage int8 = 4 // 私信
+)
+
+// 举报原因
+const (
+	ReportReasonSpam         int8 = 1  // 垃圾广告
+	ReportReasonPorn         int8 = 2  // 色情低俗
+	ReportReasonViolence     int8 = 3  // 暴力血腥
+	ReportReasonHarassment   int8 = 4  // 人身攻击
+	ReportReasonIllegal      int8 = 5  // 违法违规
+	ReportReasonMisinfo      int8 = 6  // 虚假信息
+	ReportReasonInfringement int8 = 7  // 侵权
+	ReportReasonOther        int8 = 99 // 其他
+)
+
+// ReportReasonWeights 举报原因对工单优先级的权重
+var ReportReasonWeights = map[int8]int{
+	ReportReasonSpam:         2,
+	ReportReasonPorn:         8,
+	ReportReasonViolence:     8,
+	ReportReasonHarassment:   5,
+	ReportReasonIllegal:      10,
+	ReportReasonMisinfo:      4,
+	ReportReasonInfringement: 3,
+	ReportReasonOther:        1,
+}
+
+// 工单状态
+const (
+	ReportCasePending   int8 = 0 // 待处理
+	ReportCaseClaimed   int8 = 1 // 处理中
+	ReportCaseResolved  int8 = 2 // 已处置
+	ReportCaseDismissed int8 = 3 // 已驳回
+)
+
+// ReportCase 举报工单，同一对象未结案的举报合并到同一工单
+type ReportCase struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	TargetType  int8       `gorm:"not null;index:idx_target" json:"targetType"`
+	TargetID    uint64     `gorm:"not null;index:idx_target" json:"targetId"`
+	TargetSeq   uint64     `gorm:"not null;default:0;index:idx_target" json:"targetSeq"`
+	OwnerID     uint64     `gorm:"not null;default:0" json:"ownerId"`
+	Snapshot    string     `gorm:"type:varchar(1000);not null;default:''" json:"snapshot"`
+	ReportCount int        `gorm:"not null;default:0" json:"reportCount"`
+	Priority    int        `gorm:"not null;default:0;index:idx_status_priority" json:"priority"`
+	Status      int8       `gorm:"not null;default:0;index:idx_target;index:idx_status_priority" json:"status"`
+	ClaimedBy   uint64     `gorm:"not null;default:0" json:"claimedBy"`
+	ClaimedAt   *time.Time `json:"claimedAt"`
+	HandledBy   uint64     `gorm:"not null;default:0" json:"handledBy"`
+	HandleNote  string     `gorm:"type:varchar(500);not null;default:''" json:"handleNote"`
+	HandledAt   *time.Time `json:"handledAt"`
+	CreatedAt   time.Time  `gorm:"index:idx_status_priority" json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+
+	Reports []*Report `gorm:"foreignKey:CaseID;references:ID" json:"reports,omitempty"`
+}
+
+func (ReportCase) TableName() string {
+	return "report_cases"
+}
+
+// Report 单条举报
+type Report struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	CaseID     uint64    `gorm:"not null;uniqueIndex:uk_case_reporter" json:"caseId"`
+	ReporterID uint64    `gorm:"not null;uniqueIndex:uk_case_reporter;index:idx_reporter" json:"reporterId"`
+	Reason     int8      `gorm:"not null" json:"reason"`
+	Detail     string    `gorm:"type:varchar(500);not null;default:''" json:"detail"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (Report) TableName() string {
+	return "reports"
+}
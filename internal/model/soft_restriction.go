@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// 软处置类型
+const (
+	SoftRestrictionShadowBan   int8 = 1 // 影子封禁：用户内容仅自己可见，不进入推荐、搜索、标签页与他人评论区
+	SoftRestrictionLimitedPost int8 = 2 // 限流：笔记不进入推荐流，可通过链接直接访问
+)
+
+// SoftRestriction 审核员对用户或笔记施加的限时软处置
+type SoftRestriction struct {
+	ID        uint64     `gorm:"primaryKey" json:"id"`
+	Kind      int8       `gorm:"not null;index:idx_kind_target;index:idx_kind_expires" json:"kind"`
+	TargetID  uint64     `gorm:"not null;index:idx_kind_target" json:"targetId"`
+	Reason    string     `gorm:"type:varchar(200);not null;default:''" json:"reason"`
+	CreatedBy uint64     `gorm:"not null" json:"createdBy"`
+	ExpiresAt time.Time  `gorm:"not null;index:idx_kind_expires" json:"expiresAt"`
+	LiftedAt  *time.Time `gorm:"index:idx_kind_target;index:idx_kind_expires" json:"liftedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+func (SoftRestriction) TableName() string {
+	return "soft_restrictions"
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+// 垃圾行为复核状态
+const (
+	SpamRiskPending   int8 = 1 // 待复核
+	SpamRiskCleared   int8 = 2 // 误判已放行
+	SpamRiskConfirmed int8 = 3 // 确认垃圾行为
+)
+
+// 垃圾行为限频等级
+const (
+	SpamThrottleNone  int8 = 0
+	SpamThrottleLight int8 = 1 // 轻度：点赞、关注、评论各自限频
+	SpamThrottleHeavy int8 = 2 // 重度：更长的操作间隔
+)
+
+// SpamSignals 滑动窗口内的行为信号
+type SpamSignals struct {
+	BurstLikes        int `json:"burst_likes"`        // 短时点赞数
+	FollowChurn       int `json:"follow_churn"`       // 关注后短时取关数
+	DuplicateComments int `json:"duplicate_comments"` // 跨笔记重复评论数
+	NewAccountLinks   int `json:"new_account_links"`  // 新账号发布的含链接评论数
+}
+
+// SpamRisk 风险分超过复核线的用户，每个用户一条
+type SpamRisk struct {
+	ID                uint64     `gorm:"primaryKey" json:"id"`
+	UserID            uint64     `gorm:"not null;uniqueIndex:uk_user" json:"userId"`
+	Score             int        `gorm:"not null;default:0;index:idx_state_score" json:"score"`
+	PeakScore         int        `gorm:"not null;default:0" json:"peakScore"`
+	BurstLikes        int        `gorm:"not null;default:0" json:"burstLikes"`
+	FollowChurn       int        `gorm:"not null;default:0" json:"followChurn"`
+	DuplicateComments int        `gorm:"not null;default:0" json:"duplicateComments"`
+	NewAccountLinks   int        `gorm:"not null;default:0" json:"newAccountLinks"`
+	ThrottleLevel     int8       `gorm:"not null;default:0" json:"throttleLevel"`
+	State             int8       `gorm:"not null;default:1;index:idx_state_score" json:"state"`
+	ReviewedBy        uint64     `gorm:"not null;default:0" json:"reviewedBy"`
+	ReviewedAt        *time.Time `json:"reviewedAt"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+func (SpamRisk) TableName() string {
+	return "spam_risks"
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+)
+
+// 违规来源
+const (
+	StrikeSourcePostAudit    int8 = 1 // 笔记审核拒绝
+	StrikeSourceCommentAudit int8 = 2 // 评论审核拒绝
+	StrikeSourceReport       int8 = 3 // 举报成立
+)
+
+// 处罚等级，数值越大越严厉
+const (
+	PenaltyNone      int8 = 0
+	PenaltyCooldown  int8 = 1 // 发布冷却，限制发布频率
+	PenaltyMute      int8 = 2 // 禁言，禁止发布笔记、评论与私信
+	PenaltyBan       int8 = 3 // 限时封禁
+	PenaltyPermanent int8 = 4 // 永久封禁
+)
+
+// UserStrike 用户违规记录，同一来源对象只记一次
+type UserStrike struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	UserID    uint64    `gorm:"not null;index:idx_user_created" json:"userId"`
+	Source    int8      `gorm:"not null;uniqueIndex:uk_source_target" json:"source"`
+	TargetID  uint64    `gorm:"not null;uniqueIndex:uk_source_target" json:"targetId"`
+	Reason    string    `gorm:"type:varchar(200);not null;default:''" json:"reason"`
+	Revoked   bool      `gorm:"not null;default:false" json:"revoked"`
+	CreatedAt time.Time `gorm:"index:idx_user_created" json:"createdAt"`
+}
+
+func (UserStrike) TableName() string {
+	return "user_strikes"
+}
+
+// UserPenalty 违规累计触发的处罚
+type UserPenalty struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	UserID      uint64     `gorm:"not null;index:idx_user_level" json:"userId"`
+	Level       int8       `gorm:"not null" json:"level"`
+	StrikeCount int        `gorm:"not null;default:0" json:"strikeCount"`
+	Reason      string     `gorm:"type:varchar(200);not null;default:''" json:"reason"`
+	ExpiresAt   *time.Time `gorm:"index:idx_expires" json:"expiresAt"`
+	LiftedAt    *time.Time `gorm:"index:idx_user_level;index:idx_expires" json:"liftedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+func (UserPenalty) TableName() string {
+	return "user_penalties"
+}
+
+// UserRestriction 缓存在 Redis 中的当前生效处罚，供鉴权中间件读取
+type UserRestriction struct {
+	Level     int8  `json:"level"`
+	ExpiresAt int64 `json:"expires_at"` // 秒级时间戳，永久封禁为 0
+}
@@ -7,10 +7,13 @@ const (
 )
 
 const (
-	PostStatusNormal = 1
+	PostStatusPending = 0
+	PostStatusNormal  = 1
+	PostStatusDeny    = 3
 )
 
 const (
-	DefaultAvatarURL = "default_avatar.png"
-	BaseURL          = "post_base_url"
+	DefaultAvatarURL      = "default_avatar.png"
+	DefaultNicknamePrefix = "用户"
+	BaseURL               = "post_base_url"
 )
@@ -15,6 +15,7 @@ const (
 	UserMetrics30DaysKey        = "user:metrics:30days:"
 	UserInterestKey             = "user:interest:"
 	UserInterestDirtyKey        = "user:interest:dirty"
+	UserCreatorDirtyKey         = "user:creator:dirty"
 	UserViewedKey               = "user:viewed:"
 	PostDirtyKey                = "post:dirty"
 	PostLikeKey                 = "post:like:"
@@ -24,8 +25,8 @@ const (
 	PostCommentLikeKey          = "post:comment:like:"
 	PostCommentLikeUserSetKey   = "post:comment:like:user:"
 	PostCommentLikeDirtyKey     = "post:comment:like:dirty"
+	PostCommentHotKey           = "post:comment:hot:"
 	PostViewKey                 = "post:view:"
-	PostReportKey               = "post:report:"
 	PostMetrics7DaysKey         = "post:metrics:7days:"
 	PostMetrics30DaysKey        = "post:metrics:30days:"
 	UserContentMetrics7DaysKey  = "user_content:metrics:7days:"
@@ -34,11 +35,46 @@ const (
 	SysBoxUnreadNotifyChannel   = "sysbox:unread:"
 	MediaTempKey                = "media:temp"
 	WebSocketTicketKey          = "ws:ticket:"
+	RecommendClickKey           = "recommend:click:"
+	OnboardingTagsKey           = "onboarding:tags"
+	SearchHistoryKey            = "search:history:"
+	SearchHotKey                = "search:hot"
+	SearchHotPinnedKey          = "search:hot:pinned"
+	SearchHotBlockedKey         = "search:hot:blocked"
+	SearchHotDedupKey           = "search:hot:dedup:"
+	ESShadowIndexKey            = "es:shadow:"
+	ESReindexProgressKey        = "es:reindex:progress:"
+	ESReconcileReportKey        = "es:reconcile:report"
+	ModerationDecisionKey       = "moderation:decision:"
+	BlocklistReloadChannel      = "moderation:blocklist:reload"
+	MediaHashReloadChannel      = "moderation:media_hash:reload"
+	UserRestrictionKey          = "user:restriction:"
+	UserWriteCooldownKey        = "user:write:cooldown:"
+	ShadowBanUsersKey           = "moderation:shadow_ban:users"
+	LimitedPostsKey             = "moderation:limited:posts"
+	SpamLikeKey                 = "spam:like:"
+	SpamFollowKey               = "spam:follow:"
+	SpamChurnKey                = "spam:churn:"
+	SpamCommentTextKey          = "spam:comment:text:"
+	SpamDuplicateKey            = "spam:comment:dup:"
+	SpamLinkKey                 = "spam:comment:link:"
+	SpamThrottleKey             = "spam:throttle:"
+	SpamThrottleGateKey         = "spam:throttle:gate:"
+	SpamClearedKey              = "spam:cleared:"
+	SpamRiskSyncKey             = "spam:risk:sync:"
+	ReactionCountKey            = "reaction:count:"
+	ReactionDirtyKey            = "reaction:dirty"
+	PostDiscussionSummaryKey    = "post:discussion:summary:"
+	PostDiscussionLockKey       = "post:discussion:lock:"
 )
 
 const (
 	UserDetailLock       = "user:detail:lock:"
 	UserDetailESLock     = "user:detail:es:lock:"
 	UserInterestInitLock = "lock:interest:init:"
-	ReportLock           = "report:lock:"
+	ESReindexLock        = "lock:es:reindex:"
+	ESReconcileLock      = "lock:es:reconcile"
+	AppealLock           = "lock:appeal:"
+	StrikeLock           = "lock:strike:"
+	ReauditLock          = "lock:reaudit:"
 )
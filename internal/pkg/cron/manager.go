@@ -14,6 +14,12 @@ type Manager struct {
 	userInterestJob *job.UserInterestJob
 	postCommentJob  *job.PostCommentJob
 	mediaCleanJob   *job.MediaCleanupJob
+	hotSearchJob    *job.HotSearchJob
+	reconcileJob    *job.SearchReconcileJob
+	creatorJob      *job.CreatorProfileJob
+	penaltyJob      *job.PenaltyExpireJob
+	restrictionJob  *job.SoftRestrictionSyncJob
+	reactionJob     *job.ReactionJob
 }
 
 func NewCronManager(
@@ -22,6 +28,12 @@ func NewCronManager(
 	userInterestJob *job.UserInterestJob,
 	postCommentJob *job.PostCommentJob,
 	mediaCleanJob *job.MediaCleanupJob,
+	hotSearchJob *job.HotSearchJob,
+	reconcileJob *job.SearchReconcileJob,
+	creatorJob *job.CreatorProfileJob,
+	penaltyJob *job.PenaltyExpireJob,
+	restrictionJob *job.SoftRestrictionSyncJob,
+	reactionJob *job.ReactionJob,
 
 ) *Manager {
 	return &Manager{
@@ -31,6 +43,12 @@ func NewCronManager(
 		userInterestJob: userInterestJob,
 		postCommentJob:  postCommentJob,
 		mediaCleanJob:   mediaCleanJob,
+		hotSearchJob:    hotSearchJob,
+		reconcileJob:    reconcileJob,
+		creatorJob:      creatorJob,
+		penaltyJob:      penaltyJob,
+		restrictionJob:  restrictionJob,
+		reactionJob:     reactionJob,
 	}
 }
 
@@ -51,6 +69,24 @@ func (s *Manager) RegisterJobs() error {
 	if _, err := s.engine.AddJob("@every 1h", s.mediaCleanJob); err != nil {
 		return err
 	}
+	if _, err := s.engine.AddJob("@every 1h", s.hotSearchJob); err != nil {
+		return err
+	}
+	if _, err := s.engine.AddJob("@every 6h", s.reconcileJob); err != nil {
+		return err
+	}
+	if _, err := s.engine.AddJob("@every 1m", s.creatorJob); err != nil {
+		return err
+	}
+	if _, err := s.engine.AddJob("@every 1m", s.penaltyJob); err != nil {
+		return err
+	}
+	if _, err := s.engine.AddJob("@every 5m", s.restrictionJob); err != nil {
+		return err
+	}
+	if _, err := s.engine.AddJob("@every 1m", s.reactionJob); err != nil {
+		return err
+	}
 	return nil
 }
 
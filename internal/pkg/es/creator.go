@@ -0,0 +1,167 @@
+package es
+
+import (
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/util"
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/goccy/go-json"
+)
+
+const (
+	creatorMainTagsSize = 5
+	creatorAITagsSize   = 20
+	creatorMainTagsAgg  = "creator_main_tags"
+	creatorAITagsAgg    = "creator_ai_tags"
+
+	// creatorKnnBoost 用户检索中创作者向量召回的权重
+	creatorKnnBoost = 5.0
+	// creatorKnnSimilarity 向量召回的最低相似度，过滤语义无关的创作者
+	creatorKnnSimilarity = 0.6
+)
+
+// CreatorTags 创作者已发布笔记的聚合标签，按出现次数降序
+type CreatorTags struct {
+	MainTags []string
+	AITags   []string
+}
+
+// All 合并主标签与 AI 标签并去重
+func (t *CreatorTags) All() []string {
+	seen := make(map[string]struct{}, len(t.MainTags)+len(t.AITags))
+	res := make([]string, 0, len(t.MainTags)+len(t.AITags))
+	for _, tag := range append(append([]string{}, t.MainTags...), t.AITags...) {
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		res = append(res, tag)
+	}
+	return res
+}
+
+// AggregateCreatorTags 聚合用户已发布笔记的 MainTag 与 AITags
+func (s *PostRepoImpl) AggregateCreatorTags(ctx context.Context, userID uint64) (*CreatorTags, error) {
+	resp, err := s.client.Search().Index(PostIndex).
+		Size(0).
+		Query(&types.Query{Bool: &types.BoolQuery{Filter: []types.Query{
+			{Term: map[string]types.TermQuery{"user_id": {Value: userID}}},
+			{Term: map[string]types.TermQuery{"status": {Value: consts.PostStatusNormal}}},
+		}}}).
+		Aggregations(map[string]types.Aggregations{
+			creatorMainTagsAgg: {Terms: &types.TermsAggregation{Field: util.PtrStr("main_tag"), Size: util.PtrInt(creatorMainTagsSize)}},
+			creatorAITagsAgg:   {Terms: &types.TermsAggregation{Field: util.PtrStr("ai_tags"), Size: util.PtrInt(creatorAITagsSize)}},
+		}).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreatorTags{
+		MainTags: bucketKeys(parseTermsBuckets(resp.Aggregations[creatorMainTagsAgg])),
+		AITags:   bucketKeys(parseTermsBuckets(resp.Aggregations[creatorAITagsAgg])),
+	}, nil
+}
+
+// GetUserById 获取用户文档，不存在时返回 nil
+func (s *UserRepoImpl) GetUserById(ctx context.Context, id uint64) (*UserES, error) {
+	docID := strconv.FormatUint(id, 10)
+	result, err := s.client.Get(UserIndex, docID).Do(ctx)
+	if err != nil {
+		var e *types.ElasticsearchError
+		if errors.As(err, &e) {
+			if e.Status == NotFoundCode {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+	if !result.Found || result.Source_ == nil {
+		return nil, nil
+	}
+	var user UserES
+	if err = json.Unmarshal(result.Source_, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SimilarCreators 按创作者向量召回相似用户，排除自身
+func (s *UserRepoImpl) SimilarCreators(ctx context.Context, userID uint64, vector []float32, size int) ([]*UserES, error) {
+	if len(vector) == 0 {
+		return []*UserES{}, nil
+	}
+
+	resp, err := s.client.Search().
+		Index(UserIndex).
+		Knn(types.KnnSearch{
+			Field:         "creator_vector",
+			QueryVector:   vector,
+			K:             util.PtrInt(size),
+			NumCandidates: util.PtrInt(size * 10),
+			Filter: []types.Query{{Bool: &types.BoolQuery{MustNot: []types.Query{
+				{Term: map[string]types.TermQuery{"id": {Value: userID}}},
+			}}}},
+		}).
+		Source_(&types.SourceFilter{Excludes: []string{"creator_vector"}}).
+		Size(size).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseUserHits(resp.Hits.Hits), nil
+}
+
+// UpdateCreatorProfile 局部更新创作者标签与向量，用户文档不存在时忽略
+func (s *UserRepoImpl) UpdateCreatorProfile(ctx context.Context, id uint64, tags []string, vector []float32) error {
+	docID := strconv.FormatUint(id, 10)
+	doc, err := json.Marshal(map[string]any{
+		"creator_tags":   tags,
+		"creator_vector": vector,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, index := range writeIndices(ctx, UserIndex) {
+		_, err = s.client.Update(index, docID).Doc(json.RawMessage(doc)).Do(ctx)
+		if err != nil {
+			var e *types.ElasticsearchError
+			if errors.As(err, &e) {
+				if e.Status == NotFoundCode || e.Status == ConflictCode {
+					continue
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func bucketKeys(buckets []FacetBucket) []string {
+	keys := make([]string, 0, len(buckets))
+	for _, b := range buckets {
+		if b.Key != "" {
+			keys = append(keys, b.Key)
+		}
+	}
+	return keys
+}
+
+func parseUserHits(hits []types.Hit) []*UserES {
+	users := make([]*UserES, 0, len(hits))
+	for _, hit := range hits {
+		if hit.Source_ == nil {
+			continue
+		}
+		var user UserES
+		if err := json.Unmarshal(hit.Source_, &user); err != nil {
+			continue
+		}
+		users = append(users, &user)
+	}
+	return users
+}
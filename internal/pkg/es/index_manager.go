@@ -0,0 +1,330 @@
+package es
+
+import (
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	log "log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/versiontype"
+	"github.com/goccy/go-json"
+)
+
+// mapping 文件，位于配置的 mapping 目录下
+const (
+	UserMappingFile = "024_user_search.json"
+	PostMappingFile = "025_post_search.json"
+)
+
+const (
+	// ShadowRefreshInterval 写入方缓存影子索引的时间，开启双写后需等待该时间再开始回填
+	ShadowRefreshInterval = 3 * time.Second
+	// ShadowIndexTTL 影子索引标记的过期时间，重建过程中持续续期，进程异常退出后自动关闭双写
+	ShadowIndexTTL = 10 * time.Minute
+)
+
+// VersionedDoc 带外部版本号的文档
+type VersionedDoc struct {
+	ID      uint64
+	Version int64
+	Source  json.RawMessage
+}
+
+// BulkDoc 批量写入的文档，Version 为外部版本号
+type BulkDoc struct {
+	ID      uint64
+	Version int64
+	Doc     any
+}
+
+// IndexManager 版本化索引管理：读写均走别名，重建期间写入同时落到影子索引
+type IndexManager interface {
+	ResolveAlias(ctx context.Context, alias string) (string, bool, error)
+	CreateVersionedIndex(ctx context.Context, alias string, mapping []byte) (string, error)
+	GetVectorDims(ctx context.Context, index, field string) (int, error)
+	SwapAlias(ctx context.Context, alias, oldIndex, newIndex string, oldIsAlias bool) error
+	DeleteStaleIndices(ctx context.Context, alias, keepIndex string) ([]string, error)
+	RefreshIndex(ctx context.Context, index string) error
+	SetShadowIndex(ctx context.Context, alias, index string) error
+	ClearShadowIndex(ctx context.Context, alias string) error
+	MGet(ctx context.Context, index string, ids []uint64) (map[uint64]*VersionedDoc, error)
+	BulkIndex(ctx context.Context, index string, docs []*BulkDoc) (int, error)
+	RangeIDs(ctx context.Context, index string, afterID, maxID uint64, size int) ([]uint64, error)
+}
+
+type IndexManagerImpl struct {
+	client *elasticsearch.TypedClient
+}
+
+func NewIndexManager(client *elasticsearch.TypedClient) IndexManager {
+	return &IndexManagerImpl{client: client}
+}
+
+// ResolveAlias 解析别名当前指向的索引；别名尚未建立（旧版直接使用固定索引名）时返回该索引本身
+func (s *IndexManagerImpl) ResolveAlias(ctx context.Context, alias string) (string, bool, error) {
+	exists, err := s.client.Indices.ExistsAlias(alias).Do(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	if !exists {
+		return alias, false, nil
+	}
+
+	resp, err := s.client.Indices.GetAlias().Name(alias).Do(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	for index, aliases := range resp {
+		if a, ok := aliases.Aliases[alias]; ok && (a.IsWriteIndex == nil || *a.IsWriteIndex) {
+			return index, true, nil
+		}
+	}
+	for index := range resp {
+		return index, true, nil
+	}
+	return "", false, fmt.Errorf("alias %s has no index", alias)
+}
+
+// CreateVersionedIndex 按 mapping 创建 {alias}_v{N} 索引，N 为已有最大版本加一
+func (s *IndexManagerImpl) CreateVersionedIndex(ctx context.Context, alias string, mapping []byte) (string, error) {
+	prefix := alias + "_v"
+	existing, err := s.client.Indices.Get(prefix + "*").AllowNoIndices(true).Do(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	version := 0
+	for name := range existing {
+		if n, err := strconv.Atoi(strings.TrimPrefix(name, prefix)); err == nil && n > version {
+			version = n
+		}
+	}
+
+	index := prefix + strconv.Itoa(version+1)
+	if _, err = s.client.Indices.Create(index).Raw(bytes.NewReader(mapping)).Do(ctx); err != nil {
+		return "", err
+	}
+	return index, nil
+}
+
+// GetVectorDims 获取索引向量字段的维度，无该字段时返回 0
+func (s *IndexManagerImpl) GetVectorDims(ctx context.Context, index, field string) (int, error) {
+	resp, err := s.client.Indices.GetMapping().Index(index).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, record := range resp {
+		if prop, ok := record.Mappings.Properties[field].(*types.DenseVectorProperty); ok && prop.Dims != nil {
+			return *prop.Dims, nil
+		}
+	}
+	return 0, nil
+}
+
+// SwapAlias 原子切换别名到新索引；旧版固定索引名与别名同名，需在同一请求中删除旧索引
+func (s *IndexManagerImpl) SwapAlias(ctx context.Context, alias, oldIndex, newIndex string, oldIsAlias bool) error {
+	isWriteIndex := true
+	actions := []types.IndicesAction{
+		{Add: &types.AddAction{Index: &newIndex, Alias: &alias, IsWriteIndex: &isWriteIndex}},
+	}
+	if oldIsAlias {
+		actions = append(actions, types.IndicesAction{Remove: &types.RemoveAction{Index: &oldIndex, Alias: &alias}})
+	} else if oldIndex != "" {
+		actions = append(actions, types.IndicesAction{RemoveIndex: &types.RemoveIndexAction{Index: &oldIndex}})
+	}
+
+	resp, err := s.client.Indices.UpdateAliases().Actions(actions...).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if !resp.Acknowledged {
+		return errors.New("update aliases not acknowledged")
+	}
+	return nil
+}
+
+// DeleteStaleIndices 删除别名下除 keepIndex 外的所有 {alias}_v{N} 索引，包括已被替换和重建失败的版本
+func (s *IndexManagerImpl) DeleteStaleIndices(ctx context.Context, alias, keepIndex string) ([]string, error) {
+	existing, err := s.client.Indices.Get(alias + "_v*").AllowNoIndices(true).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stale := make([]string, 0, len(existing))
+	for name := range existing {
+		if name != keepIndex {
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) == 0 {
+		return nil, nil
+	}
+	if _, err = s.client.Indices.Delete(strings.Join(stale, ",")).Do(ctx); err != nil {
+		return nil, err
+	}
+	return stale, nil
+}
+
+func (s *IndexManagerImpl) RefreshIndex(ctx context.Context, index string) error {
+	_, err := s.client.Indices.Refresh().Index(index).Do(ctx)
+	return err
+}
+
+// SetShadowIndex 开启或续期双写，写入别名的同时写入影子索引
+func (s *IndexManagerImpl) SetShadowIndex(ctx context.Context, alias, index string) error {
+	return redis.SetWithExpiration(ctx, consts.ESShadowIndexKey+alias, index, ShadowIndexTTL)
+}
+
+// ClearShadowIndex 关闭双写
+func (s *IndexManagerImpl) ClearShadowIndex(ctx context.Context, alias string) error {
+	return redis.DeleteKey(ctx, consts.ESShadowIndexKey+alias)
+}
+
+// MGet 批量获取文档源与外部版本号，不存在的文档不返回
+func (s *IndexManagerImpl) MGet(ctx context.Context, index string, ids []uint64) (map[uint64]*VersionedDoc, error) {
+	res := make(map[uint64]*VersionedDoc, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	docIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		docIDs = append(docIDs, strconv.FormatUint(id, 10))
+	}
+
+	resp, err := s.client.Mget().Index(index).Ids(docIDs...).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range resp.Docs {
+		doc, ok := item.(*types.GetResult)
+		if !ok || !doc.Found {
+			continue
+		}
+		id, err := strconv.ParseUint(doc.Id_, 10, 64)
+		if err != nil {
+			continue
+		}
+		v := &VersionedDoc{ID: id, Source: doc.Source_}
+		if doc.Version_ != nil {
+			v.Version = *doc.Version_
+		}
+		res[id] = v
+	}
+	return res, nil
+}
+
+// BulkIndex 按外部版本号批量写入，版本冲突说明双写已写入更新的数据，不计为失败
+func (s *IndexManagerImpl) BulkIndex(ctx context.Context, index string, docs []*BulkDoc) (int, error) {
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	req := s.client.Bulk().Index(index)
+	for _, d := range docs {
+		id := strconv.FormatUint(d.ID, 10)
+		version := d.Version
+		if err := req.IndexOp(types.IndexOperation{
+			Id_:         &id,
+			Version:     &version,
+			VersionType: &versiontype.External,
+		}, d.Doc); err != nil {
+			return 0, err
+		}
+	}
+
+	resp, err := req.Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Errors {
+		return 0, nil
+	}
+
+	failed := 0
+	for _, item := range resp.Items {
+		for _, r := range item {
+			if r.Error != nil && r.Status != ConflictCode {
+				failed++
+				log.WarnContext(ctx, "bulk index failed", "index", index, "id", r.Id_, "reason", r.Error.Reason)
+			}
+		}
+	}
+	return failed, nil
+}
+
+// RangeIDs 按 ID 升序获取 (afterID, maxID] 区间内的文档 ID，maxID 为 0 时不设上界
+func (s *IndexManagerImpl) RangeIDs(ctx context.Context, index string, afterID, maxID uint64, size int) ([]uint64, error) {
+	gt := types.Float64(afterID)
+	idRange := types.NumberRangeQuery{Gt: &gt}
+	if maxID > 0 {
+		lte := types.Float64(maxID)
+		idRange.Lte = &lte
+	}
+
+	resp, err := s.client.Search().
+		Index(index).
+		Query(&types.Query{Range: map[string]types.RangeQuery{"id": idRange}}).
+		Sort(types.SortOptions{SortOptions: map[string]types.FieldSort{
+			"id": {Order: &sortorder.Asc},
+		}}).
+		Source_(false).
+		Size(size).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(resp.Hits.Hits))
+	for _, hit := range resp.Hits.Hits {
+		if hit.Id_ == nil {
+			continue
+		}
+		if id, err := strconv.ParseUint(*hit.Id_, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+var shadowCache sync.Map
+
+type shadowEntry struct {
+	index    string
+	expireAt time.Time
+}
+
+// shadowIndex 获取别名当前的影子索引，本地缓存 ShadowRefreshInterval
+func shadowIndex(ctx context.Context, alias string) string {
+	if v, ok := shadowCache.Load(alias); ok {
+		entry := v.(shadowEntry)
+		if time.Now().Before(entry.expireAt) {
+			return entry.index
+		}
+	}
+	index, err := redis.GetValue(ctx, consts.ESShadowIndexKey+alias)
+	if err != nil {
+		log.WarnContext(ctx, "get shadow index failed", "alias", alias, "err", err)
+		index = ""
+	}
+	shadowCache.Store(alias, shadowEntry{index: index, expireAt: time.Now().Add(ShadowRefreshInterval)})
+	return index
+}
+
+// writeIndices 写入目标：别名及其影子索引
+func writeIndices(ctx context.Context, alias string) []string {
+	if shadow := shadowIndex(ctx, alias); shadow != "" && shadow != alias {
+		return []string{alias, shadow}
+	}
+	return []string{alias}
+}
@@ -0,0 +1,65 @@
+package es
+
+import (
+	"Cornerstone/internal/pkg/util"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/operator"
+)
+
+// initialsMaxLen 首字母匹配仅对短查询生效，避免长句产生误召回
+const initialsMaxLen = 10
+
+// normalizedQueries 基于简体归一化字段的简体、全拼与首字母三路匹配，
+// 用户输入繁体、拼音或拼音首字母时均可命中。fields 为归一化字段，可带 ^boost 后缀
+func normalizedQueries(text string, boost float32, fields ...string) []types.Query {
+	simplified := util.ToSimplified(strings.TrimSpace(text))
+	if simplified == "" {
+		return nil
+	}
+
+	queries := []types.Query{
+		{
+			MultiMatch: &types.MultiMatchQuery{
+				Query:  simplified,
+				Fields: fields,
+				Boost:  util.PtrFloat32(boost),
+			},
+		},
+		{
+			MultiMatch: &types.MultiMatchQuery{
+				Query:    simplified,
+				Fields:   subFields(fields, "pinyin"),
+				Operator: &operator.And,
+				Boost:    util.PtrFloat32(boost * 0.5),
+			},
+		},
+	}
+
+	if !strings.ContainsAny(simplified, " \t") && utf8.RuneCountInString(simplified) <= initialsMaxLen {
+		queries = append(queries, types.Query{
+			MultiMatch: &types.MultiMatchQuery{
+				Query:  simplified,
+				Fields: subFields(fields, "initials"),
+				Boost:  util.PtrFloat32(boost * 0.4),
+			},
+		})
+	}
+	return queries
+}
+
+// subFields 将 field^boost 转换为 field.sub^boost
+func subFields(fields []string, sub string) []string {
+	res := make([]string, 0, len(fields))
+	for _, f := range fields {
+		name, weight, found := strings.Cut(f, "^")
+		if found {
+			res = append(res, name+"."+sub+"^"+weight)
+		} else {
+			res = append(res, name+"."+sub)
+		}
+	}
+	return res
+}
@@ -1,6 +1,9 @@
 package es
 
-import "time"
+import (
+	"Cornerstone/internal/pkg/util"
+	"time"
+)
 
 // PostES 写入 ES 的完整文档
 type PostES struct {
@@ -16,15 +19,37 @@ type PostES struct {
 	AITags        []string      `json:"ai_tags"`
 	AISummary     string        `json:"ai_summary"`
 	Media         []PostMediaES `json:"media"`
+	MediaTypes    []string      `json:"media_types"`
 	UserNickname  string        `json:"user_nickname"`
-	UserAvatar    string        `json:"user_avatar"`
-	LikesCount    int           `json:"likes_count"`
-	CommentsCount int           `json:"comments_count"`
-	CollectsCount int           `json:"collects_count"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
-
-	Sort []interface{} `json:"-"`
+
+	// 简体归一化字段，拼音与首字母由 mapping 子字段生成
+	TitleSimplified string   `json:"title_simplified,omitempty"`
+	TagsSimplified  []string `json:"tags_simplified,omitempty"`
+
+	UserAvatar    string    `json:"user_avatar"`
+	LikesCount    int       `json:"likes_count"`
+	CommentsCount int       `json:"comments_count"`
+	CollectsCount int       `json:"collects_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	Sort           []interface{}  `json:"-"`
+	Score          float64        `json:"-"` // 检索得分
+	MatchedQueries []string       `json:"-"` // 命中的具名查询
+	Highlight      *PostHighlight `json:"-"` // 检索命中片段
+}
+
+// FillNormalized 写入前生成简体归一化字段
+func (p *PostES) FillNormalized() {
+	p.TitleSimplified = util.ToSimplified(p.Title)
+
+	tags := make([]string, 0, len(p.UserTags)+len(p.AITags)+1)
+	if p.MainTag != "" {
+		tags = append(tags, p.MainTag)
+	}
+	tags = append(tags, p.UserTags...)
+	tags = append(tags, p.AITags...)
+	p.TagsSimplified = util.ToSimplifiedSlice(tags)
 }
 
 // PostMediaES 对应 Mapping 中的 media 对象
@@ -0,0 +1,44 @@
+package es
+
+import (
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// Exclusion 软处置排除条件，由调用方按查看者裁剪后传入
+type Exclusion struct {
+	UserIDs []uint64 // 影子封禁的作者
+	PostIDs []uint64 // 限流的帖子
+}
+
+// IsEmpty 无任何排除条件
+func (e *Exclusion) IsEmpty() bool {
+	return e == nil || (len(e.UserIDs) == 0 && len(e.PostIDs) == 0)
+}
+
+// appendTo 将排除条件以 must_not 子句追加到 filter 列表，filter 上下文不影响打分
+func (e *Exclusion) appendTo(filters []types.Query) []types.Query {
+	if e.IsEmpty() {
+		return filters
+	}
+
+	mustNot := make([]types.Query, 0, 2)
+	if len(e.UserIDs) > 0 {
+		mustNot = append(mustNot, types.Query{Terms: &types.TermsQuery{
+			TermsQuery: map[string]types.TermsQueryField{"user_id": toFieldValues(e.UserIDs)},
+		}})
+	}
+	if len(e.PostIDs) > 0 {
+		mustNot = append(mustNot, types.Query{Terms: &types.TermsQuery{
+			TermsQuery: map[string]types.TermsQueryField{"id": toFieldValues(e.PostIDs)},
+		}})
+	}
+	return append(filters, types.Query{Bool: &types.BoolQuery{MustNot: mustNot}})
+}
+
+func toFieldValues(ids []uint64) []types.FieldValue {
+	values := make([]types.FieldValue, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	return values
+}
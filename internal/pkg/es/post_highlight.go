@@ -0,0 +1,188 @@
+package es
+
+import (
+	"Cornerstone/internal/pkg/util"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/highlighterencoder"
+)
+
+// 高亮标签
+const (
+	HighlightPreTag  = "<em>"
+	HighlightPostTag = "</em>"
+)
+
+const (
+	// snippetSize 正文片段长度（字符）
+	snippetSize = 80
+	// snippetCount 正文片段最大数量
+	snippetCount = 3
+)
+
+// PostHighlight 检索命中片段，内容已做 HTML 转义，仅命中词包裹高亮标签
+type PostHighlight struct {
+	Title    string
+	Snippets []string
+}
+
+// buildHighlight 文本召回的高亮配置：标题整段高亮，正文按片段返回
+func buildHighlight() *types.Highlight {
+	return &types.Highlight{
+		Encoder:  &highlighterencoder.Html,
+		PreTags:  []string{HighlightPreTag},
+		PostTags: []string{HighlightPostTag},
+		Fields: map[string]types.HighlightField{
+			"title": {NumberOfFragments: util.PtrInt(0)},
+			"plain_content": {
+				FragmentSize:      util.PtrInt(snippetSize),
+				NumberOfFragments: util.PtrInt(snippetCount),
+			},
+		},
+	}
+}
+
+// parseHighlight 解析命中文档的高亮结果
+func parseHighlight(raw map[string][]string) *PostHighlight {
+	if len(raw) == 0 {
+		return nil
+	}
+	h := &PostHighlight{Snippets: raw["plain_content"]}
+	if titles := raw["title"]; len(titles) > 0 {
+		h.Title = titles[0]
+	}
+	if h.Title == "" && len(h.Snippets) == 0 {
+		return nil
+	}
+	return h
+}
+
+// fillPassages 为仅由向量召回、没有高亮结果的帖子补充最佳匹配段落
+func fillPassages(posts []*PostES, queryText string) {
+	if queryText == "" {
+		return
+	}
+	terms := queryTerms(queryText)
+	for _, post := range posts {
+		if post.Highlight != nil {
+			continue
+		}
+		if passage := BestPassage(post.PlainContent, terms); passage != "" {
+			post.Highlight = &PostHighlight{Snippets: []string{passage}}
+		}
+	}
+}
+
+// BestPassage 将正文按句切分并拼成不超过片段长度的段落，取命中查询词最多的一段，均未命中时取开头
+func BestPassage(content string, terms []string) string {
+	passages := splitPassages(content)
+	if len(passages) == 0 {
+		return ""
+	}
+
+	best, bestScore := passages[0], 0
+	for _, p := range passages {
+		lower := strings.ToLower(p)
+		score := 0
+		for _, t := range terms {
+			if strings.Contains(lower, t) {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = p, score
+		}
+	}
+	return html.EscapeString(util.TruncateRunes(best, snippetSize))
+}
+
+// splitPassages 按句末标点切句，相邻短句合并至片段长度
+func splitPassages(content string) []string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil
+	}
+
+	var (
+		passages []string
+		current  []rune
+		sentence []rune
+	)
+	flush := func() {
+		if s := strings.TrimSpace(string(current)); s != "" {
+			passages = append(passages, s)
+		}
+		current = current[:0]
+	}
+	appendSentence := func() {
+		if len(current) > 0 && len(current)+len(sentence) > snippetSize {
+			flush()
+		}
+		current = append(current, sentence...)
+		sentence = sentence[:0]
+	}
+
+	for _, r := range content {
+		sentence = append(sentence, r)
+		switch r {
+		case '。', '！', '？', '；', '!', '?', ';', '\n':
+			appendSentence()
+		}
+	}
+	if len(sentence) > 0 {
+		appendSentence()
+	}
+	flush()
+	return passages
+}
+
+// queryTerms 拆分查询词：英文数字按词，中文按二元组，单字中文保留单字
+func queryTerms(query string) []string {
+	seen := make(map[string]struct{})
+	terms := make([]string, 0)
+	add := func(t string) {
+		if t == "" {
+			return
+		}
+		if _, ok := seen[t]; ok {
+			return
+		}
+		seen[t] = struct{}{}
+		terms = append(terms, t)
+	}
+
+	var word, han []rune
+	flushWord := func() {
+		add(strings.ToLower(string(word)))
+		word = word[:0]
+	}
+	flushHan := func() {
+		if len(han) == 1 {
+			add(string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			add(string(han[i : i+2]))
+		}
+		han = han[:0]
+	}
+
+	for _, r := range query {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return terms
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
@@ -22,18 +23,38 @@ import (
 
 const MaxSearchDepth = 400
 
+// searchKnnSimilarity 搜索向量召回的最低余弦相似度，搜索结果与分面统计共用，保证计数与结果一致
+const searchKnnSimilarity = 0.5
+
+// RankParams 推荐流排序参数，由实验策略决定
+type RankParams struct {
+	KnnBoost     float32 // 向量召回权重，0 表示关闭向量召回
+	TextBoost    float32 // 兴趣文本匹配权重，0 表示关闭文本匹配
+	RandomWeight float64 // 随机打散权重，0 表示关闭随机打散
+}
+
+// RecommendTextQueryName 推荐流兴趣文本匹配的具名查询
+const RecommendTextQueryName = "interest_text"
+
+// DefaultRankParams 默认排序参数
+var DefaultRankParams = RankParams{KnnBoost: 20.0, TextBoost: 2.0, RandomWeight: 1.0}
+
 type PostRepo interface {
 	HybridSearch(ctx context.Context, queryText string, queryVector []float32, from, size int) ([]*PostES, error)
 	HybridSearchMe(ctx context.Context, userID uint64, queryText string, queryVector []float32, from, size int) ([]*PostES, error)
-	RecommendPosts(ctx context.Context, queryText string, queryVector []float32, lastSortValues []interface{}, size int, seed int64) ([]*PostES, error)
+	FilteredSearch(ctx context.Context, queryText string, queryVector []float32, filter *SearchFilter, sortMode string, from, size int) ([]*PostES, error)
+	SearchFacets(ctx context.Context, queryText string, queryVector []float32, filter *SearchFilter, interval string) (*SearchFacets, error)
+	RecommendPosts(ctx context.Context, queryText string, queryVector []float32, lastSortValues []interface{}, size int, seed int64, params *RankParams, exclude *Exclusion) ([]*PostES, error)
+	ExplainRecommendText(ctx context.Context, postID uint64, queryText string, params *RankParams) (float64, string, error)
 	GetSuggestions(ctx context.Context, keyword string) ([]string, error)
 	GetPostById(ctx context.Context, id uint64) (*PostES, error)
-	GetPostByTag(ctx context.Context, tag string, isMain bool, from, size int) ([]*PostES, error)
-	GetLatestPosts(ctx context.Context, from, size int) ([]*PostES, error)
-	GetLatestPostsByCursor(ctx context.Context, lastSortValues []interface{}, size int) ([]*PostES, error)
+	GetPostByTag(ctx context.Context, tag string, isMain bool, from, size int, exclude *Exclusion) ([]*PostES, error)
+	GetLatestPosts(ctx context.Context, from, size int, exclude *Exclusion) ([]*PostES, error)
+	GetLatestPostsByCursor(ctx context.Context, lastSortValues []interface{}, size int, exclude *Exclusion) ([]*PostES, error)
 	IndexPost(ctx context.Context, post *PostES, version int64) error
 	DeletePost(ctx context.Context, id uint64) error
 	UpdatePostUserDetail(ctx context.Context, userID uint64, newNickname string, newAvatar string) error
+	AggregateCreatorTags(ctx context.Context, userID uint64) (*CreatorTags, error)
 }
 
 type PostRepoImpl struct {
@@ -79,52 +100,59 @@ func (s *PostRepoImpl) HybridSearchMe(ctx context.Context, userID uint64, queryT
 }
 
 // RecommendPosts 推荐流：混合检索 + 随机种子 + SearchAfter
-func (s *PostRepoImpl) RecommendPosts(ctx context.Context, queryText string, queryVector []float32, lastSortValues []interface{}, size int, seed int64) ([]*PostES, error) {
+func (s *PostRepoImpl) RecommendPosts(ctx context.Context, queryText string, queryVector []float32, lastSortValues []interface{}, size int, seed int64, params *RankParams, exclude *Exclusion) ([]*PostES, error) {
+	if params == nil {
+		params = &DefaultRankParams
+	}
 	req := s.client.Search().Index(PostIndex).Size(size)
 
 	boolQuery := &types.BoolQuery{
-		Filter: []types.Query{
+		Filter: exclude.appendTo([]types.Query{
 			{Term: map[string]types.TermQuery{"status": {Value: consts.PostStatusNormal}}},
-		},
+		}),
 		Should: []types.Query{},
 	}
 
-	if queryText != "" {
+	if queryText != "" && params.TextBoost > 0 {
 		boolQuery.Should = append(boolQuery.Should, types.Query{
 			MultiMatch: &types.MultiMatchQuery{
-				Query:  queryText,
-				Fields: []string{"title^2", "plain_content", "user_tags"},
-				Boost:  util.PtrFloat32(2.0),
+				Query:      queryText,
+				Fields:     []string{"title^2", "plain_content", "user_tags"},
+				Boost:      util.PtrFloat32(params.TextBoost),
+				QueryName_: util.PtrStr(RecommendTextQueryName),
 			},
 		})
 	}
 
-	seedStr := strconv.FormatInt(seed, 10)
-	weightVal := types.Float64(1.0)
-	boolQuery.Should = append(boolQuery.Should, types.Query{
-		FunctionScore: &types.FunctionScoreQuery{
-			Functions: []types.FunctionScore{
-				{
-					RandomScore: &types.RandomScoreFunction{
-						Seed:  &seedStr,
-						Field: util.PtrStr("_seq_no"),
+	if params.RandomWeight > 0 {
+		seedStr := strconv.FormatInt(seed, 10)
+		weightVal := types.Float64(params.RandomWeight)
+		boolQuery.Should = append(boolQuery.Should, types.Query{
+			FunctionScore: &types.FunctionScoreQuery{
+				Functions: []types.FunctionScore{
+					{
+						RandomScore: &types.RandomScoreFunction{
+							Seed:  &seedStr,
+							Field: util.PtrStr("_seq_no"),
+						},
+						Weight: &weightVal,
 					},
-					Weight: &weightVal,
 				},
+				BoostMode: &functionboostmode.Sum,
 			},
-			BoostMode: &functionboostmode.Sum,
-		},
-	})
+		})
+	}
 
 	req.Query(&types.Query{Bool: boolQuery})
 
-	if len(queryVector) > 0 {
+	if len(queryVector) > 0 && params.KnnBoost > 0 {
 		req.Knn(types.KnnSearch{
 			Field:         "content_vector",
 			QueryVector:   queryVector,
 			K:             util.PtrInt(size),
 			NumCandidates: util.PtrInt(size * 5),
-			Boost:         util.PtrFloat32(20.0),
+			Boost:         util.PtrFloat32(params.KnnBoost),
+			Filter:        boolQuery.Filter,
 		})
 	}
 
@@ -141,9 +169,43 @@ func (s *PostRepoImpl) RecommendPosts(ctx context.Context, queryText string, que
 	return s.executeSearch(ctx, req)
 }
 
+// ExplainRecommendText 复现推荐流中兴趣文本匹配对单个帖子的打分
+func (s *PostRepoImpl) ExplainRecommendText(ctx context.Context, postID uint64, queryText string, params *RankParams) (float64, string, error) {
+	if params == nil {
+		params = &DefaultRankParams
+	}
+	if queryText == "" || params.TextBoost <= 0 {
+		return 0, "", nil
+	}
+
+	query := &types.Query{
+		MultiMatch: &types.MultiMatchQuery{
+			Query:  queryText,
+			Fields: []string{"title^2", "plain_content", "user_tags"},
+			Boost:  util.PtrFloat32(params.TextBoost),
+		},
+	}
+
+	resp, err := s.client.Explain(PostIndex, strconv.FormatUint(postID, 10)).Query(query).Do(ctx)
+	if err != nil {
+		var e *types.ElasticsearchError
+		if errors.As(err, &e) && e.Status == NotFoundCode {
+			return 0, "", nil
+		}
+		return 0, "", err
+	}
+	if !resp.Matched || resp.Explanation == nil {
+		return 0, "", nil
+	}
+	return float64(resp.Explanation.Value), resp.Explanation.Description, nil
+}
+
 func (s *PostRepoImpl) GetSuggestions(ctx context.Context, keyword string) ([]string, error) {
 	suggestKey := "post-suggest"
 
+	simplifiedKey := "post-suggest-simplified"
+	simplified := util.ToSimplified(keyword)
+
 	suggester := types.NewSuggester()
 	suggester.Suggesters[suggestKey] = types.FieldSuggester{
 		Prefix: &keyword,
@@ -155,6 +217,17 @@ func (s *PostRepoImpl) GetSuggestions(ctx context.Context, keyword string) ([]st
 			Size: util.PtrInt(5),
 		},
 	}
+	// 简体归一化标题补全，繁体输入也能得到建议
+	suggester.Suggesters[simplifiedKey] = types.FieldSuggester{
+		Prefix: &simplified,
+		Completion: &types.CompletionSuggester{
+			Field: "title_simplified.suggestion",
+			Fuzzy: &types.SuggestFuzziness{
+				Fuzziness: util.PtrStr("AUTO"),
+			},
+			Size: util.PtrInt(5),
+		},
+	}
 
 	res, err := s.client.Search().
 		Index(PostIndex).
@@ -166,12 +239,19 @@ func (s *PostRepoImpl) GetSuggestions(ctx context.Context, keyword string) ([]st
 	}
 
 	suggestions := make([]string, 0)
-	if results, ok := res.Suggest[suggestKey]; ok {
-		for _, r := range results {
-			if cs, ok := r.(*types.CompletionSuggest); ok {
-				for _, opt := range cs.Options {
-					suggestions = append(suggestions, opt.Text)
+	seen := make(map[string]struct{})
+	for _, key := range []string{suggestKey, simplifiedKey} {
+		for _, r := range res.Suggest[key] {
+			cs, ok := r.(*types.CompletionSuggest)
+			if !ok {
+				continue
+			}
+			for _, opt := range cs.Options {
+				if _, ok := seen[opt.Text]; ok {
+					continue
 				}
+				seen[opt.Text] = struct{}{}
+				suggestions = append(suggestions, opt.Text)
 			}
 		}
 	}
@@ -209,7 +289,7 @@ func (s *PostRepoImpl) GetPostById(ctx context.Context, id uint64) (*PostES, err
 	return &post, nil
 }
 
-func (s *PostRepoImpl) GetPostByTag(ctx context.Context, tag string, isMain bool, from, size int) ([]*PostES, error) {
+func (s *PostRepoImpl) GetPostByTag(ctx context.Context, tag string, isMain bool, from, size int, exclude *Exclusion) ([]*PostES, error) {
 	searchField := "user_tags"
 	if isMain {
 		searchField = "main_tag"
@@ -226,13 +306,13 @@ func (s *PostRepoImpl) GetPostByTag(ctx context.Context, tag string, isMain bool
 						},
 					},
 				},
-				Filter: []types.Query{
+				Filter: exclude.appendTo([]types.Query{
 					{
 						Term: map[string]types.TermQuery{
 							"status": {Value: consts.PostStatusNormal},
 						},
 					},
-				},
+				}),
 			},
 		}).
 		Source_(&types.SourceFilter{
@@ -250,14 +330,10 @@ func (s *PostRepoImpl) GetPostByTag(ctx context.Context, tag string, isMain bool
 }
 
 // GetLatestPosts 获取最新的帖子列表
-func (s *PostRepoImpl) GetLatestPosts(ctx context.Context, from, size int) ([]*PostES, error) {
+func (s *PostRepoImpl) GetLatestPosts(ctx context.Context, from, size int, exclude *Exclusion) ([]*PostES, error) {
 	searchReq := s.client.Search().
 		Index(PostIndex).
-		Query(&types.Query{
-			Term: map[string]types.TermQuery{
-				"status": {Value: consts.PostStatusNormal},
-			},
-		}).
+		Query(latestQuery(exclude)).
 		Sort(types.SortOptions{SortOptions: map[string]types.FieldSort{
 			"created_at": {Order: &sortorder.Desc},
 		}}).
@@ -268,14 +344,10 @@ func (s *PostRepoImpl) GetLatestPosts(ctx context.Context, from, size int) ([]*P
 	return s.executeSearch(ctx, searchReq)
 }
 
-func (s *PostRepoImpl) GetLatestPostsByCursor(ctx context.Context, lastSortValues []interface{}, size int) ([]*PostES, error) {
+func (s *PostRepoImpl) GetLatestPostsByCursor(ctx context.Context, lastSortValues []interface{}, size int, exclude *Exclusion) ([]*PostES, error) {
 	req := s.client.Search().
 		Index(PostIndex).
-		Query(&types.Query{
-			Term: map[string]types.TermQuery{
-				"status": {Value: consts.PostStatusNormal},
-			},
-		}).
+		Query(latestQuery(exclude)).
 		Sort(types.SortOptions{SortOptions: map[string]types.FieldSort{
 			"created_at": {Order: &sortorder.Desc},
 		}}).
@@ -294,24 +366,40 @@ func (s *PostRepoImpl) GetLatestPostsByCursor(ctx context.Context, lastSortValue
 	return s.executeSearch(ctx, req)
 }
 
+// latestQuery 最新流查询，仅含已发布帖子并应用软处置排除
+func latestQuery(exclude *Exclusion) *types.Query {
+	statusQuery := types.Query{
+		Term: map[string]types.TermQuery{
+			"status": {Value: consts.PostStatusNormal},
+		},
+	}
+	if exclude.IsEmpty() {
+		return &statusQuery
+	}
+	return &types.Query{Bool: &types.BoolQuery{Filter: exclude.appendTo([]types.Query{statusQuery})}}
+}
+
 func (s *PostRepoImpl) IndexPost(ctx context.Context, post *PostES, version int64) error {
 	docID := strconv.FormatUint(post.ID, 10)
-
-	_, err := s.client.Index(PostIndex).
-		Id(docID).
-		Document(post).
-		Version(strconv.FormatInt(version, 10)).
-		VersionType(versiontype.External).
-		Do(ctx)
-
-	if err != nil {
-		var e *types.ElasticsearchError
-		if errors.As(err, &e) {
-			if e.Status == ConflictCode {
-				return nil
+	post.FillNormalized()
+
+	for _, index := range writeIndices(ctx, PostIndex) {
+		_, err := s.client.Index(index).
+			Id(docID).
+			Document(post).
+			Version(strconv.FormatInt(version, 10)).
+			VersionType(versiontype.External).
+			Do(ctx)
+
+		if err != nil {
+			var e *types.ElasticsearchError
+			if errors.As(err, &e) {
+				if e.Status == ConflictCode {
+					continue
+				}
 			}
+			return err
 		}
-		return err
 	}
 
 	return nil
@@ -320,16 +408,18 @@ func (s *PostRepoImpl) IndexPost(ctx context.Context, post *PostES, version int6
 func (s *PostRepoImpl) DeletePost(ctx context.Context, id uint64) error {
 	docID := strconv.FormatUint(id, 10)
 
-	_, err := s.client.Delete(PostIndex, docID).Do(ctx)
+	for _, index := range writeIndices(ctx, PostIndex) {
+		_, err := s.client.Delete(index, docID).Do(ctx)
 
-	if err != nil {
-		var e *types.ElasticsearchError
-		if errors.As(err, &e) {
-			if e.Status == NotFoundCode {
-				return nil
+		if err != nil {
+			var e *types.ElasticsearchError
+			if errors.As(err, &e) {
+				if e.Status == NotFoundCode {
+					continue
+				}
 			}
+			return err
 		}
-		return err
 	}
 
 	return nil
@@ -346,7 +436,7 @@ func (s *PostRepoImpl) UpdatePostUserDetail(ctx context.Context, userID uint64,
 
 	scriptSource := "ctx._source.user_nickname = params.new_nickname; ctx._source.user_avatar = params.new_avatar;"
 
-	req := s.client.UpdateByQuery(PostIndex).
+	req := s.client.UpdateByQuery(strings.Join(writeIndices(ctx, PostIndex), ",")).
 		Query(&types.Query{
 			Term: map[string]types.TermQuery{
 				"user_id": {Value: userID},
@@ -405,7 +495,9 @@ func (s *PostRepoImpl) executeHybridFusion(ctx context.Context, queryText string
 		end = len(merged)
 	}
 
-	return merged[start:end], nil
+	page := merged[start:end]
+	fillPassages(page, queryText)
+	return page, nil
 }
 
 func (s *PostRepoImpl) vectorSearch(ctx context.Context, vector []float32, limit int, filters []types.Query, lastSortValues []interface{}) ([]*PostES, error) {
@@ -419,8 +511,8 @@ func (s *PostRepoImpl) vectorSearch(ctx context.Context, vector []float32, limit
 			K:             util.PtrInt(limit),
 			NumCandidates: util.PtrInt(limit * 2),
 			Filter:        filters,
+			Similarity:    util.PtrFloat32(searchKnnSimilarity),
 		}).
-		MinScore(0.75).
 		Source_(&types.SourceFilter{Excludes: []string{"content_vector"}}).
 		Size(limit)
 
@@ -440,29 +532,7 @@ func (s *PostRepoImpl) textSearch(ctx context.Context, text string, limit int, f
 		return []*PostES{}, nil
 	}
 
-	baseQuery := &types.Query{
-		Bool: &types.BoolQuery{
-			Should: []types.Query{
-				{
-					MultiMatch: &types.MultiMatchQuery{
-						Query:  text,
-						Fields: []string{"title^3", "title.pinyin^1", "plain_content^1", "ai_summary^1", "user_tags^3"},
-						Boost:  util.PtrFloat32(2.0),
-					},
-				},
-				{
-					MultiMatch: &types.MultiMatchQuery{
-						Query:     text,
-						Fields:    []string{"title", "plain_content"},
-						Fuzziness: util.PtrStr("AUTO"),
-						Boost:     util.PtrFloat32(0.5),
-					},
-				},
-			},
-			Filter:             filters,
-			MinimumShouldMatch: util.PtrStr("1"),
-		},
-	}
+	baseQuery := s.buildTextQuery(text, filters)
 
 	finalQuery := baseQuery
 	if decorator != nil {
@@ -471,6 +541,7 @@ func (s *PostRepoImpl) textSearch(ctx context.Context, text string, limit int, f
 
 	req := s.client.Search().Index(PostIndex).
 		Query(finalQuery).
+		Highlight(buildHighlight()).
 		Source_(&types.SourceFilter{Excludes: []string{"content_vector"}}).
 		Size(limit)
 
@@ -485,6 +556,36 @@ func (s *PostRepoImpl) textSearch(ctx context.Context, text string, limit int, f
 	return s.executeSearch(ctx, req)
 }
 
+// buildTextQuery 文本检索主查询，供文本召回与聚合统计复用
+func (s *PostRepoImpl) buildTextQuery(text string, filters []types.Query) *types.Query {
+	should := []types.Query{
+		{
+			MultiMatch: &types.MultiMatchQuery{
+				Query:  text,
+				Fields: []string{"title^3", "title.pinyin^1", "plain_content^1", "ai_summary^1", "user_tags^3"},
+				Boost:  util.PtrFloat32(2.0),
+			},
+		},
+		{
+			MultiMatch: &types.MultiMatchQuery{
+				Query:     text,
+				Fields:    []string{"title", "plain_content"},
+				Fuzziness: util.PtrStr("AUTO"),
+				Boost:     util.PtrFloat32(0.5),
+			},
+		},
+	}
+	should = append(should, normalizedQueries(text, 1.0, "title_simplified^3", "tags_simplified^2")...)
+
+	return &types.Query{
+		Bool: &types.BoolQuery{
+			Should:             should,
+			Filter:             filters,
+			MinimumShouldMatch: util.PtrStr("1"),
+		},
+	}
+}
+
 func (s *PostRepoImpl) manualRRF(ranks ...[]*PostES) []*PostES {
 	const k = 60
 	scoreMap := make(map[uint64]float64)
@@ -493,6 +594,10 @@ func (s *PostRepoImpl) manualRRF(ranks ...[]*PostES) []*PostES {
 	for _, resultList := range ranks {
 		for rank, post := range resultList {
 			scoreMap[post.ID] += 1.0 / float64(k+rank+1)
+			// 同一帖子保留带高亮的文本召回结果
+			if exist, ok := postMap[post.ID]; ok && exist.Highlight != nil {
+				continue
+			}
 			postMap[post.ID] = post
 		}
 	}
@@ -544,7 +649,28 @@ func (s *PostRepoImpl) executeSearch(ctx context.Context, req *search.Search) ([
 				post.Sort[i] = v
 			}
 		}
+		if hit.Score_ != nil {
+			post.Score = float64(*hit.Score_)
+		}
+		post.MatchedQueries = parseMatchedQueries(hit.MatchedQueries)
+		post.Highlight = parseHighlight(hit.Highlight)
 		results = append(results, &post)
 	}
 	return results, nil
 }
+
+// parseMatchedQueries 兼容 matched_queries 的数组与带分数的对象两种返回格式
+func parseMatchedQueries(raw any) []string {
+	switch v := raw.(type) {
+	case []string:
+		return v
+	case map[string]types.Float64:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		return names
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,278 @@
+package es
+
+import (
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/util"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/calendarinterval"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+)
+
+// 搜索排序方式
+const (
+	SearchSortRelevance = "relevance"
+	SearchSortNewest    = "newest"
+	SearchSortMostLiked = "most_liked"
+)
+
+// MediaTypeText 无媒体的纯文本帖子
+const MediaTypeText = "text"
+
+const (
+	facetTermsSize  = 20
+	facetMainTags   = "main_tags"
+	facetAITags     = "ai_tags"
+	facetMediaTypes = "media_types"
+	facetDates      = "dates"
+)
+
+// SearchFilter 搜索过滤条件
+type SearchFilter struct {
+	MainTag   string
+	AITags    []string
+	AuthorID  uint64
+	StartTime *time.Time
+	EndTime   *time.Time
+	MediaType string
+	MinLikes  int
+	Exclude   *Exclusion // 软处置排除条件
+}
+
+// FacetBucket 聚合桶
+type FacetBucket struct {
+	Key   string
+	Count int64
+}
+
+// SearchFacets 搜索聚合结果
+type SearchFacets struct {
+	MainTags      []FacetBucket
+	AITags        []FacetBucket
+	MediaTypes    []FacetBucket
+	DateHistogram []FacetBucket
+}
+
+// BuildMediaTypes 根据媒体列表生成 media_types 字段，用于按媒体类型过滤与聚合
+func BuildMediaTypes(media []PostMediaES) []string {
+	if len(media) == 0 {
+		return []string{MediaTypeText}
+	}
+	seen := make(map[string]struct{})
+	res := make([]string, 0, 1)
+	for _, m := range media {
+		prefix, _, _ := strings.Cut(m.Type, "/")
+		if prefix == "" {
+			continue
+		}
+		if _, ok := seen[prefix]; ok {
+			continue
+		}
+		seen[prefix] = struct{}{}
+		res = append(res, prefix)
+	}
+	if len(res) == 0 {
+		return []string{MediaTypeText}
+	}
+	return res
+}
+
+// FilteredSearch 带过滤与排序的混合检索，过滤条件同时作用于向量与文本两路召回，RRF 融合后再排序
+func (s *PostRepoImpl) FilteredSearch(ctx context.Context, queryText string, queryVector []float32, filter *SearchFilter, sortMode string, from, size int) ([]*PostES, error) {
+	if from >= MaxSearchDepth {
+		return []*PostES{}, nil
+	}
+	filters := filter.toQueries()
+
+	// 无关键词时退化为纯过滤浏览
+	if queryText == "" {
+		return s.browseSearch(ctx, filters, sortMode, from, size)
+	}
+
+	candidateLimit := s.calculateCandidateLimit(from + size)
+	if sortMode == SearchSortRelevance || sortMode == "" {
+		return s.executeHybridFusion(ctx, queryText, queryVector, filters, candidateLimit, from, size, nil, nil)
+	}
+
+	candidates, err := s.executeHybridFusion(ctx, queryText, queryVector, filters, candidateLimit, 0, candidateLimit, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	sortPosts(candidates, sortMode)
+
+	if from > len(candidates) {
+		return []*PostES{}, nil
+	}
+	end := from + size
+	if end > len(candidates) {
+		end = len(candidates)
+	}
+	return candidates[from:end], nil
+}
+
+// SearchFacets 统计当前检索条件下的标签、媒体类型与时间分布
+func (s *PostRepoImpl) SearchFacets(ctx context.Context, queryText string, queryVector []float32, filter *SearchFilter, interval string) (*SearchFacets, error) {
+	filters := filter.toQueries()
+
+	dateInterval := calendarinterval.Month
+	if interval == calendarinterval.Day.Name {
+		dateInterval = calendarinterval.Day
+	}
+
+	req := s.client.Search().Index(PostIndex).
+		Size(0).
+		Aggregations(map[string]types.Aggregations{
+			facetMainTags:   {Terms: &types.TermsAggregation{Field: util.PtrStr("main_tag"), Size: util.PtrInt(facetTermsSize)}},
+			facetAITags:     {Terms: &types.TermsAggregation{Field: util.PtrStr("ai_tags"), Size: util.PtrInt(facetTermsSize)}},
+			facetMediaTypes: {Terms: &types.TermsAggregation{Field: util.PtrStr("media_types"), Size: util.PtrInt(facetTermsSize)}},
+			facetDates: {DateHistogram: &types.DateHistogramAggregation{
+				Field:            util.PtrStr("created_at"),
+				CalendarInterval: &dateInterval,
+				Format:           util.PtrStr("yyyy-MM-dd"),
+				MinDocCount:      util.PtrInt(1),
+			}},
+		})
+
+	if queryText == "" {
+		req.Query(&types.Query{Bool: &types.BoolQuery{Filter: filters}})
+	} else {
+		req.Query(s.buildTextQuery(queryText, filters))
+		if len(queryVector) > 0 {
+			limit := s.calculateCandidateLimit(MaxSearchDepth)
+			req.Knn(types.KnnSearch{
+				Field:         "content_vector",
+				QueryVector:   queryVector,
+				K:             util.PtrInt(limit),
+				NumCandidates: util.PtrInt(limit * 2),
+				Filter:        filters,
+				Similarity:    util.PtrFloat32(searchKnnSimilarity),
+			})
+		}
+	}
+
+	resp, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchFacets{
+		MainTags:      parseTermsBuckets(resp.Aggregations[facetMainTags]),
+		AITags:        parseTermsBuckets(resp.Aggregations[facetAITags]),
+		MediaTypes:    parseTermsBuckets(resp.Aggregations[facetMediaTypes]),
+		DateHistogram: parseDateBuckets(resp.Aggregations[facetDates]),
+	}, nil
+}
+
+// browseSearch 无关键词时按过滤条件分页浏览
+func (s *PostRepoImpl) browseSearch(ctx context.Context, filters []types.Query, sortMode string, from, size int) ([]*PostES, error) {
+	sortField := "created_at"
+	if sortMode == SearchSortMostLiked {
+		sortField = "likes_count"
+	}
+
+	req := s.client.Search().Index(PostIndex).
+		Query(&types.Query{Bool: &types.BoolQuery{Filter: filters}}).
+		Sort(types.SortOptions{SortOptions: map[string]types.FieldSort{
+			sortField: {Order: &sortorder.Desc},
+		}}).
+		Source_(&types.SourceFilter{Excludes: []string{"content_vector"}}).
+		From(from).
+		Size(size)
+
+	return s.executeSearch(ctx, req)
+}
+
+// toQueries 将过滤条件转换为 ES filter 子句，始终只检索已发布的帖子
+func (f *SearchFilter) toQueries() []types.Query {
+	filters := []types.Query{
+		{Term: map[string]types.TermQuery{"status": {Value: consts.PostStatusNormal}}},
+	}
+	if f == nil {
+		return filters
+	}
+
+	if f.MainTag != "" {
+		filters = append(filters, types.Query{Term: map[string]types.TermQuery{"main_tag": {Value: f.MainTag}}})
+	}
+	for _, tag := range f.AITags {
+		filters = append(filters, types.Query{Term: map[string]types.TermQuery{"ai_tags": {Value: tag}}})
+	}
+	if f.AuthorID > 0 {
+		filters = append(filters, types.Query{Term: map[string]types.TermQuery{"user_id": {Value: f.AuthorID}}})
+	}
+	if f.MediaType != "" {
+		filters = append(filters, types.Query{Term: map[string]types.TermQuery{"media_types": {Value: f.MediaType}}})
+	}
+	if f.MinLikes > 0 {
+		minLikes := types.Float64(f.MinLikes)
+		filters = append(filters, types.Query{Range: map[string]types.RangeQuery{
+			"likes_count": types.NumberRangeQuery{Gte: &minLikes},
+		}})
+	}
+	if f.StartTime != nil || f.EndTime != nil {
+		dateRange := types.DateRangeQuery{}
+		if f.StartTime != nil {
+			dateRange.Gte = util.PtrStr(f.StartTime.UTC().Format(time.RFC3339))
+		}
+		if f.EndTime != nil {
+			dateRange.Lt = util.PtrStr(f.EndTime.UTC().Format(time.RFC3339))
+		}
+		filters = append(filters, types.Query{Range: map[string]types.RangeQuery{"created_at": dateRange}})
+	}
+	return f.Exclude.appendTo(filters)
+}
+
+// sortPosts 对融合后的候选集按指定方式排序
+func sortPosts(posts []*PostES, sortMode string) {
+	switch sortMode {
+	case SearchSortNewest:
+		sort.SliceStable(posts, func(i, j int) bool {
+			return posts[i].CreatedAt.After(posts[j].CreatedAt)
+		})
+	case SearchSortMostLiked:
+		sort.SliceStable(posts, func(i, j int) bool {
+			return posts[i].LikesCount > posts[j].LikesCount
+		})
+	}
+}
+
+func parseTermsBuckets(agg types.Aggregate) []FacetBucket {
+	res := make([]FacetBucket, 0)
+	terms, ok := agg.(*types.StringTermsAggregate)
+	if !ok {
+		return res
+	}
+	buckets, ok := terms.Buckets.([]types.StringTermsBucket)
+	if !ok {
+		return res
+	}
+	for _, b := range buckets {
+		key, _ := b.Key.(string)
+		res = append(res, FacetBucket{Key: key, Count: b.DocCount})
+	}
+	return res
+}
+
+func parseDateBuckets(agg types.Aggregate) []FacetBucket {
+	res := make([]FacetBucket, 0)
+	histogram, ok := agg.(*types.DateHistogramAggregate)
+	if !ok {
+		return res
+	}
+	buckets, ok := histogram.Buckets.([]types.DateHistogramBucket)
+	if !ok {
+		return res
+	}
+	for _, b := range buckets {
+		key := time.UnixMilli(b.Key).UTC().Format(time.DateOnly)
+		if b.KeyAsString != nil {
+			key = *b.KeyAsString
+		}
+		res = append(res, FacetBucket{Key: key, Count: b.DocCount})
+	}
+	return res
+}
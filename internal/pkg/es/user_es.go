@@ -1,16 +1,30 @@
 package es
 
-import "time"
+import (
+	"Cornerstone/internal/pkg/util"
+	"time"
+)
 
 // UserES 对应 user_index 的文档结构
 type UserES struct {
-	ID             uint64    `json:"id"`
-	Nickname       string    `json:"nickname"`
-	Bio            *string   `json:"bio,omitempty"`
-	AvatarURL      string    `json:"avatar_url"`
-	Gender         int       `json:"gender"`
-	Region         string    `json:"region"`
-	Birthday       time.Time `json:"birthday"`
-	FollowersCount int       `json:"followers_count"`
-	FollowingCount int       `json:"following_count"`
+	ID       uint64 `json:"id"`
+	Nickname string `json:"nickname"`
+	// NicknameSimplified 简体归一化昵称，拼音与首字母由 mapping 子字段生成
+	NicknameSimplified string    `json:"nickname_simplified,omitempty"`
+	Bio                *string   `json:"bio,omitempty"`
+	AvatarURL          string    `json:"avatar_url"`
+	Gender             int       `json:"gender"`
+	Region             string    `json:"region"`
+	Birthday           time.Time `json:"birthday"`
+	FollowersCount     int       `json:"followers_count"`
+	FollowingCount     int       `json:"following_count"`
+	// CreatorTags 已发布笔记聚合的主标签与 AI 标签
+	CreatorTags []string `json:"creator_tags,omitempty"`
+	// CreatorVector 由简介与 CreatorTags 生成的创作者向量
+	CreatorVector []float32 `json:"creator_vector,omitempty"`
+}
+
+// FillNormalized 写入前生成简体归一化字段
+func (u *UserES) FillNormalized() {
+	u.NicknameSimplified = util.ToSimplified(u.Nickname)
 }
@@ -13,9 +13,13 @@ import (
 )
 
 type UserRepo interface {
-	SearchUser(ctx context.Context, keyword string, from, size int) ([]*UserES, int64, error)
+	SearchUser(ctx context.Context, keyword string, queryVector []float32, from, size int) ([]*UserES, int64, error)
+	GetSuggestions(ctx context.Context, keyword string) ([]string, error)
 	Exist(ctx context.Context, id uint64) (bool, error)
+	GetUserById(ctx context.Context, id uint64) (*UserES, error)
+	SimilarCreators(ctx context.Context, userID uint64, vector []float32, size int) ([]*UserES, error)
 	IndexUser(ctx context.Context, user *UserES, version int64) error
+	UpdateCreatorProfile(ctx context.Context, id uint64, tags []string, vector []float32) error
 	DeleteUser(ctx context.Context, id uint64) error
 }
 
@@ -27,63 +31,135 @@ func NewUserRepo(client *elasticsearch.TypedClient) UserRepo {
 	return &UserRepoImpl{client: client}
 }
 
-func (s *UserRepoImpl) SearchUser(ctx context.Context, keyword string, from, size int) ([]*UserES, int64, error) {
+// SearchUser 昵称关键词检索，传入向量时叠加创作者向量召回
+func (s *UserRepoImpl) SearchUser(ctx context.Context, keyword string, queryVector []float32, from, size int) ([]*UserES, int64, error) {
 	if keyword == "" {
 		return []*UserES{}, 0, nil
 	}
 
-	query := &types.Query{
-		Bool: &types.BoolQuery{
-			Should: []types.Query{
-				{
-					MultiMatch: &types.MultiMatchQuery{
-						Query: keyword,
-						Fields: []string{
-							"nickname^3",
-							"nickname.pinyin^1",
-						},
-						Boost: util.PtrFloat32(2.0),
-					},
-				},
-				{
-					MultiMatch: &types.MultiMatchQuery{
-						Query:     keyword,
-						Fields:    []string{"nickname"},
-						Fuzziness: util.PtrStr("AUTO"),
-						Boost:     util.PtrFloat32(0.5),
-					},
+	should := []types.Query{
+		{
+			MultiMatch: &types.MultiMatchQuery{
+				Query: keyword,
+				Fields: []string{
+					"nickname^3",
+					"nickname.pinyin^1",
 				},
+				Boost: util.PtrFloat32(2.0),
+			},
+		},
+		{
+			MultiMatch: &types.MultiMatchQuery{
+				Query:     keyword,
+				Fields:    []string{"nickname"},
+				Fuzziness: util.PtrStr("AUTO"),
+				Boost:     util.PtrFloat32(0.5),
 			},
+		},
+	}
+	should = append(should, normalizedQueries(keyword, 1.0, "nickname_simplified^3")...)
+
+	query := &types.Query{
+		Bool: &types.BoolQuery{
+			Should:             should,
 			MinimumShouldMatch: util.PtrStr("1"),
 		},
 	}
 
-	res, err := s.client.Search().
+	req := s.client.Search().
 		Index(UserIndex).
 		Query(query).
+		Source_(&types.SourceFilter{Excludes: []string{"creator_vector"}}).
 		From(from).
-		Size(size).
-		Do(ctx)
+		Size(size)
+
+	if len(queryVector) > 0 {
+		req.Knn(types.KnnSearch{
+			Field:         "creator_vector",
+			QueryVector:   queryVector,
+			K:             util.PtrInt(from + size),
+			NumCandidates: util.PtrInt((from + size) * 5),
+			Boost:         util.PtrFloat32(creatorKnnBoost),
+			Similarity:    util.PtrFloat32(creatorKnnSimilarity),
+		})
+	}
 
+	res, err := req.Do(ctx)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	total := res.Hits.Total.Value
-	users := make([]*UserES, 0, len(res.Hits.Hits))
+	return parseUserHits(res.Hits.Hits), res.Hits.Total.Value, nil
+}
 
-	for _, hit := range res.Hits.Hits {
-		var user UserES
-		if hit.Source_ == nil {
-			continue
-		}
-		if err := json.Unmarshal(hit.Source_, &user); err != nil {
-			continue
+// GetSuggestions 昵称补全，原文、简体、全拼与首字母前缀均可命中
+func (s *UserRepoImpl) GetSuggestions(ctx context.Context, keyword string) ([]string, error) {
+	if keyword == "" {
+		return []string{}, nil
+	}
+	simplified := util.ToSimplified(keyword)
+
+	fields := []struct {
+		key    string
+		field  string
+		prefix string
+	}{
+		{"user-suggest", "nickname.suggestion", keyword},
+		{"user-suggest-simplified", "nickname_simplified.suggestion", simplified},
+		{"user-suggest-initials", "nickname_simplified.initials_suggestion", simplified},
+	}
+
+	suggester := types.NewSuggester()
+	for _, f := range fields {
+		prefix := f.prefix
+		suggester.Suggesters[f.key] = types.FieldSuggester{
+			Prefix: &prefix,
+			Completion: &types.CompletionSuggester{
+				Field: f.field,
+				Fuzzy: &types.SuggestFuzziness{
+					Fuzziness: util.PtrStr("AUTO"),
+				},
+				Size: util.PtrInt(5),
+			},
 		}
-		users = append(users, &user)
 	}
 
-	return users, total, nil
+	res, err := s.client.Search().
+		Index(UserIndex).
+		Suggest(suggester).
+		Source_(&types.SourceFilter{Excludes: []string{"creator_vector"}}).
+		Size(0).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, f := range fields {
+		for _, r := range res.Suggest[f.key] {
+			cs, ok := r.(*types.CompletionSuggest)
+			if !ok {
+				continue
+			}
+			for _, opt := range cs.Options {
+				// 简体子字段的补全文本为简体，统一返回原昵称
+				text := opt.Text
+				if opt.Source_ != nil {
+					var doc UserES
+					if err := json.Unmarshal(opt.Source_, &doc); err == nil && doc.Nickname != "" {
+						text = doc.Nickname
+					}
+				}
+				if _, ok := seen[text]; ok {
+					continue
+				}
+				seen[text] = struct{}{}
+				suggestions = append(suggestions, text)
+			}
+		}
+	}
+	return suggestions, nil
 }
 
 func (s *UserRepoImpl) Exist(ctx context.Context, id uint64) (bool, error) {
@@ -97,22 +173,25 @@ func (s *UserRepoImpl) Exist(ctx context.Context, id uint64) (bool, error) {
 
 func (s *UserRepoImpl) IndexUser(ctx context.Context, user *UserES, version int64) error {
 	docID := strconv.FormatUint(user.ID, 10)
-
-	_, err := s.client.Index(UserIndex).
-		Id(docID).
-		Document(user).
-		Version(strconv.FormatInt(version, 10)).
-		VersionType(versiontype.External).
-		Do(ctx)
-
-	if err != nil {
-		var e *types.ElasticsearchError
-		if errors.As(err, &e) {
-			if e.Status == ConflictCode {
-				return nil
+	user.FillNormalized()
+
+	for _, index := range writeIndices(ctx, UserIndex) {
+		_, err := s.client.Index(index).
+			Id(docID).
+			Document(user).
+			Version(strconv.FormatInt(version, 10)).
+			VersionType(versiontype.External).
+			Do(ctx)
+
+		if err != nil {
+			var e *types.ElasticsearchError
+			if errors.As(err, &e) {
+				if e.Status == ConflictCode {
+					continue
+				}
 			}
+			return err
 		}
-		return err
 	}
 
 	return nil
@@ -120,15 +199,17 @@ func (s *UserRepoImpl) IndexUser(ctx context.Context, user *UserES, version int6
 
 func (s *UserRepoImpl) DeleteUser(ctx context.Context, id uint64) error {
 	docID := strconv.FormatUint(id, 10)
-	_, err := s.client.Delete(UserIndex, docID).Do(ctx)
-	if err != nil {
-		var e *types.ElasticsearchError
-		if errors.As(err, &e) {
-			if e.Status == NotFoundCode {
-				return nil
+	for _, index := range writeIndices(ctx, UserIndex) {
+		_, err := s.client.Delete(index, docID).Do(ctx)
+		if err != nil {
+			var e *types.ElasticsearchError
+			if errors.As(err, &e) {
+				if e.Status == NotFoundCode {
+					continue
+				}
 			}
+			return err
 		}
-		return err
 	}
 	return nil
 }
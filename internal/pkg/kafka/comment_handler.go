@@ -24,21 +24,33 @@ const (
 
 type CommentsHandler struct {
 	postActionRepo repository.PostActionRepo
+	editRepo       repository.PostCommentEditRepo
 	postRepo       repository.PostRepo
 	sysBoxRepo     mongo.SysBoxRepo
+	moderationRepo repository.ModerationRepo
+	strikeRecorder StrikeRecorder
+	spamObserver   SpamObserver
 	processor      processor.ContentLLMProcessor
 }
 
 func NewCommentsHandler(
 	actionRepo repository.PostActionRepo,
+	editRepo repository.PostCommentEditRepo,
 	postRepo repository.PostRepo,
 	sysBoxRepo mongo.SysBoxRepo,
+	moderationRepo repository.ModerationRepo,
+	strikeRecorder StrikeRecorder,
+	spamObserver SpamObserver,
 	proc processor.ContentLLMProcessor,
 ) *CommentsHandler {
 	return &CommentsHandler{
 		postActionRepo: actionRepo,
+		editRepo:       editRepo,
 		postRepo:       postRepo,
 		sysBoxRepo:     sysBoxRepo,
+		moderationRepo: moderationRepo,
+		strikeRecorder: strikeRecorder,
+		spamObserver:   spamObserver,
 		processor:      proc,
 	}
 }
@@ -73,7 +85,10 @@ func (s *CommentsHandler) logic(ctx context.Context, msg *sarama.ConsumerMessage
 		if s.isSoftDeleted(canalMsg) {
 			return s.handleDelete(ctx, canalMsg)
 		}
-		return nil
+		if s.isEditSubmitted(canalMsg) {
+			return s.handleEdit(ctx, canalMsg)
+		}
+		return s.handleReview(ctx, canalMsg)
 	}
 
 	if canalMsg.Type != INSERT {
@@ -84,6 +99,7 @@ func (s *CommentsHandler) logic(ctx context.Context, msg *sarama.ConsumerMessage
 	if err != nil || commentModel == nil {
 		return err
 	}
+	s.spamObserver.ObserveComment(ctx, commentModel.UserID, commentModel.PostID, commentModel.ID, commentModel.Content, eventTime(canalMsg))
 
 	// 准备审核素材
 	mediaForAudit := make([]*es.PostMediaES, 0, len(commentModel.MediaInfo))
@@ -101,7 +117,13 @@ func (s *CommentsHandler) logic(ctx context.Context, msg *sarama.ConsumerMessage
 		return err
 	}
 
+	saveVerdicts(ctx, s.moderationRepo, model.ModerationTargetComment, commentModel.ID, res)
+
 	finalStatus := atomic.LoadInt32(&res.MaxStatus)
+	if finalStatus == llm.ContentSafeDeny {
+		saveModerationDecision(ctx, "comment", commentModel.ID, res)
+		recordStrike(ctx, s.strikeRecorder, commentModel.UserID, model.StrikeSourceCommentAudit, commentModel.ID, res)
+	}
 
 	return s.handleAuditResult(ctx, commentModel, int8(finalStatus))
 }
@@ -196,6 +218,93 @@ func (s *CommentsHandler) handleDelete(ctx context.Context, msg *CanalMessage) e
 	return nil
 }
 
+// handleReview 人工复核改变评论状态：拒绝后恢复计入评论数，下架后扣减评论数
+// 待审核到通过的首次审核由 INSERT 流程计数，这里不重复处理
+func (s *CommentsHandler) handleReview(ctx context.Context, msg *CanalMessage) error {
+	if len(msg.Old) == 0 {
+		return nil
+	}
+	oldVal, ok := msg.Old[0]["status"]
+	if !ok {
+		return nil
+	}
+	row := msg.Data[0]
+	if row["is_deleted"] == "1" {
+		return nil
+	}
+
+	oldStatus := int8(StrToInt(oldVal))
+	newStatus := int8(StrToInt(row["status"]))
+	var increment bool
+	switch {
+	case oldStatus == llm.ContentSafeDeny && newStatus == CommentStatusApproved:
+		increment = true
+	case oldStatus == CommentStatusApproved && newStatus == llm.ContentSafeDeny:
+		increment = false
+	default:
+		return nil
+	}
+
+	postID := StrToUint64(row["post_id"])
+	ExecAction(ctx, ActionParams{
+		TargetID:       postID,
+		CountKeyPrefix: consts.PostCommentKey,
+		DirtyKey:       consts.PostDirtyKey,
+		IsIncrement:    increment,
+	})
+	log.InfoContext(ctx, "comment status reviewed", "id", StrToUint64(row["id"]), "postID", postID, "status", newStatus)
+	return nil
+}
+
+// handleEdit 审核评论的待生效编辑：通过后替换内容，未通过则保留原内容，评论状态不受影响
+func (s *CommentsHandler) handleEdit(ctx context.Context, msg *CanalMessage) error {
+	row := msg.Data[0]
+	commentID := StrToUint64(row["id"])
+	edit, err := s.editRepo.GetPendingEdit(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if edit == nil {
+		return nil
+	}
+	s.spamObserver.ObserveComment(ctx, edit.UserID, StrToUint64(row["post_id"]), commentID, edit.Content, eventTime(msg))
+
+	res, err := s.processor.Process(ctx, "", edit.Content, nil, true)
+	if err != nil {
+		// 审核失败时按未通过处理并清除待审核标记，评论者可重新提交编辑
+		log.ErrorContext(ctx, "comment edit processor execution failed", "id", commentID, "editId", edit.ID, "err", err)
+		return s.editRepo.ApplyEdit(ctx, edit, false, time.Now())
+	}
+
+	saveVerdicts(ctx, s.moderationRepo, model.ModerationTargetComment, commentID, res)
+
+	finalStatus := atomic.LoadInt32(&res.MaxStatus)
+	if finalStatus == llm.ContentSafeDeny {
+		recordStrike(ctx, s.strikeRecorder, edit.UserID, model.StrikeSourceCommentAudit, commentID, res)
+	}
+
+	// 只有完全通过审核的编辑才生效
+	approved := finalStatus == llm.ContentSafePass
+	if err = s.editRepo.ApplyEdit(ctx, edit, approved, time.Now()); err != nil {
+		return err
+	}
+	log.InfoContext(ctx, "comment edit audited", "id", commentID, "editId", edit.ID, "approved", approved)
+	return nil
+}
+
+// isEditSubmitted 评论被标记为有待审核编辑，表示评论者提交了编辑
+func (s *CommentsHandler) isEditSubmitted(msg *CanalMessage) bool {
+	if len(msg.Data) == 0 || len(msg.Old) == 0 {
+		return false
+	}
+	oldVal, ok := msg.Old[0]["edit_pending"]
+	if !ok {
+		return false
+	}
+	row := msg.Data[0]
+	return oldVal == "0" && row["edit_pending"] == "1" && row["is_deleted"] != "1"
+}
+
 func (s *CommentsHandler) isSoftDeleted(msg *CanalMessage) bool {
 	if len(msg.Data) == 0 || len(msg.Old) == 0 {
 		return false
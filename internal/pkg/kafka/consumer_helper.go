@@ -2,13 +2,20 @@ package kafka
 
 import (
 	"Cornerstone/internal/api/config"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/llm"
 	"Cornerstone/internal/pkg/logger"
+	"Cornerstone/internal/pkg/processor"
 	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
 	"context"
 	"errors"
 	log "log/slog"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -16,6 +23,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// moderationDecisionTTL 审核结论保留时间，超出后申诉不再附带模型结论
+const moderationDecisionTTL = 30 * 24 * time.Hour
+
 type LogicFunc func(ctx context.Context, msg *sarama.ConsumerMessage) error
 
 // ActionParams 定义消费者执行动作的统一参数
@@ -185,3 +195,99 @@ func ExecAction(ctx context.Context, p ActionParams) {
 		p.NotifyFunc()
 	}
 }
+
+// saveModerationDecision 记录模型审核拒绝结论，作者申诉时附带给审核员
+func saveModerationDecision(ctx context.Context, target string, id uint64, r *processor.Result) {
+	r.Lock()
+	decision := &llm.ModerationDecision{
+		Status:    int(atomic.LoadInt32(&r.MaxStatus)),
+		Tags:      append([]string{}, r.Tags...),
+		Summaries: append([]string{}, r.Summaries...),
+		AuditedAt: time.Now(),
+	}
+	for _, v := range r.Verdicts {
+		if v.Response.Status == llm.ContentSafeDeny && v.Response.Reason != "" {
+			decision.Reasons = append(decision.Reasons, v.Response.Reason)
+		}
+	}
+	r.Unlock()
+
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return
+	}
+	key := consts.ModerationDecisionKey + target + ":" + strconv.FormatUint(id, 10)
+	if err = redis.SetWithExpiration(ctx, key, string(data), moderationDecisionTTL); err != nil {
+		log.WarnContext(ctx, "save moderation decision failed", "target", target, "id", id, "err", err)
+	}
+}
+
+// StrikeRecorder 记录用户违规，由 service 层实现
+type StrikeRecorder interface {
+	AddStrike(ctx context.Context, userID uint64, source int8, targetID uint64, reason string) error
+}
+
+// SpamObserver 接收点赞、关注与评论事件用于垃圾行为识别，由 service 层实现，内部失败不影响消费
+type SpamObserver interface {
+	ObserveLike(ctx context.Context, userID, postID uint64, at time.Time)
+	ObserveFollow(ctx context.Context, followerID, followingID uint64, followed bool, at time.Time)
+	ObserveComment(ctx context.Context, userID, postID, commentID uint64, content string, at time.Time)
+}
+
+// eventTime Canal 事件在源库的执行时间，缺失时取当前时间
+func eventTime(msg *CanalMessage) time.Time {
+	if msg.ES > 0 {
+		return time.UnixMilli(msg.ES)
+	}
+	return time.Now()
+}
+
+// recordStrike 审核拒绝后为作者记录违规，失败仅记录日志
+func recordStrike(ctx context.Context, recorder StrikeRecorder, userID uint64, source int8, targetID uint64, r *processor.Result) {
+	reason := "内容审核未通过"
+	r.Lock()
+	for _, v := range r.Verdicts {
+		if v.Response.Status == llm.ContentSafeDeny && v.Response.Reason != "" {
+			reason = v.Response.Reason
+			break
+		}
+	}
+	r.Unlock()
+
+	if err := recorder.AddStrike(ctx, userID, source, targetID, reason); err != nil {
+		log.ErrorContext(ctx, "record strike failed", "userId", userID, "source", source, "targetId", targetID, "err", err)
+	}
+}
+
+// saveVerdicts 留存本次审核的全部模型子结论，写入失败不影响审核流程
+func saveVerdicts(ctx context.Context, repo repository.ModerationRepo, targetType int8, targetID uint64, r *processor.Result) {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.Verdicts) == 0 {
+		return
+	}
+
+	auditID := uuid.NewString()
+	verdicts := make([]*model.ModerationVerdict, 0, len(r.Verdicts))
+	for _, v := range r.Verdicts {
+		resp := v.Response
+		verdicts = append(verdicts, &model.ModerationVerdict{
+			TargetType:    targetType,
+			TargetID:      targetID,
+			AuditID:       auditID,
+			Source:        v.Source,
+			Refs:          v.Refs,
+			Input:         util.TruncateRunes(v.Input, 900),
+			Status:        int8(resp.Status),
+			Reason:        util.TruncateRunes(resp.Reason, 450),
+			Confidence:    resp.Confidence,
+			Model:         resp.Model,
+			PromptVersion: resp.PromptVersion,
+			LatencyMs:     resp.Latency.Milliseconds(),
+			Raw:           resp.Raw,
+		})
+	}
+	if err := repo.CreateVerdicts(ctx, verdicts); err != nil {
+		log.WarnContext(ctx, "save moderation verdicts failed", "targetType", targetType, "targetId", targetID, "err", err)
+	}
+}
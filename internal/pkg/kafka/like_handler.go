@@ -14,14 +14,16 @@ import (
 )
 
 type LikesHandler struct {
-	postRepo   repository.PostRepo
-	sysBoxRepo mongo.SysBoxRepo
+	postRepo     repository.PostRepo
+	sysBoxRepo   mongo.SysBoxRepo
+	spamObserver SpamObserver
 }
 
-func NewLikesHandler(postRepo repository.PostRepo, sysBox mongo.SysBoxRepo) *LikesHandler {
+func NewLikesHandler(postRepo repository.PostRepo, sysBox mongo.SysBoxRepo, spamObserver SpamObserver) *LikesHandler {
 	return &LikesHandler{
-		postRepo:   postRepo,
-		sysBoxRepo: sysBox,
+		postRepo:     postRepo,
+		sysBoxRepo:   sysBox,
+		spamObserver: spamObserver,
 	}
 }
 
@@ -87,6 +89,7 @@ func (s *LikesHandler) handleInsert(ctx context.Context, msg *CanalMessage) erro
 	if err := redis.Expire(ctx, userSetKey, 7*24*time.Hour); err != nil {
 		return err
 	}
+	s.spamObserver.ObserveLike(ctx, userID, postID, eventTime(msg))
 
 	log.InfoContext(ctx, "post like inserted", "userID", userID, "postID", postID)
 	return nil
@@ -51,8 +51,12 @@ func NewConsumerManager(
 	sysBoxRepo mongo.SysBoxRepo,
 	userDBRepo repository.UserRepo,
 	actionDBRepo repository.PostActionRepo,
+	commentEditRepo repository.PostCommentEditRepo,
 	userFollowDBRepo repository.UserFollowRepo,
 	postDBRepo repository.PostRepo,
+	moderationRepo repository.ModerationRepo,
+	strikeRecorder StrikeRecorder,
+	spamObserver SpamObserver,
 ) (*ConsumerManager, error) {
 	saramaCfg := newSaramaConfig(cfg.Kafka)
 	m := &ConsumerManager{}
@@ -74,35 +78,35 @@ func NewConsumerManager(
 		rollback()
 		return nil, err
 	}
-	m.userDetailHandler = NewUserDetailHandler(userFollowDBRepo, userESRepo, postESRepo)
+	m.userDetailHandler = NewUserDetailHandler(userDBRepo, userFollowDBRepo, userESRepo, postESRepo, sysBoxRepo, contentProcessor)
 
 	m.userFollowsConsumer, err = sarama.NewConsumerGroup(cfg.Kafka.Brokers, cfg.KafkaUserFollowsConsumer.GroupID, saramaCfg)
 	if err != nil {
 		rollback()
 		return nil, err
 	}
-	m.userFollowsHandler = NewUserFollowsConsumer(sysBoxRepo)
+	m.userFollowsHandler = NewUserFollowsConsumer(sysBoxRepo, spamObserver)
 
 	m.postConsumer, err = sarama.NewConsumerGroup(cfg.Kafka.Brokers, cfg.KafkaPostConsumer.GroupID, saramaCfg)
 	if err != nil {
 		rollback()
 		return nil, err
 	}
-	m.postHandler = NewPostsHandler(userDBRepo, postDBRepo, postESRepo, contentProcessor)
+	m.postHandler = NewPostsHandler(userDBRepo, postDBRepo, postESRepo, moderationRepo, strikeRecorder, contentProcessor)
 
 	m.commentsConsumer, err = sarama.NewConsumerGroup(cfg.Kafka.Brokers, cfg.KafkaCommentConsumer.GroupID, saramaCfg)
 	if err != nil {
 		rollback()
 		return nil, err
 	}
-	m.commentsHandler = NewCommentsHandler(actionDBRepo, postDBRepo, sysBoxRepo, contentProcessor)
+	m.commentsHandler = NewCommentsHandler(actionDBRepo, commentEditRepo, postDBRepo, sysBoxRepo, moderationRepo, strikeRecorder, spamObserver, contentProcessor)
 
 	m.likesConsumer, err = sarama.NewConsumerGroup(cfg.Kafka.Brokers, cfg.KafkaLikeConsumer.GroupID, saramaCfg)
 	if err != nil {
 		rollback()
 		return nil, err
 	}
-	m.likesHandler = NewLikesHandler(postDBRepo, sysBoxRepo)
+	m.likesHandler = NewLikesHandler(postDBRepo, sysBoxRepo, spamObserver)
 
 	m.collectionsConsumer, err = sarama.NewConsumerGroup(cfg.Kafka.Brokers, cfg.KafkaCollectionConsumer.GroupID, saramaCfg)
 	if err != nil {
@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"Cornerstone/internal/api/config"
+	"Cornerstone/internal/model"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/goccy/go-json"
+)
+
+const canalDateTimeLayout = "2006-01-02 15:04:05"
+
+// PostRedriver 将笔记按 Canal INSERT 格式重新投递到笔记 Topic，使其重新经过审核、打标与向量化
+type PostRedriver struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+func NewPostRedriver(cfg *config.Config) (*PostRedriver, error) {
+	saramaCfg := newSaramaConfig(cfg.Kafka)
+	saramaCfg.Producer.Return.Successes = true
+	saramaCfg.Producer.RequiredAcks = sarama.WaitForAll
+
+	producer, err := sarama.NewSyncProducer(cfg.Kafka.Brokers, saramaCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &PostRedriver{producer: producer, topic: cfg.KafkaPostConsumer.Topic}, nil
+}
+
+// RedrivePost 以当前数据库行构造一条 INSERT 消息，由笔记消费者按新发布流程处理
+func (r *PostRedriver) RedrivePost(_ context.Context, post *model.Post) error {
+	media, err := json.Marshal(post.MediaList)
+	if err != nil {
+		return err
+	}
+
+	isDeleted := "0"
+	if post.IsDeleted {
+		isDeleted = "1"
+	}
+	now := time.Now()
+	msg := &CanalMessage{
+		Table:   "posts",
+		PKNames: []string{"id"},
+		Type:    INSERT,
+		ES:      now.UnixMilli(),
+		TS:      now.UnixMilli(),
+		Data: []map[string]interface{}{{
+			"id":             strconv.FormatUint(post.ID, 10),
+			"user_id":        strconv.FormatUint(post.UserID, 10),
+			"status":         strconv.Itoa(int(post.Status)),
+			"title":          post.Title,
+			"content":        post.Content,
+			"plain_content":  post.PlainContent,
+			"media_list":     string(media),
+			"created_at":     post.CreatedAt.In(time.Local).Format(canalDateTimeLayout),
+			"updated_at":     post.UpdatedAt.In(time.Local).Format(canalDateTimeLayout),
+			"likes_count":    strconv.Itoa(post.LikesCount),
+			"comments_count": strconv.Itoa(post.CommentsCount),
+			"collects_count": strconv.Itoa(post.CollectsCount),
+			"is_deleted":     isDeleted,
+		}},
+	}
+	value, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: r.topic,
+		Key:   sarama.StringEncoder(strconv.FormatUint(post.ID, 10)),
+		Value: sarama.ByteEncoder(value),
+	})
+	return err
+}
+
+func (r *PostRedriver) Close() error {
+	return r.producer.Close()
+}
@@ -26,14 +26,18 @@ type PostsHandler struct {
 	userDBRepo       repository.UserRepo
 	postDBRepo       repository.PostRepo
 	postESRepo       es.PostRepo
+	moderationRepo   repository.ModerationRepo
+	strikeRecorder   StrikeRecorder
 	contentProcesser processor.ContentLLMProcessor
 }
 
-func NewPostsHandler(userDBRepo repository.UserRepo, postDBRepo repository.PostRepo, postESRepo es.PostRepo, contentProcesser processor.ContentLLMProcessor) *PostsHandler {
+func NewPostsHandler(userDBRepo repository.UserRepo, postDBRepo repository.PostRepo, postESRepo es.PostRepo, moderationRepo repository.ModerationRepo, strikeRecorder StrikeRecorder, contentProcesser processor.ContentLLMProcessor) *PostsHandler {
 	return &PostsHandler{
 		userDBRepo:       userDBRepo,
 		postDBRepo:       postDBRepo,
 		postESRepo:       postESRepo,
+		moderationRepo:   moderationRepo,
+		strikeRecorder:   strikeRecorder,
 		contentProcesser: contentProcesser,
 	}
 }
@@ -71,11 +75,18 @@ func (s *PostsHandler) logic(ctx context.Context, msg *sarama.ConsumerMessage) e
 	}
 
 	if post == nil {
-		return s.postESRepo.DeletePost(ctx, StrToUint64(canalMsg.Data[0]["id"]))
+		if err = s.postESRepo.DeletePost(ctx, StrToUint64(canalMsg.Data[0]["id"])); err != nil {
+			return err
+		}
+		markCreatorDirty(ctx, StrToUint64(canalMsg.Data[0]["user_id"]))
+		return nil
 	}
 
 	// 没有内容变更，直接覆写ES
 	if !s.checkContentIsChange(canalMsg) {
+		if s.checkStatusIsChange(canalMsg) {
+			defer markCreatorDirty(ctx, post.UserID)
+		}
 		getById, err := s.postESRepo.GetPostById(ctx, post.ID)
 		if err != nil {
 			return err
@@ -87,6 +98,10 @@ func (s *PostsHandler) logic(ctx context.Context, msg *sarama.ConsumerMessage) e
 			post.ContentVector = getById.ContentVector
 			post.AISummary = getById.AISummary
 		}
+		// 申诉通过的笔记此前在审核阶段被拦截，补齐标签与向量
+		if s.checkDenyRestored(canalMsg) && len(post.ContentVector) == 0 {
+			return s.restorePost(ctx, post, canalMsg.TS)
+		}
 		return s.getUserDetailAndIndexES(ctx, post, canalMsg.TS)
 	}
 
@@ -103,15 +118,24 @@ func (s *PostsHandler) logic(ctx context.Context, msg *sarama.ConsumerMessage) e
 		return err
 	}
 
+	saveVerdicts(ctx, s.moderationRepo, model.ModerationTargetPost, post.ID, r)
+
 	post.Status = int(atomic.LoadInt32(&r.MaxStatus))
 	if err = s.postDBRepo.UpdatePostStatus(ctx, post.ID, post.Status); err != nil {
 		return err
 	}
 	if post.Status == int(llm.ContentSafeDeny) {
 		log.WarnContext(ctx, "内容审核未通过，拦截后续处理", "post_id", post.ID)
+		saveModerationDecision(ctx, "post", post.ID, r)
+		recordStrike(ctx, s.strikeRecorder, post.UserID, model.StrikeSourcePostAudit, post.ID, r)
 		return s.getUserDetailAndIndexES(ctx, post, canalMsg.TS)
 	}
 
+	return s.enrichAndIndex(ctx, post, r, canalMsg.TS)
+}
+
+// enrichAndIndex 聚合标签、生成向量并写入 ES
+func (s *PostsHandler) enrichAndIndex(ctx context.Context, post *es.PostES, r *processor.Result, timeStamp int64) error {
 	// LLM 进行语义聚合
 	aggress, err := llm.Aggressive(ctx, &llm.TagAggressive{
 		MainTags:  r.MainTags,
@@ -143,7 +167,26 @@ func (s *PostsHandler) logic(ctx context.Context, msg *sarama.ConsumerMessage) e
 
 	post.ContentVector = vector
 
-	return s.getUserDetailAndIndexES(ctx, post, canalMsg.TS)
+	if err = s.getUserDetailAndIndexES(ctx, post, timeStamp); err != nil {
+		return err
+	}
+	markCreatorDirty(ctx, post.UserID)
+	return nil
+}
+
+// restorePost 人工复核放行后重新提取标签与向量，模型审核结论不再覆盖人工决定
+func (s *PostsHandler) restorePost(ctx context.Context, post *es.PostES, timeStamp int64) error {
+	post.UserTags = util.ExtractTags(post.PlainContent)
+
+	mediaForLLM := make([]*es.PostMediaES, len(post.Media))
+	for i := range post.Media {
+		mediaForLLM[i] = &post.Media[i]
+	}
+	r, err := s.contentProcesser.Process(ctx, post.Title, post.PlainContent, mediaForLLM, false)
+	if err != nil {
+		return err
+	}
+	return s.enrichAndIndex(ctx, post, r, timeStamp)
 }
 
 func (s *PostsHandler) toESModel(message *CanalMessage) (*es.PostES, error) {
@@ -192,6 +235,7 @@ func (s *PostsHandler) toESModel(message *CanalMessage) (*es.PostES, error) {
 		Content:       StrToString(row["content"]),
 		PlainContent:  StrToString(row["plain_content"]),
 		Media:         mediaList,
+		MediaTypes:    es.BuildMediaTypes(mediaList),
 		CreatedAt:     StrToDateTime(row["created_at"]),
 		UpdatedAt:     StrToDateTime(row["updated_at"]),
 		LikesCount:    StrToInt(row["likes_count"]),
@@ -238,3 +282,30 @@ func (s *PostsHandler) checkContentIsChange(message *CanalMessage) bool {
 	}
 	return false
 }
+
+func (s *PostsHandler) checkStatusIsChange(message *CanalMessage) bool {
+	if message.Type == UPDATE && len(message.Old) > 0 {
+		_, statusChanged := message.Old[0]["status"]
+		return statusChanged
+	}
+	return false
+}
+
+// checkDenyRestored 笔记由审核拒绝恢复为正常
+func (s *PostsHandler) checkDenyRestored(message *CanalMessage) bool {
+	if !s.checkStatusIsChange(message) {
+		return false
+	}
+	return StrToInt(message.Old[0]["status"]) == int(llm.ContentSafeDeny) &&
+		StrToInt(message.Data[0]["status"]) == consts.PostStatusNormal
+}
+
+// markCreatorDirty 标记作者画像待刷新，由定时任务聚合标签并重新生成创作者向量
+func markCreatorDirty(ctx context.Context, userID uint64) {
+	if userID == 0 {
+		return
+	}
+	if err := redis.SAdd(ctx, consts.UserCreatorDirtyKey, userID); err != nil {
+		log.WarnContext(ctx, "mark creator dirty failed", "user_id", userID, "err", err)
+	}
+}
@@ -0,0 +1,145 @@
+package kafka
+
+import (
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/minio"
+	"Cornerstone/internal/pkg/mongo"
+	"Cornerstone/internal/pkg/redis"
+	"context"
+	log "log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// profileField 待审核的资料字段
+type profileField struct {
+	column string // user_detail 列名
+	name   string // 通知中展示的字段名
+	value  string
+	safe   string // 违规时回退的安全值
+}
+
+// auditProfile 审核本次变更的昵称、简介与头像，违规字段回退为安全值并通知用户；
+// 回退成功后同步修正待写入 ES 的用户模型，避免违规资料进入搜索与笔记作者信息
+func (s *UserDetailHandler) auditProfile(ctx context.Context, msg *CanalMessage, user *es.UserES) error {
+	fields := s.changedProfileFields(msg, user)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	denied := make([]bool, len(fields))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, f := range fields {
+		g.Go(func() error {
+			var media []*es.PostMediaES
+			content := f.value
+			if f.column == "avatar_url" {
+				media = []*es.PostMediaES{{Type: consts.MimePrefixImage + "/*", URL: f.value}}
+				content = ""
+			}
+			r, err := s.contentProcessor.Process(gCtx, "", content, media, true)
+			if err != nil {
+				return err
+			}
+			denied[i] = atomic.LoadInt32(&r.MaxStatus) == int32(llm.ContentSafeDeny)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		log.ErrorContext(ctx, "profile audit failed", "userId", user.ID, "err", err)
+		return err
+	}
+
+	names := make([]string, 0, len(fields))
+	for i, f := range fields {
+		if !denied[i] {
+			continue
+		}
+		ok, err := s.userDBRepo.ResetUserDetailField(ctx, user.ID, f.column, f.value, f.safe)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		log.WarnContext(ctx, "profile field denied and reset", "userId", user.ID, "field", f.column)
+
+		switch f.column {
+		case "nickname":
+			user.Nickname = f.safe
+		case "bio":
+			user.Bio = &f.safe
+		case "avatar_url":
+			user.AvatarURL = f.safe
+			_ = minio.DeleteFile(ctx, f.value)
+		}
+		names = append(names, f.name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	idStr := strconv.FormatUint(user.ID, 10)
+	_ = redis.DeleteKey(ctx, consts.UserHomeInfoKey+idStr)
+	_ = redis.DeleteKey(ctx, consts.UserSimpleInfoKey+idStr)
+	s.sendProfileNotice(ctx, user.ID, names)
+	return nil
+}
+
+// changedProfileFields 新建资料时审核全部字段，更新时仅审核发生变化的字段，已是安全值的字段跳过
+func (s *UserDetailHandler) changedProfileFields(msg *CanalMessage, user *es.UserES) []*profileField {
+	var bio string
+	if user.Bio != nil {
+		bio = *user.Bio
+	}
+	candidates := []*profileField{
+		{column: "nickname", name: "昵称", value: user.Nickname, safe: consts.DefaultNicknamePrefix + strconv.FormatUint(user.ID, 10)},
+		{column: "bio", name: "简介", value: bio, safe: ""},
+		{column: "avatar_url", name: "头像", value: user.AvatarURL, safe: consts.DefaultAvatarURL},
+	}
+
+	fields := make([]*profileField, 0, len(candidates))
+	for _, f := range candidates {
+		if f.value == "" || f.value == f.safe {
+			continue
+		}
+		if msg.Type == UPDATE {
+			if len(msg.Old) == 0 {
+				continue
+			}
+			if _, changed := msg.Old[0][f.column]; !changed {
+				continue
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func (s *UserDetailHandler) sendProfileNotice(ctx context.Context, userID uint64, names []string) {
+	notification := &mongo.SysBoxModel{
+		ReceiverID: userID,
+		Type:       mongo.SysBoxTypeProfileAudit,
+		Content:    "你的" + strings.Join(names, "、") + "涉嫌违反社区规范，已恢复为默认设置，请重新修改。",
+		Payload: map[string]any{
+			"fields": names,
+		},
+		IsRead:    false,
+		CreatedAt: time.Now(),
+	}
+	if err := s.sysBoxRepo.CreateNotification(ctx, notification); err != nil {
+		log.ErrorContext(ctx, "failed to create profile audit notification", "userId", userID, "err", err)
+		return
+	}
+
+	channelName := consts.SysBoxUnreadNotifyChannel + strconv.FormatUint(userID, 10)
+	if err := PublishUnreadCountUpdate(ctx, channelName, userID); err != nil {
+		log.ErrorContext(ctx, "failed to publish unread count update", "receiverID", userID, "err", err)
+	}
+}
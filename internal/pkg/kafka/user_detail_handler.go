@@ -3,6 +3,8 @@ package kafka
 import (
 	"Cornerstone/internal/pkg/consts"
 	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/mongo"
+	"Cornerstone/internal/pkg/processor"
 	"Cornerstone/internal/pkg/redis"
 	"Cornerstone/internal/repository"
 	"context"
@@ -17,16 +19,29 @@ import (
 )
 
 type UserDetailHandler struct {
+	userDBRepo       repository.UserRepo
 	userDBFollowRepo repository.UserFollowRepo
 	userESRepo       es.UserRepo
 	postESRepo       es.PostRepo
+	sysBoxRepo       mongo.SysBoxRepo
+	contentProcessor processor.ContentLLMProcessor
 }
 
-func NewUserDetailHandler(userFollowDBRepo repository.UserFollowRepo, userESRepo es.UserRepo, postESRepo es.PostRepo) *UserDetailHandler {
+func NewUserDetailHandler(
+	userDBRepo repository.UserRepo,
+	userFollowDBRepo repository.UserFollowRepo,
+	userESRepo es.UserRepo,
+	postESRepo es.PostRepo,
+	sysBoxRepo mongo.SysBoxRepo,
+	contentProcessor processor.ContentLLMProcessor,
+) *UserDetailHandler {
 	return &UserDetailHandler{
+		userDBRepo:       userDBRepo,
 		userDBFollowRepo: userFollowDBRepo,
 		userESRepo:       userESRepo,
 		postESRepo:       postESRepo,
+		sysBoxRepo:       sysBoxRepo,
+		contentProcessor: contentProcessor,
 	}
 }
 
@@ -60,6 +75,12 @@ func (s *UserDetailHandler) logic(ctx context.Context, msg *sarama.ConsumerMessa
 	if err != nil {
 		return err
 	}
+	// 资料变更先审核，违规字段回退后再写入 ES
+	if canalMsg.Type == UPDATE || canalMsg.Type == INSERT {
+		if err = s.auditProfile(ctx, canalMsg, user); err != nil {
+			return err
+		}
+	}
 	if canalMsg.Type == UPDATE {
 		lockKey := consts.UserDetailESLock + strconv.FormatUint(user.ID, 10)
 		uuidStr := uuid.NewString()
@@ -71,17 +92,25 @@ func (s *UserDetailHandler) logic(ctx context.Context, msg *sarama.ConsumerMessa
 		if !lock {
 			return errors.New("acquire lock failed, retry later")
 		}
-		exist, err := s.userESRepo.Exist(ctx, user.ID)
+		current, err := s.userESRepo.GetUserById(ctx, user.ID)
 		if err != nil {
 			return err
 		}
-		if !exist {
+		if current == nil {
 			return nil
 		}
+		// 覆写时保留创作者画像，简介变更后重新生成
+		user.CreatorTags = current.CreatorTags
+		user.CreatorVector = current.CreatorVector
 		err = s.userESRepo.IndexUser(ctx, user, canalMsg.TS)
 		if err != nil {
 			return err
 		}
+		if len(canalMsg.Old) > 0 {
+			if _, bioChanged := canalMsg.Old[0]["bio"]; bioChanged {
+				markCreatorDirty(ctx, user.ID)
+			}
+		}
 		lockKey = consts.UserDetailLock + strconv.FormatUint(user.ID, 10)
 		lock, err = redis.TryLock(ctx, lockKey, uuidStr, 30*time.Second, -1)
 		defer redis.UnLock(ctx, lockKey, uuidStr)
@@ -90,7 +119,13 @@ func (s *UserDetailHandler) logic(ctx context.Context, msg *sarama.ConsumerMessa
 		}
 		return s.postESRepo.UpdatePostUserDetail(ctx, user.ID, user.Nickname, user.AvatarURL)
 	} else if canalMsg.Type == INSERT {
-		return s.userESRepo.IndexUser(ctx, user, canalMsg.TS)
+		if err = s.userESRepo.IndexUser(ctx, user, canalMsg.TS); err != nil {
+			return err
+		}
+		if user.Bio != nil && *user.Bio != "" {
+			markCreatorDirty(ctx, user.ID)
+		}
+		return nil
 	}
 	return nil
 }
@@ -14,12 +14,14 @@ import (
 )
 
 type UserFollowsHandler struct {
-	sysBoxRepo mongo.SysBoxRepo
+	sysBoxRepo   mongo.SysBoxRepo
+	spamObserver SpamObserver
 }
 
-func NewUserFollowsConsumer(sysBoxRepo mongo.SysBoxRepo) *UserFollowsHandler {
+func NewUserFollowsConsumer(sysBoxRepo mongo.SysBoxRepo, spamObserver SpamObserver) *UserFollowsHandler {
 	return &UserFollowsHandler{
-		sysBoxRepo: sysBoxRepo,
+		sysBoxRepo:   sysBoxRepo,
+		spamObserver: spamObserver,
 	}
 }
 
@@ -93,6 +95,13 @@ func (s *UserFollowsHandler) logic(ctx context.Context, msg *sarama.ConsumerMess
 		return err
 	}
 
+	if canalMsg.Type == INSERT || canalMsg.Type == DELETE {
+		at := eventTime(canalMsg)
+		for _, row := range canalMsg.Data {
+			s.spamObserver.ObserveFollow(ctx, StrToUint64(row["follower_id"]), StrToUint64(row["following_id"]), canalMsg.Type == INSERT, at)
+		}
+	}
+
 	return nil
 }
 
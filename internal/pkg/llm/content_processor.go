@@ -1,11 +1,14 @@
 package llm
 
 import (
+	"Cornerstone/internal/api/config"
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	log "log/slog"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-json"
 )
@@ -15,14 +18,17 @@ func ImageProcess(ctx context.Context, urls []string, auditOnly bool) (*ContentR
 		return GetPassResponse(), nil
 	}
 	if len(urls) > 9 {
-		return GetWarnResponse(), nil
+		res := GetWarnResponse()
+		res.Reason = "单批图片数量超过审核上限"
+		return res, nil
 	}
 
-	prompt := imageProcessPrompt
+	prompt, promptName := imageProcessPrompt, "image-process"
 	if auditOnly {
-		prompt = imageAuditOnlyPrompt
+		prompt, promptName = imageAuditOnlyPrompt, "image-audit-only"
 	}
 
+	start := time.Now()
 	resp, err := fetchModelByPicUrls(ctx, prompt, urls, 0.1)
 	if err != nil {
 		log.ErrorContext(ctx, "图像处理-AI大模型请求失败", "err", err)
@@ -33,7 +39,10 @@ func ImageProcess(ctx context.Context, urls []string, auditOnly bool) (*ContentR
 
 	if len(resp.Choices) > 0 {
 		if resp.Choices[0].StopReason == ContentSensitive {
-			return GetDenyResponse(), nil
+			res := GetDenyResponse()
+			res.Reason = sensitiveReason
+			fillAuditMeta(res, config.Cfg.LLM.VisionModel, promptName, prompt, start, "")
+			return res, nil
 		}
 
 		contentResp, err := GetContentResponse(resp.Choices[0].Content)
@@ -41,15 +50,16 @@ func ImageProcess(ctx context.Context, urls []string, auditOnly bool) (*ContentR
 			log.ErrorContext(ctx, "图像处理-AI大模型返回数据解析失败", "err", err, "resp", resp.Choices[0].Content)
 			return nil, err
 		}
+		fillAuditMeta(contentResp, config.Cfg.LLM.VisionModel, promptName, prompt, start, resp.Choices[0].Content)
 		return contentResp, nil
 	}
 	return nil, errors.New("图像处理-AI大模型返回数据为空")
 }
 
 func ContentProcess(ctx context.Context, content *Content, auditOnly bool) (*ContentResponse, error) {
-	prompt := contentProcessPrompt
+	prompt, promptName := contentProcessPrompt, "content-process"
 	if auditOnly {
-		prompt = contentAuditOnlyPrompt
+		prompt, promptName = contentAuditOnlyPrompt, "content-audit-only"
 	}
 
 	contentJSON, err := json.Marshal(content)
@@ -58,6 +68,7 @@ func ContentProcess(ctx context.Context, content *Content, auditOnly bool) (*Con
 		return nil, err
 	}
 
+	start := time.Now()
 	resp, err := fetchModel(ctx, prompt, string(contentJSON), 0.1)
 	if err != nil {
 		log.ErrorContext(ctx, "内容处理-AI大模型请求失败", "err", err)
@@ -68,7 +79,10 @@ func ContentProcess(ctx context.Context, content *Content, auditOnly bool) (*Con
 
 	if len(resp.Choices) > 0 {
 		if resp.Choices[0].StopReason == ContentSensitive {
-			return GetDenyResponse(), nil
+			res := GetDenyResponse()
+			res.Reason = sensitiveReason
+			fillAuditMeta(res, config.Cfg.LLM.TextModel, promptName, prompt, start, "")
+			return res, nil
 		}
 
 		contentResp, err := GetContentResponse(resp.Choices[0].Content)
@@ -76,6 +90,7 @@ func ContentProcess(ctx context.Context, content *Content, auditOnly bool) (*Con
 			log.ErrorContext(ctx, "内容处理-AI大模型返回数据解析失败", "err", err, "resp", resp.Choices[0].Content)
 			return nil, err
 		}
+		fillAuditMeta(contentResp, config.Cfg.LLM.TextModel, promptName, prompt, start, resp.Choices[0].Content)
 		return contentResp, nil
 	}
 
@@ -126,6 +141,20 @@ func GetVector(ctx context.Context, content *Content, tags []string, summary str
 	return vector, nil
 }
 
+// GetCreatorVector 由简介与已发布笔记的聚合标签生成创作者向量，二者均为空时返回 nil
+func GetCreatorVector(ctx context.Context, bio string, mainTags, tags []string) ([]float32, error) {
+	if bio == "" && len(mainTags) == 0 && len(tags) == 0 {
+		return nil, nil
+	}
+	s := fmt.Sprintf("Bio: %s\nMainTags: %s\nTags: %s\n", bio, strings.Join(mainTags, ","), strings.Join(tags, ","))
+	vector, err := fetchModelEmbedding(ctx, s)
+	if err != nil {
+		log.ErrorContext(ctx, "创作者画像-AI大模型向量获取失败", "err", err)
+		return nil, err
+	}
+	return vector, nil
+}
+
 func GetVectorByString(ctx context.Context, s string) ([]float32, error) {
 	if s == "" {
 		return nil, errors.New("内容处理-AI大模型返回数据为空")
@@ -138,3 +167,21 @@ func GetVectorByString(ctx context.Context, s string) ([]float32, error) {
 	log.InfoContext(ctx, "内容处理-AI大模型向量获取成功", "vector", vector)
 	return vector, nil
 }
+
+// sensitiveReason 模型侧安全拦截时没有返回内容，使用固定原因
+const sensitiveReason = "模型服务触发内容安全拦截"
+
+// fillAuditMeta 记录审核所用模型、提示词版本、耗时（含排队）与原始返回
+func fillAuditMeta(res *ContentResponse, model, promptName, prompt string, start time.Time, raw string) {
+	res.Model = model
+	res.PromptVersion = promptVersion(promptName, prompt)
+	res.Latency = time.Since(start)
+	res.Raw = raw
+}
+
+// promptVersion 以提示词内容摘要作为版本号，提示词文件变更后版本随之变化
+func promptVersion(name, prompt string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(prompt))
+	return fmt.Sprintf("%s@%08x", name, h.Sum32())
+}
@@ -2,6 +2,7 @@ package llm
 
 import (
 	"strings"
+	"time"
 
 	"github.com/goccy/go-json"
 )
@@ -39,17 +40,36 @@ var mapContentSafe = map[string]int{
 }
 
 type ContentResponse struct {
-	Status  int
-	MainTag string
-	Tags    []string
-	Summary string
+	Status     int
+	MainTag    string
+	Tags       []string
+	Summary    string
+	Reason     string
+	Confidence float64
+
+	// 审核元信息，用于留存审核记录
+	Model         string
+	PromptVersion string
+	Latency       time.Duration
+	Raw           string
+}
+
+// ModerationDecision 模型审核结论快照，作者申诉时附带给审核员
+type ModerationDecision struct {
+	Status    int       `json:"status"`
+	Reasons   []string  `json:"reasons,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Summaries []string  `json:"summaries,omitempty"`
+	AuditedAt time.Time `json:"audited_at"`
 }
 
 type ReturnResponse struct {
-	Status  string   `json:"status"`
-	MainTag string   `json:"main_tag"`
-	Tags    []string `json:"tags"`
-	Summary string   `json:"summary"`
+	Status     string   `json:"status"`
+	Reason     string   `json:"reason"`
+	Confidence float64  `json:"confidence"`
+	MainTag    string   `json:"main_tag"`
+	Tags       []string `json:"tags"`
+	Summary    string   `json:"summary"`
 }
 
 func GetContentResponse(s string) (*ContentResponse, error) {
@@ -66,10 +86,12 @@ func GetContentResponse(s string) (*ContentResponse, error) {
 	}
 
 	res := &ContentResponse{
-		Status:  mapContentSafe[temp.Status],
-		MainTag: temp.MainTag,
-		Tags:    temp.Tags,
-		Summary: temp.Summary,
+		Status:     mapContentSafe[temp.Status],
+		MainTag:    temp.MainTag,
+		Tags:       temp.Tags,
+		Summary:    temp.Summary,
+		Reason:     temp.Reason,
+		Confidence: temp.Confidence,
 	}
 
 	// 校验status
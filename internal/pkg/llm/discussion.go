@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	log "log/slog"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// DiscussionComment 参与讨论总结的评论
+type DiscussionComment struct {
+	ID      uint64 `json:"id"`
+	Content string `json:"content"`
+	Likes   int    `json:"likes"`
+	IsReply bool   `json:"is_reply"`
+}
+
+// DiscussionInput 讨论总结请求
+type DiscussionInput struct {
+	Title    string               `json:"title"`
+	Comments []*DiscussionComment `json:"comments"`
+}
+
+// DiscussionViewpoint 评论区的一个主要观点及其代表评论
+type DiscussionViewpoint struct {
+	Viewpoint  string   `json:"viewpoint"`
+	CommentIDs []uint64 `json:"comment_ids"`
+}
+
+// DiscussionSummary 讨论总结结果
+type DiscussionSummary struct {
+	Overview   string                 `json:"overview"`
+	Viewpoints []*DiscussionViewpoint `json:"viewpoints"`
+}
+
+// SummarizeDiscussion 将评论区提炼为概述与主要观点，代表评论只保留输入中存在的 ID
+func SummarizeDiscussion(ctx context.Context, input *DiscussionInput) (*DiscussionSummary, error) {
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		log.ErrorContext(ctx, "讨论总结-AI大模型请求数据序列化失败", "err", err)
+		return nil, err
+	}
+
+	resp, err := fetchModel(ctx, discussionPrompt, string(inputJSON), 0.3)
+	if err != nil {
+		log.ErrorContext(ctx, "讨论总结-AI大模型请求失败", "err", err)
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("讨论总结-AI大模型返回数据为空")
+	}
+	if resp.Choices[0].StopReason == ContentSensitive {
+		return nil, errors.New("讨论总结-内容命中敏感拦截")
+	}
+
+	cleaned := strings.TrimSpace(resp.Choices[0].Content)
+	cleaned = strings.TrimPrefix(cleaned, "```json")
+	cleaned = strings.TrimPrefix(cleaned, "```")
+	cleaned = strings.TrimSuffix(cleaned, "```")
+	cleaned = strings.TrimSpace(cleaned)
+
+	var summary DiscussionSummary
+	if err = json.Unmarshal([]byte(cleaned), &summary); err != nil {
+		log.ErrorContext(ctx, "讨论总结-AI大模型返回数据解析失败", "err", err, "resp", resp.Choices[0].Content)
+		return nil, err
+	}
+	log.InfoContext(ctx, "讨论总结-AI大模型请求成功", "viewpoints", len(summary.Viewpoints))
+
+	// 过滤模型编造的评论 ID，没有代表评论的观点一并丢弃
+	known := make(map[uint64]bool, len(input.Comments))
+	for _, c := range input.Comments {
+		known[c.ID] = true
+	}
+	viewpoints := make([]*DiscussionViewpoint, 0, len(summary.Viewpoints))
+	for _, v := range summary.Viewpoints {
+		if v == nil || strings.TrimSpace(v.Viewpoint) == "" {
+			continue
+		}
+		ids := make([]uint64, 0, len(v.CommentIDs))
+		for _, id := range v.CommentIDs {
+			if known[id] {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		v.CommentIDs = ids
+		viewpoints = append(viewpoints, v)
+	}
+	summary.Viewpoints = viewpoints
+	return &summary, nil
+}
@@ -20,6 +20,7 @@ var (
 	imageProcessPrompt     string
 	imageAuditOnlyPrompt   string
 	searchPrompt           string
+	discussionPrompt       string
 )
 
 func InitLLM() error {
@@ -42,6 +43,7 @@ func InitLLM() error {
 	imageProcessPrompt = readPrompt(promptPath.ImageProcess)
 	imageAuditOnlyPrompt = readPrompt(promptPath.ImageAuditOnly)
 	searchPrompt = readPrompt(promptPath.Search)
+	discussionPrompt = readPrompt(promptPath.DiscussionSummary)
 
 	log.Info("LLM Initial Success")
 	return nil
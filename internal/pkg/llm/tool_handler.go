@@ -4,11 +4,13 @@ import (
 	"Cornerstone/internal/api/config"
 	"Cornerstone/internal/pkg/consts"
 	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/util"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	log "log/slog"
 	"net/url"
 	"regexp"
@@ -103,19 +105,34 @@ func (s *ToolHandler) SearchCommunityPosts(ctx context.Context, argsJson string)
 	builder.WriteString("以下是为你找到的站内相关笔记：\n\n")
 
 	for i, post := range posts {
-		displayContent := post.Content
-		if len(displayContent) > 300 {
-			displayContent = displayContent[:300] + "..."
-		}
+		displayContent := util.TruncateRunes(post.PlainContent, 300)
 
-		item := fmt.Sprintf("### 笔记 %d\n- **ID**: %d\n- **标题**: %s\n- **作者**: %s\n- **内容**: %s\n- **AI总结**: %s\n---\n",
-			i+1, post.ID, post.Title, post.UserNickname, displayContent, post.AISummary)
+		item := fmt.Sprintf("### 笔记 %d\n- **ID**: %d\n- **标题**: %s\n- **作者**: %s\n- **内容**: %s\n",
+			i+1, post.ID, post.Title, post.UserNickname, displayContent)
 		builder.WriteString(item)
+		if snippets := postSnippets(post); len(snippets) > 0 {
+			builder.WriteString("- **命中片段**: " + strings.Join(snippets, " / ") + "\n")
+		}
+		builder.WriteString(fmt.Sprintf("- **AI总结**: %s\n---\n", post.AISummary))
 	}
 	log.InfoContext(ctx, "SearchCommunityPosts", "query", args.Query, "results", builder.String())
 	return builder.String(), nil
 }
 
+// postSnippets 将检索命中片段还原为纯文本，命中词以 ** 标出
+func postSnippets(post *es.PostES) []string {
+	if post.Highlight == nil {
+		return nil
+	}
+	res := make([]string, 0, len(post.Highlight.Snippets))
+	for _, snippet := range post.Highlight.Snippets {
+		snippet = strings.ReplaceAll(snippet, es.HighlightPreTag, "**")
+		snippet = strings.ReplaceAll(snippet, es.HighlightPostTag, "**")
+		res = append(res, html.UnescapeString(snippet))
+	}
+	return res
+}
+
 // GetPostURL 动态生成帖子访问链接
 func (s *ToolHandler) GetPostURL(ctx context.Context, argsJson string) (string, error) {
 	var args struct {
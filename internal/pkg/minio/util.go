@@ -43,6 +43,19 @@ func UploadTempFile(ctx context.Context, objectName string, reader io.Reader, co
 	return uploadInfo.Key, nil
 }
 
+// GetFile 读取MinIO中的文件，调用方负责关闭
+func GetFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	if Client == nil {
+		return nil, fmt.Errorf("minio client is not initialized")
+	}
+
+	obj, err := Client.GetObject(ctx, MainBucket, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	return obj, nil
+}
+
 // DeleteFile 删除MinIO中的文件
 func DeleteFile(ctx context.Context, objectName string) error {
 	if Client == nil {
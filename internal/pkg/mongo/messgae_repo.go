@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -13,6 +14,8 @@ type MessageRepo interface {
 	GetHistory(ctx context.Context, convID uint64, lastSeq uint64, pageSize int) ([]*Message, error)
 	SyncMessages(ctx context.Context, convID uint64, lastSeq uint64, pageSize int) ([]*Message, error)
 	GetMessageBySeq(ctx context.Context, convID uint64, seq uint64) (*Message, error)
+	SetReaction(ctx context.Context, convID uint64, seq uint64, userID uint64, emoji string) (bool, error)
+	RemoveReaction(ctx context.Context, convID uint64, seq uint64, userID uint64) (bool, error)
 }
 
 type messageRepoImpl struct {
@@ -102,3 +105,31 @@ func (s *messageRepoImpl) GetMessageBySeq(ctx context.Context, convID uint64, se
 	}
 	return &msg, nil
 }
+
+// SetReaction 写入或替换用户对消息的表情回应，消息不存在时返回 false
+func (s *messageRepoImpl) SetReaction(ctx context.Context, convID uint64, seq uint64, userID uint64, emoji string) (bool, error) {
+	filter := bson.M{
+		"conversation_id": convID,
+		"seq":             seq,
+	}
+	update := bson.M{"$set": bson.M{"reactions." + strconv.FormatUint(userID, 10): emoji}}
+	res, err := s.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// RemoveReaction 取消用户对消息的表情回应，消息不存在时返回 false
+func (s *messageRepoImpl) RemoveReaction(ctx context.Context, convID uint64, seq uint64, userID uint64) (bool, error) {
+	filter := bson.M{
+		"conversation_id": convID,
+		"seq":             seq,
+	}
+	update := bson.M{"$unset": bson.M{"reactions." + strconv.FormatUint(userID, 10): ""}}
+	res, err := s.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
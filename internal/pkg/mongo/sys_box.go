@@ -6,12 +6,20 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// 系统通知类型
+const (
+	SysBoxTypeReportResult int8 = 6 // 举报处理结果
+	SysBoxTypeAppealResult int8 = 7 // 申诉处理结果
+	SysBoxTypeStrike       int8 = 8 // 违规与处罚通知
+	SysBoxTypeProfileAudit int8 = 9 // 资料审核结果
+)
+
 // SysBoxModel 系统通知模型
 type SysBoxModel struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	ReceiverID uint64             `bson:"receiver_id" json:"receiver_id"`   // 消息接收者ID
 	SenderID   uint64             `bson:"sender_id" json:"sender_id"`       // 动作发起者ID (系统通知可为0)
-	Type       int8               `bson:"type" json:"type"`                 // 通知类型: 1-帖子点赞, 2-帖子收藏, 3-帖子评论, 4-评论点赞, 5-被关注
+	Type       int8               `bson:"type" json:"type"`                 // 通知类型: 1-帖子点赞, 2-帖子收藏, 3-帖子评论, 4-评论点赞, 5-被关注, 6-举报处理结果, 7-申诉处理结果, 8-违规处罚, 9-资料审核结果
 	TargetID   uint64             `bson:"target_id" json:"target_id"`       // 关联的目标ID (如帖子ID、评论ID)
 	Content    string             `bson:"content" json:"content"`           // 通知文案预览或评论片段
 	Payload    map[string]any     `bson:"payload,omitempty" json:"payload"` // 额外元数据 (可选，如帖子标题快照)
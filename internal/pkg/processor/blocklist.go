@@ -0,0 +1,195 @@
+package processor
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	"fmt"
+	log "log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// blocklistRefreshInterval 兜底全量刷新间隔，防止漏收重载通知
+const blocklistRefreshInterval = 5 * time.Minute
+
+// BlockHit 屏蔽规则命中结果
+type BlockHit struct {
+	Version  string
+	Action   int8
+	RuleIDs  []uint64
+	Patterns []string
+}
+
+// Blocklist 送审前的本地屏蔽词过滤，规则变更时通过 Redis 通知热加载
+type Blocklist struct {
+	repo    repository.BlockRuleRepo
+	matcher atomic.Pointer[blockMatcher]
+}
+
+// blockMatcher 某一版本规则构建出的只读匹配器
+type blockMatcher struct {
+	version    string
+	wordAC     *util.ACMatcher
+	wordRules  []*model.BlockRule
+	pinyinAC   *util.ACMatcher
+	pinyinRule []*model.BlockRule
+	regexes    []*regexp.Regexp
+	regexRules []*model.BlockRule
+}
+
+func NewBlocklist(repo repository.BlockRuleRepo) *Blocklist {
+	return &Blocklist{repo: repo}
+}
+
+// Watch 监听重载通知并定期刷新，阻塞直到 ctx 结束
+func (b *Blocklist) Watch(ctx context.Context) error {
+	watchReload(ctx, consts.BlocklistReloadChannel, b.Reload)
+	return nil
+}
+
+// Reload 从数据库重建匹配器并原子替换
+func (b *Blocklist) Reload(ctx context.Context) error {
+	rules, err := b.repo.GetEnabledBlockRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	m := &blockMatcher{version: time.Now().Format("20060102150405")}
+	var words, pinyins []string
+	for _, rule := range rules {
+		switch rule.MatchType {
+		case model.BlockMatchWord:
+			folded := util.FoldForMatch(rule.Pattern)
+			if folded == "" {
+				continue
+			}
+			words = append(words, folded)
+			m.wordRules = append(m.wordRules, rule)
+			if rule.FoldPinyin && containsHan(folded) {
+				pinyins = append(pinyins, padPinyin(util.ToPinyin(folded)))
+				m.pinyinRule = append(m.pinyinRule, rule)
+			}
+		case model.BlockMatchRegex:
+			// 匹配时文本已转为小写，规则忽略大小写以免含大写字母的规则永远无法命中
+			re, err := regexp.Compile("(?i)" + rule.Pattern)
+			if err != nil {
+				log.WarnContext(ctx, "skip invalid blocklist regex", "ruleId", rule.ID, "err", err)
+				continue
+			}
+			m.regexes = append(m.regexes, re)
+			m.regexRules = append(m.regexRules, rule)
+		}
+	}
+	m.wordAC = util.NewACMatcher(words)
+	m.pinyinAC = util.NewACMatcher(pinyins)
+
+	b.matcher.Store(m)
+	log.InfoContext(ctx, "blocklist loaded", "version", m.version, "words", len(words), "pinyin", len(pinyins), "regex", len(m.regexes))
+	return nil
+}
+
+// Match 匹配文本，未命中或规则未加载时返回 nil
+func (b *Blocklist) Match(text string) *BlockHit {
+	if b == nil || text == "" {
+		return nil
+	}
+	m := b.matcher.Load()
+	if m == nil {
+		return nil
+	}
+
+	hit := &BlockHit{Version: m.version}
+	seen := make(map[uint64]struct{})
+	add := func(rule *model.BlockRule) {
+		if _, ok := seen[rule.ID]; ok {
+			return
+		}
+		seen[rule.ID] = struct{}{}
+		hit.RuleIDs = append(hit.RuleIDs, rule.ID)
+		hit.Patterns = append(hit.Patterns, rule.Pattern)
+		if rule.Action > hit.Action {
+			hit.Action = rule.Action
+		}
+	}
+
+	folded := util.FoldForMatch(text)
+	for _, idx := range m.wordAC.Match(folded) {
+		add(m.wordRules[idx])
+	}
+	if len(m.pinyinRule) > 0 {
+		for _, idx := range m.pinyinAC.Match(padPinyin(util.ToPinyin(folded))) {
+			add(m.pinyinRule[idx])
+		}
+	}
+	if len(m.regexes) > 0 {
+		simplified := strings.ToLower(util.ToSimplified(text))
+		for i, re := range m.regexes {
+			if re.MatchString(simplified) {
+				add(m.regexRules[i])
+			}
+		}
+	}
+
+	if len(hit.RuleIDs) == 0 {
+		return nil
+	}
+	return hit
+}
+
+// toResponse 将命中结果转为审核结论，与模型结论一同留存
+func (h *BlockHit) toResponse() *llm.ContentResponse {
+	return &llm.ContentResponse{
+		Status:        int(h.Action),
+		Reason:        fmt.Sprintf("命中屏蔽规则: %s", strings.Join(h.Patterns, "、")),
+		Confidence:    1,
+		Model:         "blocklist",
+		PromptVersion: "blocklist@" + h.Version,
+	}
+}
+
+// padPinyin 首尾补分隔符，使规则只能在音节边界上命中，避免 ji 命中 jintian
+func padPinyin(py string) string {
+	return string(util.PinyinSeparator) + py + string(util.PinyinSeparator)
+}
+
+func containsHan(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchReload 订阅重载通知，收到通知或到达兜底间隔时执行 reload，阻塞直到 ctx 结束
+func watchReload(ctx context.Context, channel string, reload func(ctx context.Context) error) {
+	pubsub := redis.Subscribe(ctx, channel)
+	defer pubsub.Close()
+	ch := pubsub.Channel()
+
+	ticker := time.NewTicker(blocklistRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-ticker.C:
+		}
+		if err := reload(ctx); err != nil {
+			log.ErrorContext(ctx, "reload blocklist failed", "channel", channel, "err", err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package processor
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/repository"
+	"context"
+	"slices"
+	"testing"
+)
+
+type fakeBlockRuleRepo struct {
+	repository.BlockRuleRepo
+	rules []*model.BlockRule
+}
+
+func (f *fakeBlockRuleRepo) GetEnabledBlockRules(context.Context) ([]*model.BlockRule, error) {
+	return f.rules, nil
+}
+
+func newTestBlocklist(t *testing.T, rules ...*model.BlockRule) *Blocklist {
+	t.Helper()
+	b := NewBlocklist(&fakeBlockRuleRepo{rules: rules})
+	if err := b.Reload(context.Background()); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	return b
+}
+
+func wordRule(id uint64, pattern string, foldPinyin bool) *model.BlockRule {
+	return &model.BlockRule{ID: id, Pattern: pattern, MatchType: model.BlockMatchWord, Action: model.BlockActionDeny, FoldPinyin: foldPinyin}
+}
+
+func regexRule(id uint64, pattern string) *model.BlockRule {
+	return &model.BlockRule{ID: id, Pattern: pattern, MatchType: model.BlockMatchRegex, Action: model.BlockActionReview}
+}
+
+func TestBlocklistMatchNotLoaded(t *testing.T) {
+	var nilList *Blocklist
+	if hit := nilList.Match("加微信"); hit != nil {
+		t.Fatalf("nil blocklist hit = %+v", hit)
+	}
+	if hit := NewBlocklist(&fakeBlockRuleRepo{}).Match("加微信"); hit != nil {
+		t.Fatalf("unloaded blocklist hit = %+v", hit)
+	}
+}
+
+func TestBlocklistMatchFold(t *testing.T) {
+	b := newTestBlocklist(t, wordRule(1, "加微信", false), wordRule(2, "发财", false), wordRule(3, "vx", false))
+
+	cases := []struct {
+		text string
+		want []uint64
+	}{
+		{"快来加 微-信 领红包", []uint64{1}},
+		{"恭喜發財", []uint64{2}},
+		{"私聊ＶＸ", []uint64{3}},
+		{"私聊 V.X", []uint64{3}},
+		{"今天天气不错", nil},
+	}
+	for _, c := range cases {
+		hit := b.Match(c.text)
+		if c.want == nil {
+			if hit != nil {
+				t.Errorf("Match(%q) = %v, want no hit", c.text, hit.RuleIDs)
+			}
+			continue
+		}
+		if hit == nil || !slices.Equal(hit.RuleIDs, c.want) {
+			t.Errorf("Match(%q) = %+v, want rules %v", c.text, hit, c.want)
+		}
+	}
+}
+
+func TestBlocklistMatchPinyin(t *testing.T) {
+	b := newTestBlocklist(t, wordRule(1, "微信", true), wordRule(2, "鸡", true), wordRule(3, "代购", false))
+
+	cases := []struct {
+		text string
+		want []uint64
+	}{
+		// 同音字按拼音命中
+		{"加威信聊", []uint64{1}},
+		{"买几个", []uint64{2}},
+		// 只在音节边界上命中，ji 不能命中 jin
+		{"今天", nil},
+		// 未开启拼音折叠的规则不做同音匹配
+		{"带够了", nil},
+	}
+	for _, c := range cases {
+		hit := b.Match(c.text)
+		if c.want == nil {
+			if hit != nil {
+				t.Errorf("Match(%q) = %v, want no hit", c.text, hit.RuleIDs)
+			}
+			continue
+		}
+		if hit == nil || !slices.Equal(hit.RuleIDs, c.want) {
+			t.Errorf("Match(%q) = %+v, want rules %v", c.text, hit, c.want)
+		}
+	}
+}
+
+func TestBlocklistMatchRegex(t *testing.T) {
+	b := newTestBlocklist(t, regexRule(1, `QQ\d{5,}`), regexRule(2, `([`))
+
+	if hit := b.Match("加qq123456"); hit == nil || !slices.Equal(hit.RuleIDs, []uint64{1}) {
+		t.Fatalf("lowercase text hit = %+v, want rule 1", hit)
+	}
+	if hit := b.Match("加QQ123456"); hit == nil || !slices.Equal(hit.RuleIDs, []uint64{1}) {
+		t.Fatalf("uppercase text hit = %+v, want rule 1", hit)
+	}
+	if hit := b.Match("qq123"); hit != nil {
+		t.Fatalf("short number hit = %v, want no hit", hit.RuleIDs)
+	}
+}
+
+func TestBlocklistMatchMergesRules(t *testing.T) {
+	b := newTestBlocklist(t, wordRule(1, "微信", true), regexRule(2, `微信\d+`))
+
+	hit := b.Match("微信123")
+	if hit == nil {
+		t.Fatal("want hit")
+	}
+	// 同一规则的字面与拼音命中只记一次，动作取最严重的
+	if !slices.Equal(hit.RuleIDs, []uint64{1, 2}) {
+		t.Fatalf("rule ids = %v, want [1 2]", hit.RuleIDs)
+	}
+	if hit.Action != model.BlockActionDeny {
+		t.Fatalf("action = %d, want %d", hit.Action, model.BlockActionDeny)
+	}
+}
@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"Cornerstone/internal/model"
 	"Cornerstone/internal/pkg/consts"
 	"Cornerstone/internal/pkg/es"
 	"Cornerstone/internal/pkg/llm"
@@ -21,24 +22,56 @@ import (
 
 var ErrAuditDenyTriggered = errors.New("audit deny detected, cancelling other batches")
 
+// 审核来源
+const (
+	VerdictSourceText       = "text"
+	VerdictSourceImageBatch = "image_batch"
+	VerdictSourceVideoFrame = "video_frame"
+	VerdictSourceAudio      = "audio"
+	VerdictSourceBlocklist  = "blocklist"
+	VerdictSourceMediaHash  = "media_hash"
+)
+
+// Verdict 单次模型调用的审核结论
+type Verdict struct {
+	Source   string   // 审核来源
+	Refs     []string // 送审的媒体地址，视频帧为 "视频地址#帧序号"
+	Input    string   // 送审文本，音频为转写文本
+	Response *llm.ContentResponse
+}
+
 type Result struct {
 	sync.Mutex
-	StopChan       chan struct{}
-	MaxStatus      int32
-	MainTags       []string
-	Tags           []string
-	Summaries      []string
-	allPendingUrls []string
+	StopChan      chan struct{}
+	MaxStatus     int32
+	MainTags      []string
+	Tags          []string
+	Summaries     []string
+	Verdicts      []*Verdict
+	pendingImages []*pendingImage
+}
+
+// pendingImage 待批量审核的图片，图片与封面合并送审，视频帧按视频分批送审
+type pendingImage struct {
+	url   string
+	group string
+	ref   string
 }
 
 type ContentLLMProcessor interface {
 	Process(ctx context.Context, title, content string, media []*es.PostMediaES, auditOnly bool) (*Result, error)
 }
 
-type contentLLMProcessorImpl struct{}
+type contentLLMProcessorImpl struct {
+	blocklist   *Blocklist
+	mediaHashes *MediaHashBlocklist
+}
 
-func NewContentLLMProcessor() ContentLLMProcessor {
-	return &contentLLMProcessorImpl{}
+func NewContentLLMProcessor(blocklist *Blocklist, mediaHashes *MediaHashBlocklist) ContentLLMProcessor {
+	return &contentLLMProcessorImpl{
+		blocklist:   blocklist,
+		mediaHashes: mediaHashes,
+	}
 }
 
 func (s *contentLLMProcessorImpl) Process(ctx context.Context, title, content string, media []*es.PostMediaES, auditOnly bool) (*Result, error) {
@@ -48,6 +81,12 @@ func (s *contentLLMProcessorImpl) Process(ctx context.Context, title, content st
 
 	log.InfoContext(ctx, "ContentLLMProcessor started", "media_count", len(media), "audit_only", auditOnly)
 
+	// 屏蔽词预过滤，命中拒绝规则时不再调用模型
+	if s.checkBlocklist(res, title, content) {
+		log.InfoContext(ctx, "process finished with blocklist deny")
+		return res, nil
+	}
+
 	gCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -55,7 +94,7 @@ func (s *contentLLMProcessorImpl) Process(ctx context.Context, title, content st
 
 	// 文本处理
 	g.Go(func() error {
-		return s.handleText(gCtx, title, content, res, cancel, auditOnly)
+		return s.handleText(gCtx, title, content, VerdictSourceText, "", res, cancel, auditOnly)
 	})
 
 	// 媒体处理
@@ -95,7 +134,7 @@ func (s *contentLLMProcessorImpl) Process(ctx context.Context, title, content st
 	return res, nil
 }
 
-func (s *contentLLMProcessorImpl) handleText(ctx context.Context, title, content string, res *Result, cancel context.CancelFunc, auditOnly bool) error {
+func (s *contentLLMProcessorImpl) handleText(ctx context.Context, title, content, source, ref string, res *Result, cancel context.CancelFunc, auditOnly bool) error {
 	if title == "" && content == "" {
 		return nil
 	}
@@ -111,12 +150,25 @@ func (s *contentLLMProcessorImpl) handleText(ctx context.Context, title, content
 		return err
 	}
 
+	verdict := &Verdict{Source: source, Input: content, Response: processed}
+	if title != "" {
+		verdict.Input = title + "\n" + content
+	}
+	if ref != "" {
+		verdict.Refs = []string{ref}
+	}
+	s.addVerdict(res, verdict)
 	s.updateMaxStatus(res, int32(processed.Status), cancel)
 	s.updateResultSlice(res, processed)
 	return nil
 }
 
 func (s *contentLLMProcessorImpl) collectAllFeatures(ctx context.Context, media []*es.PostMediaES, res *Result, cancel context.CancelFunc, auditOnly bool) error {
+	// 图片指纹预过滤，命中已知违规图片时不再送审
+	if s.checkStoredMediaHashes(ctx, media, res, cancel) {
+		return nil
+	}
+
 	g, gCtx := errgroup.WithContext(ctx)
 
 	for _, m := range media {
@@ -131,15 +183,11 @@ func (s *contentLLMProcessorImpl) collectAllFeatures(ctx context.Context, media
 			typePrefix := strings.Split(m.Type, "/")[0]
 			switch typePrefix {
 			case consts.MimePrefixImage:
-				res.Lock()
-				res.allPendingUrls = append(res.allPendingUrls, minio.GetForcePublicURL(m.URL))
-				res.Unlock()
+				s.addPendingImage(res, minio.GetForcePublicURL(m.URL), VerdictSourceImageBatch, m.URL)
 				return nil
 			case consts.MimePrefixVideo:
 				if m.Cover != nil && *m.Cover != "" {
-					res.Lock()
-					res.allPendingUrls = append(res.allPendingUrls, minio.GetForcePublicURL(*m.Cover))
-					res.Unlock()
+					s.addPendingImage(res, minio.GetForcePublicURL(*m.Cover), VerdictSourceImageBatch, *m.Cover)
 				}
 				return s.processVideoItem(gCtx, g, m, res, cancel, auditOnly)
 			case consts.MimePrefixAudio:
@@ -163,7 +211,7 @@ func (s *contentLLMProcessorImpl) processVideoItem(ctx context.Context, g *errgr
 		if err != nil {
 			return err
 		}
-		for _, frame := range frames {
+		for i, frame := range frames {
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -177,6 +225,10 @@ func (s *contentLLMProcessorImpl) processVideoItem(ctx context.Context, g *errgr
 				default:
 				}
 
+				if s.checkFrameHash(ctx, m.URL, i, frame, res, cancel) {
+					return nil
+				}
+
 				f, err := util.ResizeImage(frame, 768, 0, 85)
 				if err != nil {
 					return err
@@ -191,9 +243,7 @@ func (s *contentLLMProcessorImpl) processVideoItem(ctx context.Context, g *errgr
 				if err != nil {
 					return err
 				}
-				res.Lock()
-				res.allPendingUrls = append(res.allPendingUrls, minio.GetTempFileURL(fileName, true))
-				res.Unlock()
+				s.addPendingImage(res, minio.GetTempFileURL(fileName, true), m.URL, fmt.Sprintf("%s#%d", m.URL, i))
 				return nil
 			})
 		}
@@ -224,7 +274,7 @@ func (s *contentLLMProcessorImpl) processVideoItem(ctx context.Context, g *errgr
 		if err != nil {
 			return err
 		}
-		return s.handleText(ctx, "", text, res, cancel, auditOnly)
+		return s.handleText(ctx, "", text, VerdictSourceAudio, m.URL, res, cancel, auditOnly)
 	})
 	return nil
 }
@@ -236,48 +286,148 @@ func (s *contentLLMProcessorImpl) processAudioItem(ctx context.Context, g *errgr
 		if err != nil {
 			return err
 		}
-		return s.handleText(ctx, "", text, res, cancel, auditOnly)
+		return s.handleText(ctx, "", text, VerdictSourceAudio, m.URL, res, cancel, auditOnly)
 	})
 	return nil
 }
 
 func (s *contentLLMProcessorImpl) performBatchImageAudit(ctx context.Context, res *Result, cancel context.CancelFunc, auditOnly bool) error {
-	urls := res.allPendingUrls
-	if len(urls) == 0 {
+	if len(res.pendingImages) == 0 {
 		return nil
 	}
 
+	// 按来源分组，图片与封面一组，每个视频的抽帧各自一组，便于定位触发判定的视频帧
+	groups := make(map[string][]*pendingImage)
+	order := make([]string, 0)
+	for _, p := range res.pendingImages {
+		if _, ok := groups[p.group]; !ok {
+			order = append(order, p.group)
+		}
+		groups[p.group] = append(groups[p.group], p)
+	}
+
 	const batchSize = 5
 	g, gCtx := errgroup.WithContext(ctx)
 
-	for i := 0; i < len(urls); i += batchSize {
-		end := i + batchSize
-		if end > len(urls) {
-			end = len(urls)
+	for _, key := range order {
+		items := groups[key]
+		source := VerdictSourceVideoFrame
+		if key == VerdictSourceImageBatch {
+			source = VerdictSourceImageBatch
 		}
-		batch := urls[i:end]
 
-		g.Go(func() error {
-			select {
-			case <-gCtx.Done():
-				return gCtx.Err()
-			default:
+		for i := 0; i < len(items); i += batchSize {
+			end := i + batchSize
+			if end > len(items) {
+				end = len(items)
 			}
+			batch := items[i:end]
 
-			processed, err := llm.ImageProcess(gCtx, batch, auditOnly)
-			if err != nil {
-				return err
-			}
+			g.Go(func() error {
+				select {
+				case <-gCtx.Done():
+					return gCtx.Err()
+				default:
+				}
 
-			s.updateMaxStatus(res, int32(processed.Status), cancel)
-			s.updateResultSlice(res, processed)
+				urls := make([]string, 0, len(batch))
+				refs := make([]string, 0, len(batch))
+				for _, p := range batch {
+					urls = append(urls, p.url)
+					refs = append(refs, p.ref)
+				}
 
-			return nil
-		})
+				processed, err := llm.ImageProcess(gCtx, urls, auditOnly)
+				if err != nil {
+					return err
+				}
+
+				s.addVerdict(res, &Verdict{Source: source, Refs: refs, Response: processed})
+				s.updateMaxStatus(res, int32(processed.Status), cancel)
+				s.updateResultSlice(res, processed)
+
+				return nil
+			})
+		}
 	}
 	return g.Wait()
 }
 
+// checkBlocklist 执行屏蔽词匹配，命中拒绝规则时返回 true，命中复核规则时先将状态置为警告
+func (s *contentLLMProcessorImpl) checkBlocklist(res *Result, title, content string) bool {
+	input := content
+	if title != "" {
+		input = title + "\n" + content
+	}
+	hit := s.blocklist.Match(input)
+	if hit == nil {
+		return false
+	}
+
+	s.addVerdict(res, &Verdict{Source: VerdictSourceBlocklist, Input: input, Response: hit.toResponse()})
+	atomic.StoreInt32(&res.MaxStatus, int32(hit.Action))
+	return hit.Action == model.BlockActionDeny
+}
+
+// checkStoredMediaHashes 使用上传时留存的哈希匹配图片与视频封面，命中时返回 true
+func (s *contentLLMProcessorImpl) checkStoredMediaHashes(ctx context.Context, media []*es.PostMediaES, res *Result, cancel context.CancelFunc) bool {
+	urls := make([]string, 0, len(media))
+	for _, m := range media {
+		switch strings.Split(m.Type, "/")[0] {
+		case consts.MimePrefixImage:
+			urls = append(urls, m.URL)
+		case consts.MimePrefixVideo:
+			if m.Cover != nil && *m.Cover != "" {
+				urls = append(urls, *m.Cover)
+			}
+		}
+	}
+
+	hits, err := s.mediaHashes.MatchStored(ctx, urls)
+	if err != nil {
+		log.WarnContext(ctx, "match stored media hashes failed", "err", err)
+		return false
+	}
+	for ref, hit := range hits {
+		s.addVerdict(res, &Verdict{Source: VerdictSourceMediaHash, Refs: []string{ref}, Response: hit.toResponse()})
+	}
+	if len(hits) == 0 {
+		return false
+	}
+	s.updateMaxStatus(res, int32(llm.ContentSafeDeny), cancel)
+	return true
+}
+
+// checkFrameHash 计算并留存视频抽帧哈希，命中指纹库时返回 true
+func (s *contentLLMProcessorImpl) checkFrameHash(ctx context.Context, url string, idx int, frame io.Reader, res *Result, cancel context.CancelFunc) bool {
+	hash, err := util.ComputeImageHash(frame)
+	if err != nil {
+		log.WarnContext(ctx, "compute video frame hash failed", "url", url, "frame", idx, "err", err)
+		return false
+	}
+	s.mediaHashes.SaveFrameHash(ctx, url, idx, hash)
+
+	hit := s.mediaHashes.Match(hash)
+	if hit == nil {
+		return false
+	}
+	s.addVerdict(res, &Verdict{Source: VerdictSourceMediaHash, Refs: []string{fmt.Sprintf("%s#%d", url, idx)}, Response: hit.toResponse()})
+	s.updateMaxStatus(res, int32(llm.ContentSafeDeny), cancel)
+	return true
+}
+
+func (s *contentLLMProcessorImpl) addPendingImage(res *Result, url, group, ref string) {
+	res.Lock()
+	defer res.Unlock()
+	res.pendingImages = append(res.pendingImages, &pendingImage{url: url, group: group, ref: ref})
+}
+
+func (s *contentLLMProcessorImpl) addVerdict(res *Result, verdict *Verdict) {
+	res.Lock()
+	defer res.Unlock()
+	res.Verdicts = append(res.Verdicts, verdict)
+}
+
 func (s *contentLLMProcessorImpl) updateResultSlice(res *Result, processed *llm.ContentResponse) {
 	res.Lock()
 	defer res.Unlock()
@@ -0,0 +1,136 @@
+package processor
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	"fmt"
+	log "log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// 感知哈希判定为同一图片的汉明距离阈值，两种哈希需同时满足
+const (
+	mediaPHashThreshold = 8
+	mediaDHashThreshold = 10
+)
+
+// MediaHashHit 图片指纹命中结果
+type MediaHashHit struct {
+	Version   string
+	BlockID   uint64
+	PDistance int
+	DDistance int
+}
+
+// MediaHashBlocklist 违规图片指纹库，按汉明距离匹配，变更时通过 Redis 通知热加载
+type MediaHashBlocklist struct {
+	repo    repository.MediaHashRepo
+	entries atomic.Pointer[mediaHashSnapshot]
+}
+
+type mediaHashSnapshot struct {
+	version string
+	blocks  []*model.MediaHashBlock
+}
+
+func NewMediaHashBlocklist(repo repository.MediaHashRepo) *MediaHashBlocklist {
+	return &MediaHashBlocklist{repo: repo}
+}
+
+// Watch 监听重载通知并定期刷新，阻塞直到 ctx 结束
+func (b *MediaHashBlocklist) Watch(ctx context.Context) error {
+	watchReload(ctx, consts.MediaHashReloadChannel, b.Reload)
+	return nil
+}
+
+// Reload 从数据库加载全部黑名单哈希并原子替换
+func (b *MediaHashBlocklist) Reload(ctx context.Context) error {
+	blocks, err := b.repo.GetAllHashBlocks(ctx)
+	if err != nil {
+		return err
+	}
+	b.entries.Store(&mediaHashSnapshot{
+		version: time.Now().Format("20060102150405"),
+		blocks:  blocks,
+	})
+	log.InfoContext(ctx, "media hash blocklist loaded", "count", len(blocks))
+	return nil
+}
+
+// Match 匹配单张图片，未命中或未加载时返回 nil
+func (b *MediaHashBlocklist) Match(hash *util.ImageHash) *MediaHashHit {
+	if b == nil || hash == nil {
+		return nil
+	}
+	snapshot := b.entries.Load()
+	if snapshot == nil {
+		return nil
+	}
+
+	var best *MediaHashHit
+	for _, block := range snapshot.blocks {
+		pd := util.HammingDistance(hash.PHash, block.PHash)
+		if pd > mediaPHashThreshold {
+			continue
+		}
+		dd := util.HammingDistance(hash.DHash, block.DHash)
+		if dd > mediaDHashThreshold {
+			continue
+		}
+		if best == nil || pd+dd < best.PDistance+best.DDistance {
+			best = &MediaHashHit{Version: snapshot.version, BlockID: block.ID, PDistance: pd, DDistance: dd}
+		}
+	}
+	return best
+}
+
+// MatchStored 使用上传时留存的哈希匹配媒体，返回以媒体引用为键的命中结果
+func (b *MediaHashBlocklist) MatchStored(ctx context.Context, mediaURLs []string) (map[string]*MediaHashHit, error) {
+	if b == nil || len(mediaURLs) == 0 || b.entries.Load() == nil {
+		return nil, nil
+	}
+	hashes, err := b.repo.GetMediaHashes(ctx, mediaURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make(map[string]*MediaHashHit)
+	for _, h := range hashes {
+		if hit := b.Match(&util.ImageHash{PHash: h.PHash, DHash: h.DHash}); hit != nil {
+			hits[h.Ref()] = hit
+		}
+	}
+	return hits, nil
+}
+
+// SaveFrameHash 留存视频抽帧哈希，拒绝确认后用于加入黑名单
+func (b *MediaHashBlocklist) SaveFrameHash(ctx context.Context, mediaURL string, idx int, hash *util.ImageHash) {
+	if b == nil {
+		return
+	}
+	err := b.repo.SaveMediaHashes(ctx, []*model.MediaHash{{
+		MediaURL: mediaURL,
+		Frame:    idx + 1,
+		PHash:    hash.PHash,
+		DHash:    hash.DHash,
+	}})
+	if err != nil {
+		log.WarnContext(ctx, "save video frame hash failed", "url", mediaURL, "frame", idx, "err", err)
+	}
+}
+
+// toResponse 将命中结果转为审核结论，与模型结论一同留存
+func (h *MediaHashHit) toResponse() *llm.ContentResponse {
+	return &llm.ContentResponse{
+		Status:        llm.ContentSafeDeny,
+		Reason:        fmt.Sprintf("命中违规图片指纹库 #%d (pHash 距离 %d, dHash 距离 %d)", h.BlockID, h.PDistance, h.DDistance),
+		Confidence:    1 - float64(h.PDistance+h.DDistance)/128,
+		Model:         "perceptual_hash",
+		PromptVersion: "media_hash@" + h.Version,
+	}
+}
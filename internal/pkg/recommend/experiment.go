@@ -0,0 +1,95 @@
+package recommend
+
+import (
+	"Cornerstone/internal/api/config"
+	"Cornerstone/internal/pkg/es"
+	"hash/crc32"
+	log "log/slog"
+	"strconv"
+)
+
+const (
+	// DefaultVariant 未命中任何实验时的分组名
+	DefaultVariant = "default"
+	// bucketCount 分桶总数，对应百分比流量
+	bucketCount = 100
+)
+
+// Assignment 用户分桶结果
+type Assignment struct {
+	ExperimentID string
+	Variant      string
+	Params       es.RankParams
+}
+
+var activeExperiment *config.ExperimentConfig
+
+// Init 加载推荐实验配置，仅第一个启用的实验生效
+func Init() {
+	activeExperiment = nil
+	for i := range config.Cfg.Recommend.Experiments {
+		exp := &config.Cfg.Recommend.Experiments[i]
+		if !exp.Enable || exp.ID == "" || len(exp.Variants) == 0 {
+			continue
+		}
+		total := 0
+		for _, v := range exp.Variants {
+			total += v.Weight
+		}
+		if total > bucketCount {
+			log.Warn("recommend experiment weight exceeds 100, skipped", "experiment", exp.ID, "total", total)
+			continue
+		}
+		activeExperiment = exp
+		log.Info("recommend experiment enabled", "experiment", exp.ID, "variants", len(exp.Variants))
+		return
+	}
+}
+
+// Assign 根据用户 ID 做确定性分桶，游客按会话 ID 分桶
+func Assign(userID uint64, sessionID string) *Assignment {
+	res := &Assignment{
+		Variant: DefaultVariant,
+		Params:  es.DefaultRankParams,
+	}
+	exp := activeExperiment
+	if exp == nil {
+		return res
+	}
+
+	unit := sessionID
+	if userID > 0 {
+		unit = strconv.FormatUint(userID, 10)
+	}
+	if unit == "" {
+		return res
+	}
+
+	// 以实验 ID 作为盐，保证不同实验之间分桶相互独立
+	bucket := int(crc32.ChecksumIEEE([]byte(exp.ID+":"+unit)) % bucketCount)
+	for _, v := range exp.Variants {
+		if bucket < v.Weight {
+			res.ExperimentID = exp.ID
+			res.Variant = v.Name
+			res.Params = buildParams(v)
+			return res
+		}
+		bucket -= v.Weight
+	}
+	return res
+}
+
+// buildParams 以策略默认值为基础，叠加分组内显式配置的参数
+func buildParams(v config.VariantConfig) es.RankParams {
+	params := GetStrategy(v.Strategy)
+	if v.KnnBoost > 0 {
+		params.KnnBoost = v.KnnBoost
+	}
+	if v.TextBoost > 0 {
+		params.TextBoost = v.TextBoost
+	}
+	if v.RandomWeight > 0 {
+		params.RandomWeight = v.RandomWeight
+	}
+	return params
+}
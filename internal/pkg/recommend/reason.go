@@ -0,0 +1,97 @@
+package recommend
+
+import (
+	"Cornerstone/internal/pkg/es"
+	"math"
+	"slices"
+)
+
+// 推荐理由码
+const (
+	ReasonFollowedAuthor   = "followed_author"   // 关注的作者
+	ReasonInterestTag      = "interest_tag"      // 命中兴趣标签
+	ReasonVectorSimilarity = "vector_similarity" // 兴趣向量相似
+	ReasonTrending         = "trending"          // 热门
+	ReasonLatest           = "latest"            // 最新内容兜底
+	ReasonExplore          = "explore"           // 随机探索
+)
+
+// TrendingLikesThreshold 判定为热门的点赞数阈值
+const TrendingLikesThreshold = 100
+
+// ReasonInput 计算推荐理由所需的用户侧上下文
+type ReasonInput struct {
+	InterestTags []string
+	Following    map[uint64]struct{}
+	Params       es.RankParams
+	Fallback     bool // 是否来自最新内容兜底
+}
+
+// ResolveReason 按 关注 > 兴趣标签 > 向量相似 > 热门 > 探索 的优先级给出推荐理由
+func ResolveReason(post *es.PostES, in *ReasonInput) (string, string) {
+	if _, ok := in.Following[post.UserID]; ok {
+		return ReasonFollowedAuthor, post.UserNickname
+	}
+	if in.Fallback {
+		return ReasonLatest, ""
+	}
+
+	if tag := MatchInterestTag(post, in.InterestTags); tag != "" {
+		return ReasonInterestTag, tag
+	}
+
+	textMatched := slices.Contains(post.MatchedQueries, es.RecommendTextQueryName)
+	if textMatched && len(in.InterestTags) > 0 {
+		return ReasonInterestTag, in.InterestTags[0]
+	}
+
+	// 未命中文本时，得分超过随机打散能贡献的上限，说明来自向量召回
+	if !textMatched && in.Params.KnnBoost > 0 && post.Score > baseScoreUpperBound(in.Params) {
+		return ReasonVectorSimilarity, ""
+	}
+
+	if post.LikesCount >= TrendingLikesThreshold {
+		return ReasonTrending, ""
+	}
+	return ReasonExplore, ""
+}
+
+// MatchInterestTag 返回帖子主标签或 AI 标签中第一个命中的兴趣标签
+func MatchInterestTag(post *es.PostES, interestTags []string) string {
+	for _, tag := range interestTags {
+		if tag == post.MainTag || slices.Contains(post.AITags, tag) {
+			return tag
+		}
+	}
+	return ""
+}
+
+// CosineSimilarity 余弦相似度
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// KnnScore 按 ES cosine 相似度的打分公式 (1 + cos) / 2 估算向量召回得分
+func KnnScore(similarity float64, params es.RankParams) float64 {
+	return (1 + similarity) / 2 * float64(params.KnnBoost)
+}
+
+// baseScoreUpperBound function_score 包裹的 match_all 基础分为 1，随机分取值 [0, 1)
+func baseScoreUpperBound(params es.RankParams) float64 {
+	if params.RandomWeight <= 0 {
+		return 0
+	}
+	return 1 + params.RandomWeight
+}
@@ -0,0 +1,40 @@
+package recommend
+
+import (
+	"Cornerstone/internal/pkg/es"
+	"sync"
+)
+
+const (
+	StrategyHybrid = "hybrid" // 向量 + 文本 + 随机打散
+	StrategyVector = "vector" // 仅向量召回 + 随机打散
+	StrategyText   = "text"   // 仅文本匹配 + 随机打散
+	StrategyRandom = "random" // 纯随机打散
+)
+
+var (
+	strategyMu sync.RWMutex
+	strategies = map[string]es.RankParams{
+		StrategyHybrid: es.DefaultRankParams,
+		StrategyVector: {KnnBoost: 20.0, RandomWeight: 1.0},
+		StrategyText:   {TextBoost: 2.0, RandomWeight: 1.0},
+		StrategyRandom: {RandomWeight: 1.0},
+	}
+)
+
+// RegisterStrategy 注册排序策略，同名覆盖
+func RegisterStrategy(name string, params es.RankParams) {
+	strategyMu.Lock()
+	defer strategyMu.Unlock()
+	strategies[name] = params
+}
+
+// GetStrategy 获取策略默认参数，未知策略回退到 hybrid
+func GetStrategy(name string) es.RankParams {
+	strategyMu.RLock()
+	defer strategyMu.RUnlock()
+	if params, ok := strategies[name]; ok {
+		return params
+	}
+	return es.DefaultRankParams
+}
@@ -82,6 +82,15 @@ func UnLock(ctx context.Context, key string, value interface{}) {
 	Rdb.Eval(ctx, "if redis.call('get', KEYS[1]) == ARGV[1] then return redis.call('del', KEYS[1]) else return 0 end", []string{key}, value)
 }
 
+// RenewLock 仅当锁仍由 value 持有时续期，返回 false 表示锁已过期或被他人持有
+func RenewLock(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	n, err := Rdb.Eval(ctx, "if redis.call('get', KEYS[1]) == ARGV[1] then return redis.call('pexpire', KEYS[1], ARGV[2]) else return 0 end", []string{key}, value, expiration.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
 // GetSet 获取集合
 func GetSet(ctx context.Context, key string) ([]string, error) {
 	value, err := Rdb.SMembers(ctx, key).Result()
@@ -0,0 +1,87 @@
+package util
+
+// ACMatcher 基于 Aho-Corasick 自动机的多模式串匹配器，构建后只读，可并发使用
+type ACMatcher struct {
+	nodes []acNode
+}
+
+type acNode struct {
+	next     map[rune]int
+	fail     int
+	patterns []int // 以当前节点结尾的模式串下标，包含 fail 链上的输出
+}
+
+// NewACMatcher 构建匹配器，空模式串会被忽略
+func NewACMatcher(patterns []string) *ACMatcher {
+	m := &ACMatcher{nodes: []acNode{{next: map[rune]int{}}}}
+	for i, p := range patterns {
+		if p == "" {
+			continue
+		}
+		cur := 0
+		for _, r := range p {
+			nxt, ok := m.nodes[cur].next[r]
+			if !ok {
+				nxt = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: map[rune]int{}})
+				m.nodes[cur].next[r] = nxt
+			}
+			cur = nxt
+		}
+		m.nodes[cur].patterns = append(m.nodes[cur].patterns, i)
+	}
+
+	// BFS 构建 fail 指针
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].next {
+			fail := m.nodes[cur].fail
+			for fail > 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if nxt, ok := m.nodes[fail].next[r]; ok && nxt != child {
+				m.nodes[child].fail = nxt
+			}
+			m.nodes[child].patterns = append(m.nodes[child].patterns, m.nodes[m.nodes[child].fail].patterns...)
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// Match 返回文本中命中的模式串下标，同一模式串只返回一次
+func (m *ACMatcher) Match(text string) []int {
+	if m == nil || len(m.nodes) == 1 {
+		return nil
+	}
+	var hits []int
+	seen := make(map[int]struct{})
+	cur := 0
+	for _, r := range text {
+		for cur > 0 {
+			if _, ok := m.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if nxt, ok := m.nodes[cur].next[r]; ok {
+			cur = nxt
+		}
+		for _, idx := range m.nodes[cur].patterns {
+			if _, ok := seen[idx]; ok {
+				continue
+			}
+			seen[idx] = struct{}{}
+			hits = append(hits, idx)
+		}
+	}
+	return hits
+}
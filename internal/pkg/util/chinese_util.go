@@ -0,0 +1,92 @@
+package util
+
+import (
+	log "log/slog"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/liuzl/gocc"
+	"github.com/mozillazg/go-pinyin"
+)
+
+var (
+	t2sOnce      sync.Once
+	t2sConverter *gocc.OpenCC
+)
+
+// ToSimplified 繁体转简体，转换器加载失败时原样返回
+func ToSimplified(text string) string {
+	if text == "" {
+		return text
+	}
+	t2sOnce.Do(func() {
+		c, err := gocc.New("t2s")
+		if err != nil {
+			log.Error("load gocc t2s failed", "err", err)
+			return
+		}
+		t2sConverter = c
+	})
+	if t2sConverter == nil {
+		return text
+	}
+	out, err := t2sConverter.Convert(text)
+	if err != nil {
+		return text
+	}
+	return out
+}
+
+// ToSimplifiedSlice 批量繁体转简体
+func ToSimplifiedSlice(texts []string) []string {
+	res := make([]string, 0, len(texts))
+	for _, t := range texts {
+		res = append(res, ToSimplified(t))
+	}
+	return res
+}
+
+// PinyinSeparator 拼音音节之间的分隔符
+const PinyinSeparator = ' '
+
+// ToPinyin 将汉字转为无声调拼音，每个音节之间以 PinyinSeparator 分隔，多音字取常用读音；
+// 连续的非汉字字符作为一个整体保留，同样与前后音节分隔
+func ToPinyin(text string) string {
+	var sb strings.Builder
+	args := pinyin.NewArgs()
+	inRun := false
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) {
+			if py := pinyin.SinglePinyin(r, args); len(py) > 0 {
+				if sb.Len() > 0 {
+					sb.WriteRune(PinyinSeparator)
+				}
+				sb.WriteString(py[0])
+				inRun = false
+				continue
+			}
+		}
+		if !inRun && sb.Len() > 0 {
+			sb.WriteRune(PinyinSeparator)
+		}
+		sb.WriteRune(r)
+		inRun = true
+	}
+	return sb.String()
+}
+
+// FoldForMatch 归一化文本用于屏蔽词匹配：繁转简、全角转半角、转小写，并去掉空白、标点等干扰字符
+func FoldForMatch(text string) string {
+	var sb strings.Builder
+	for _, r := range ToSimplified(text) {
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
@@ -73,3 +73,12 @@ func ValidatePhone(phone string) bool {
 	rgx := regexp.MustCompile(reg)
 	return rgx.MatchString(phone)
 }
+
+// TruncateRunes 按字符截断字符串，避免截断多字节字符，超出时追加省略号
+func TruncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
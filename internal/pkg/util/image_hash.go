@@ -0,0 +1,112 @@
+package util
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/png"
+	"io"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/disintegration/imaging"
+)
+
+// ImageHash 图片感知哈希
+type ImageHash struct {
+	PHash uint64 // 基于 DCT 低频分量，抗缩放与压缩
+	DHash uint64 // 基于相邻像素梯度，抗亮度调整
+}
+
+// ComputeImageHash 解码图片并计算感知哈希，输入为 io.Seeker 时读取后重置指针
+func ComputeImageHash(r io.Reader) (*ImageHash, error) {
+	img, _, err := image.Decode(r)
+	if seeker, ok := r.(io.Seeker); ok {
+		if _, seekErr := seeker.Seek(0, io.SeekStart); seekErr != nil {
+			return nil, fmt.Errorf("重置文件指针失败: %w", seekErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ImageHash{PHash: pHash(img), DHash: dHash(img)}, nil
+}
+
+// HammingDistance 两个哈希值的汉明距离
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// pHash 缩放到 32x32 灰度图做二维 DCT，取左上 8x8 低频分量与中位数比较
+func pHash(img image.Image) uint64 {
+	const size, low = 32, 8
+	gray := imaging.Grayscale(imaging.Resize(img, size, size, imaging.Lanczos))
+
+	pixels := make([][]float64, size)
+	for y := 0; y < size; y++ {
+		pixels[y] = make([]float64, size)
+		for x := 0; x < size; x++ {
+			pixels[y][x] = float64(gray.Pix[y*gray.Stride+x*4])
+		}
+	}
+
+	// 先按行再按列做 DCT-II，只计算需要的低频系数
+	rows := make([][]float64, size)
+	for y := 0; y < size; y++ {
+		rows[y] = dct1D(pixels[y], low)
+	}
+	coeffs := make([]float64, 0, low*low)
+	col := make([]float64, size)
+	for u := 0; u < low; u++ {
+		for y := 0; y < size; y++ {
+			col[y] = rows[y][u]
+		}
+		coeffs = append(coeffs, dct1D(col, low)...)
+	}
+
+	// 直流分量不参与中位数计算
+	sorted := append([]float64{}, coeffs[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+func dct1D(in []float64, n int) []float64 {
+	size := float64(len(in))
+	out := make([]float64, n)
+	for k := 0; k < n; k++ {
+		var sum float64
+		for i, v := range in {
+			sum += v * math.Cos(math.Pi/size*(float64(i)+0.5)*float64(k))
+		}
+		out[k] = sum
+	}
+	return out
+}
+
+// dHash 缩放到 9x8 灰度图，比较每行相邻像素的亮度
+func dHash(img image.Image) uint64 {
+	gray := imaging.Grayscale(imaging.Resize(img, 9, 8, imaging.Lanczos))
+
+	var hash uint64
+	bit := 0
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			left := gray.Pix[y*gray.Stride+x*4]
+			right := gray.Pix[y*gray.Stride+(x+1)*4]
+			if left > right {
+				hash |= 1 << uint(bit)
+			}
+			bit++
+		}
+	}
+	return hash
+}
@@ -18,7 +18,6 @@ import (
 	"strings"
 
 	"github.com/disintegration/imaging"
-	"github.com/liuzl/gocc"
 )
 
 // GetSafeContentType 嗅探文件的真实 MIME 类型并校验合法性
@@ -221,13 +220,7 @@ func AudioStreamToText(ctx context.Context, mediaUrl string) (string, error) {
 		}
 	}
 
-	res := strings.TrimSpace(fullText.String())
-	t2s, err := gocc.New("t2s")
-	if err != nil {
-		return res, nil
-	}
-	out, _ := t2s.Convert(res)
-	return out, nil
+	return ToSimplified(strings.TrimSpace(fullText.String())), nil
 }
 
 // transcribeSegment 转写音频片段
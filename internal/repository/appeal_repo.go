@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AppealRepo interface {
+	CreateAppeal(ctx context.Context, appeal *model.Appeal) error
+	GetAppeal(ctx context.Context, id uint64) (*model.Appeal, error)
+	GetAppealsByTarget(ctx context.Context, targetType int8, targetID uint64) ([]*model.Appeal, error)
+	GetAppealsByUser(ctx context.Context, userID uint64, limit, offset int) ([]*model.Appeal, int64, error)
+	GetAppeals(ctx context.Context, status int8, targetType int8, limit, offset int) ([]*model.Appeal, int64, error)
+	CloseAppeal(ctx context.Context, id, auditorID uint64, status int8, note string) (bool, error)
+	ReopenAppeal(ctx context.Context, id uint64) error
+}
+
+type appealRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewAppealRepo(db *gorm.DB) AppealRepo {
+	return &appealRepoImpl{db: db}
+}
+
+func (s *appealRepoImpl) CreateAppeal(ctx context.Context, appeal *model.Appeal) error {
+	return s.db.WithContext(ctx).Create(appeal).Error
+}
+
+func (s *appealRepoImpl) GetAppeal(ctx context.Context, id uint64) (*model.Appeal, error) {
+	var res model.Appeal
+	err := s.db.WithContext(ctx).First(&res, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetAppealsByTarget 获取同一内容的全部申诉记录
+func (s *appealRepoImpl) GetAppealsByTarget(ctx context.Context, targetType int8, targetID uint64) ([]*model.Appeal, error) {
+	var list []*model.Appeal
+	err := s.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// GetAppealsByUser 分页获取用户提交的申诉，按时间倒序
+func (s *appealRepoImpl) GetAppealsByUser(ctx context.Context, userID uint64, limit, offset int) ([]*model.Appeal, int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.Appeal{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*model.Appeal, 0, limit)
+	err := db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&list).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// GetAppeals 审核端分页获取申诉，按提交时间先后处理
+func (s *appealRepoImpl) GetAppeals(ctx context.Context, status int8, targetType int8, limit, offset int) ([]*model.Appeal, int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.Appeal{}).Where("status = ?", status)
+	if targetType > 0 {
+		db = db.Where("target_type = ?", targetType)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*model.Appeal, 0, limit)
+	err := db.Order("created_at ASC").Limit(limit).Offset(offset).Find(&list).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// CloseAppeal 处理待处理的申诉，已被处理时返回 false
+func (s *appealRepoImpl) CloseAppeal(ctx context.Context, id, auditorID uint64, status int8, note string) (bool, error) {
+	now := time.Now()
+	result := s.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Where("id = ? AND status = ?", id, model.AppealPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"handled_by":  auditorID,
+			"handle_note": note,
+			"handled_at":  now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReopenAppeal 处理失败时将申诉恢复为待处理
+func (s *appealRepoImpl) ReopenAppeal(ctx context.Context, id uint64) error {
+	return s.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      model.AppealPending,
+			"handled_by":  0,
+			"handle_note": "",
+			"handled_at":  nil,
+		}).Error
+}
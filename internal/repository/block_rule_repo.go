@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type BlockRuleRepo interface {
+	CreateBlockRule(ctx context.Context, rule *model.BlockRule) error
+	UpdateBlockRule(ctx context.Context, rule *model.BlockRule) error
+	DeleteBlockRule(ctx context.Context, id uint64) (bool, error)
+	GetBlockRule(ctx context.Context, id uint64) (*model.BlockRule, error)
+	GetBlockRules(ctx context.Context, matchType int8, keyword string, limit, offset int) ([]*model.BlockRule, int64, error)
+	GetEnabledBlockRules(ctx context.Context) ([]*model.BlockRule, error)
+}
+
+type blockRuleRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewBlockRuleRepo(db *gorm.DB) BlockRuleRepo {
+	return &blockRuleRepoImpl{db: db}
+}
+
+func (s *blockRuleRepoImpl) CreateBlockRule(ctx context.Context, rule *model.BlockRule) error {
+	return s.db.WithContext(ctx).Create(rule).Error
+}
+
+func (s *blockRuleRepoImpl) UpdateBlockRule(ctx context.Context, rule *model.BlockRule) error {
+	return s.db.WithContext(ctx).
+		Model(&model.BlockRule{}).
+		Where("id = ?", rule.ID).
+		Updates(map[string]interface{}{
+			"pattern":     rule.Pattern,
+			"match_type":  rule.MatchType,
+			"action":      rule.Action,
+			"fold_pinyin": rule.FoldPinyin,
+			"enabled":     rule.Enabled,
+			"remark":      rule.Remark,
+		}).Error
+}
+
+// DeleteBlockRule 删除规则，规则不存在时返回 false
+func (s *blockRuleRepoImpl) DeleteBlockRule(ctx context.Context, id uint64) (bool, error) {
+	result := s.db.WithContext(ctx).Delete(&model.BlockRule{}, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *blockRuleRepoImpl) GetBlockRule(ctx context.Context, id uint64) (*model.BlockRule, error) {
+	var res model.BlockRule
+	err := s.db.WithContext(ctx).First(&res, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetBlockRules 管理端分页获取规则，按更新时间倒序
+func (s *blockRuleRepoImpl) GetBlockRules(ctx context.Context, matchType int8, keyword string, limit, offset int) ([]*model.BlockRule, int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.BlockRule{})
+	if matchType > 0 {
+		db = db.Where("match_type = ?", matchType)
+	}
+	if keyword != "" {
+		db = db.Where("pattern LIKE ?", "%"+keyword+"%")
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*model.BlockRule, 0, limit)
+	err := db.Order("updated_at DESC, id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// GetEnabledBlockRules 获取全部启用的规则，用于构建匹配器
+func (s *blockRuleRepoImpl) GetEnabledBlockRules(ctx context.Context) ([]*model.BlockRule, error) {
+	var list []*model.BlockRule
+	err := s.db.WithContext(ctx).Where("enabled = ?", true).Order("id ASC").Find(&list).Error
+	return list, err
+}
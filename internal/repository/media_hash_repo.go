@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MediaHashRepo interface {
+	SaveMediaHashes(ctx context.Context, hashes []*model.MediaHash) error
+	GetMediaHashes(ctx context.Context, mediaURLs []string) ([]*model.MediaHash, error)
+	CreateHashBlocks(ctx context.Context, blocks []*model.MediaHashBlock) (int64, error)
+	DeleteHashBlock(ctx context.Context, id uint64) (bool, error)
+	GetHashBlocks(ctx context.Context, source int8, limit, offset int) ([]*model.MediaHashBlock, int64, error)
+	GetAllHashBlocks(ctx context.Context) ([]*model.MediaHashBlock, error)
+}
+
+type mediaHashRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewMediaHashRepo(db *gorm.DB) MediaHashRepo {
+	return &mediaHashRepoImpl{db: db}
+}
+
+// SaveMediaHashes 写入媒体哈希，同一媒体同一帧重复写入时覆盖
+func (s *mediaHashRepoImpl) SaveMediaHashes(ctx context.Context, hashes []*model.MediaHash) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_url"}, {Name: "frame"}},
+		DoUpdates: clause.AssignmentColumns([]string{"phash", "dhash"}),
+	}).Create(&hashes).Error
+}
+
+// GetMediaHashes 获取媒体的全部哈希，视频包含各抽帧
+func (s *mediaHashRepoImpl) GetMediaHashes(ctx context.Context, mediaURLs []string) ([]*model.MediaHash, error) {
+	var list []*model.MediaHash
+	if len(mediaURLs) == 0 {
+		return list, nil
+	}
+	err := s.db.WithContext(ctx).
+		Where("media_url IN ?", mediaURLs).
+		Order("media_url ASC, frame ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// CreateHashBlocks 批量加入黑名单，已存在的哈希忽略，返回实际新增数量
+func (s *mediaHashRepoImpl) CreateHashBlocks(ctx context.Context, blocks []*model.MediaHashBlock) (int64, error) {
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&blocks)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteHashBlock 移出黑名单，记录不存在时返回 false
+func (s *mediaHashRepoImpl) DeleteHashBlock(ctx context.Context, id uint64) (bool, error) {
+	result := s.db.WithContext(ctx).Delete(&model.MediaHashBlock{}, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetHashBlocks 审核端分页获取黑名单，按添加时间倒序
+func (s *mediaHashRepoImpl) GetHashBlocks(ctx context.Context, source int8, limit, offset int) ([]*model.MediaHashBlock, int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.MediaHashBlock{})
+	if source > 0 {
+		db = db.Where("source = ?", source)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*model.MediaHashBlock, 0, limit)
+	err := db.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// GetAllHashBlocks 获取全部黑名单哈希，用于构建匹配器
+func (s *mediaHashRepoImpl) GetAllHashBlocks(ctx context.Context) ([]*model.MediaHashBlock, error) {
+	var list []*model.MediaHashBlock
+	err := s.db.WithContext(ctx).Select("id", "phash", "dhash").Order("id ASC").Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type ModerationRepo interface {
+	CreateVerdicts(ctx context.Context, verdicts []*model.ModerationVerdict) error
+	GetVerdicts(ctx context.Context, targetType int8, targetID uint64, limit int) ([]*model.ModerationVerdict, error)
+}
+
+type moderationRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewModerationRepo(db *gorm.DB) ModerationRepo {
+	return &moderationRepoImpl{db: db}
+}
+
+// CreateVerdicts 批量写入一次审核的全部子结论
+func (s *moderationRepoImpl) CreateVerdicts(ctx context.Context, verdicts []*model.ModerationVerdict) error {
+	if len(verdicts) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Create(&verdicts).Error
+}
+
+// GetVerdicts 获取内容最近的审核记录，按时间倒序
+func (s *moderationRepoImpl) GetVerdicts(ctx context.Context, targetType int8, targetID uint64, limit int) ([]*model.ModerationVerdict, error) {
+	var list []*model.ModerationVerdict
+	err := s.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
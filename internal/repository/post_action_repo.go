@@ -4,6 +4,7 @@ import (
 	"Cornerstone/internal/model"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
@@ -25,10 +26,14 @@ type PostActionRepo interface {
 	UpdateCommentStatus(ctx context.Context, commentID uint64, status int8) error
 	UpdateCommentLikesCount(ctx context.Context, commentID uint64, count int) error
 	GetCommentByID(ctx context.Context, commentID uint64) (*model.PostComment, error)
-	GetRootCommentsByPostID(ctx context.Context, postID uint64, limit, offset int) ([]*model.PostComment, error)
-	GetSubCommentCounts(ctx context.Context, rootIDs []uint64) (map[uint64]int, error)
-	GetSubCommentsByRootID(ctx context.Context, rootID uint64, limit, offset int) ([]*model.PostComment, error)
+	GetRootCommentsByPostID(ctx context.Context, postID uint64, exclude *CommentExclusion, oldestFirst bool, cursorTime time.Time, cursorID uint64, limit int) ([]*model.PostComment, error)
+	GetRootCommentsByIDs(ctx context.Context, ids []uint64, exclude *CommentExclusion) ([]*model.PostComment, error)
+	GetRootCommentHotStats(ctx context.Context, postID uint64) ([]*model.CommentHotStat, error)
+	GetReplyPreviewCandidates(ctx context.Context, rootIDs []uint64, exclude *CommentExclusion, perRoot int) ([]*model.PostComment, error)
+	GetSubCommentCounts(ctx context.Context, rootIDs []uint64, exclude *CommentExclusion) (map[uint64]int, error)
+	GetSubCommentsByRootID(ctx context.Context, rootID uint64, exclude *CommentExclusion, limit, offset int) ([]*model.PostComment, error)
 	GetSubCommentCountByRootID(ctx context.Context, rootID uint64) (int64, error)
+	GetTopApprovedComments(ctx context.Context, postID uint64, exclude *CommentExclusion, limit int) ([]*model.PostComment, error)
 
 	CreateCommentLike(ctx context.Context, cl *model.CommentLike) error
 	DeleteCommentLike(ctx context.Context, userID, commentID uint64) error
@@ -142,60 +147,93 @@ func (s *PostActionRepoImpl) GetCommentByID(ctx context.Context, commentID uint6
 	return &comment, err
 }
 
-func (s *PostActionRepoImpl) GetRootCommentsByPostID(ctx context.Context, postID uint64, limit, offset int) ([]*model.PostComment, error) {
+// GetRootCommentsByPostID 按发布时间游标获取帖子的根评论，cursorID 为 0 时从头开始，exclude 中的评论不返回
+func (s *PostActionRepoImpl) GetRootCommentsByPostID(ctx context.Context, postID uint64, exclude *CommentExclusion, oldestFirst bool, cursorTime time.Time, cursorID uint64, limit int) ([]*model.PostComment, error) {
 	var comments []*model.PostComment
 
-	err := s.db.WithContext(ctx).
+	db := exclude.apply(s.db.WithContext(ctx)).
 		Select("post_comments.*").
 		Joins("User").
 		Joins("ReplyUser").
-		Where("post_comments.post_id = ? AND post_comments.root_id = ? AND post_comments.is_deleted = ?", postID, 0, 0).
-		Order("post_comments.created_at DESC").
-		Limit(limit).Offset(offset).
-		Find(&comments).Error
-	if err != nil || len(comments) == 0 {
-		return comments, err
+		Where("post_comments.post_id = ? AND post_comments.root_id = ? AND post_comments.is_deleted = ?", postID, 0, 0)
+
+	if oldestFirst {
+		if cursorID > 0 {
+			db = db.Where("(post_comments.created_at, post_comments.id) > (?, ?)", cursorTime, cursorID)
+		}
+		db = db.Order("post_comments.created_at ASC, post_comments.id ASC")
+	} else {
+		if cursorID > 0 {
+			db = db.Where("(post_comments.created_at, post_comments.id) < (?, ?)", cursorTime, cursorID)
+		}
+		db = db.Order("post_comments.created_at DESC, post_comments.id DESC")
 	}
 
-	rootIDs := make([]uint64, len(comments))
-	for i, rc := range comments {
-		rootIDs[i] = rc.ID
+	err := db.Limit(limit).Find(&comments).Error
+	return comments, err
+}
+
+// GetRootCommentsByIDs 按 ID 获取根评论，返回顺序不保证与 ids 一致
+func (s *PostActionRepoImpl) GetRootCommentsByIDs(ctx context.Context, ids []uint64, exclude *CommentExclusion) ([]*model.PostComment, error) {
+	var comments []*model.PostComment
+	if len(ids) == 0 {
+		return comments, nil
 	}
 
+	err := exclude.apply(s.db.WithContext(ctx)).
+		Select("post_comments.*").
+		Joins("User").
+		Joins("ReplyUser").
+		Where("post_comments.id IN ? AND post_comments.root_id = ? AND post_comments.is_deleted = ?", ids, 0, 0).
+		Find(&comments).Error
+	return comments, err
+}
+
+// GetRootCommentHotStats 获取帖子全部根评论的发布时间、落库点赞数与回复数
+func (s *PostActionRepoImpl) GetRootCommentHotStats(ctx context.Context, postID uint64) ([]*model.CommentHotStat, error) {
+	var stats []*model.CommentHotStat
+
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT pc.id, pc.created_at, pc.likes_count, COUNT(r.id) AS reply_count
+		FROM post_comments pc
+		LEFT JOIN post_comments r ON r.root_id = pc.id AND r.is_deleted = 0
+		WHERE pc.post_id = ? AND pc.root_id = 0 AND pc.is_deleted = 0
+		GROUP BY pc.id, pc.created_at, pc.likes_count
+	`, postID).Scan(&stats).Error
+	return stats, err
+}
+
+// GetReplyPreviewCandidates 按落库点赞数为每条根评论取前 perRoot 条回复，作为热门回复预览的候选
+func (s *PostActionRepoImpl) GetReplyPreviewCandidates(ctx context.Context, rootIDs []uint64, exclude *CommentExclusion, perRoot int) ([]*model.PostComment, error) {
 	var subComments []*model.PostComment
+	if len(rootIDs) == 0 {
+		return subComments, nil
+	}
+
+	hiddenCond, hiddenArgs := exclude.rawCondition("pc")
+	args := append([]any{rootIDs}, hiddenArgs...)
+	args = append(args, perRoot)
 	subQuery := `
 		   SELECT t.*, 
 				  u.user_id AS User__user_id, u.nickname AS User__nickname, u.avatar_url AS User__avatar_url,
 				  ru.user_id AS ReplyUser__user_id, ru.nickname AS ReplyUser__nickname
 		   FROM (
 			  SELECT pc.*, 
-					 ROW_NUMBER() OVER (PARTITION BY pc.root_id ORDER BY pc.created_at) as rn
+					 ROW_NUMBER() OVER (PARTITION BY pc.root_id ORDER BY pc.likes_count DESC, pc.created_at DESC) as rn
 			  FROM post_comments pc
-			  WHERE pc.root_id IN (?) AND pc.is_deleted = 0
+			  WHERE pc.root_id IN (?) AND pc.is_deleted = 0` + hiddenCond + `
 		   ) t
 		   LEFT JOIN user_detail u ON t.user_id = u.user_id
 		   LEFT JOIN user_detail ru ON t.reply_to_user_id = ru.user_id
-		   WHERE t.rn <= 2
+		   WHERE t.rn <= ?
 		`
 
-	err = s.db.WithContext(ctx).Raw(subQuery, rootIDs).Scan(&subComments).Error
-	if err != nil {
-		return comments, err
-	}
-
-	subMap := make(map[uint64][]*model.PostComment)
-	for _, sc := range subComments {
-		subMap[sc.RootID] = append(subMap[sc.RootID], sc)
-	}
-	for _, rc := range comments {
-		rc.SubComments = subMap[rc.ID]
-	}
-
-	return comments, nil
+	err := s.db.WithContext(ctx).Raw(subQuery, args...).Scan(&subComments).Error
+	return subComments, err
 }
 
 // GetSubCommentCounts 批量获取多个根评论的子评论数
-func (s *PostActionRepoImpl) GetSubCommentCounts(ctx context.Context, rootIDs []uint64) (map[uint64]int, error) {
+func (s *PostActionRepoImpl) GetSubCommentCounts(ctx context.Context, rootIDs []uint64, exclude *CommentExclusion) (map[uint64]int, error) {
 	type Result struct {
 		RootID uint64
 		Count  int
@@ -207,7 +245,7 @@ func (s *PostActionRepoImpl) GetSubCommentCounts(ctx context.Context, rootIDs []
 		return counts, nil
 	}
 
-	err := s.db.WithContext(ctx).Model(&model.PostComment{}).
+	err := exclude.apply(s.db.WithContext(ctx)).Model(&model.PostComment{}).
 		Select("root_id, count(*) as count").
 		Where("root_id IN ? AND is_deleted = ?", rootIDs, false).
 		Group("root_id").
@@ -224,10 +262,10 @@ func (s *PostActionRepoImpl) GetSubCommentCounts(ctx context.Context, rootIDs []
 }
 
 // GetSubCommentsByRootID 获取某个根评论下的子评论
-func (s *PostActionRepoImpl) GetSubCommentsByRootID(ctx context.Context, rootID uint64, limit, offset int) ([]*model.PostComment, error) {
+func (s *PostActionRepoImpl) GetSubCommentsByRootID(ctx context.Context, rootID uint64, exclude *CommentExclusion, limit, offset int) ([]*model.PostComment, error) {
 	var comments []*model.PostComment
 
-	err := s.db.WithContext(ctx).
+	err := exclude.apply(s.db.WithContext(ctx)).
 		Joins("User").
 		Joins("ReplyUser").
 		Where("post_comments.root_id = ? AND post_comments.is_deleted = ?", rootID, false).
@@ -238,6 +276,56 @@ func (s *PostActionRepoImpl) GetSubCommentsByRootID(ctx context.Context, rootID
 	return comments, err
 }
 
+// GetTopApprovedComments 按点赞数获取帖子下审核通过的评论（含回复），用于讨论总结
+func (s *PostActionRepoImpl) GetTopApprovedComments(ctx context.Context, postID uint64, exclude *CommentExclusion, limit int) ([]*model.PostComment, error) {
+	var comments []*model.PostComment
+
+	err := exclude.apply(s.db.WithContext(ctx)).
+		Select("id", "root_id", "content", "likes_count", "created_at").
+		Where("post_comments.post_id = ? AND post_comments.status = ? AND post_comments.is_deleted = ?", postID, 1, 0).
+		Order("post_comments.likes_count DESC, post_comments.id DESC").
+		Limit(limit).
+		Find(&comments).Error
+	return comments, err
+}
+
+// CommentExclusion 对查看者隐藏的评论，nil 表示不排除
+type CommentExclusion struct {
+	UserIDs    []uint64 // 影子封禁用户发布的评论
+	CommentIDs []uint64 // 被笔记作者隐藏的评论
+}
+
+func (e *CommentExclusion) apply(db *gorm.DB) *gorm.DB {
+	if e == nil {
+		return db
+	}
+	if len(e.UserIDs) > 0 {
+		db = db.Where("post_comments.user_id NOT IN ?", e.UserIDs)
+	}
+	if len(e.CommentIDs) > 0 {
+		db = db.Where("post_comments.id NOT IN ?", e.CommentIDs)
+	}
+	return db
+}
+
+// rawCondition 拼接原生 SQL 中别名为 alias 的评论表的排除条件
+func (e *CommentExclusion) rawCondition(alias string) (string, []any) {
+	if e == nil {
+		return "", nil
+	}
+	var cond string
+	var args []any
+	if len(e.UserIDs) > 0 {
+		cond += " AND " + alias + ".user_id NOT IN (?)"
+		args = append(args, e.UserIDs)
+	}
+	if len(e.CommentIDs) > 0 {
+		cond += " AND " + alias + ".id NOT IN (?)"
+		args = append(args, e.CommentIDs)
+	}
+	return cond, args
+}
+
 // GetSubCommentCountByRootID 获取某个根评论下的回复总数
 func (s *PostActionRepoImpl) GetSubCommentCountByRootID(ctx context.Context, rootID uint64) (int64, error) {
 	var count int64
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostCommentControlRepo interface {
+	GetSetting(ctx context.Context, postID uint64) (*model.PostCommentSetting, error)
+	SaveSetting(ctx context.Context, setting *model.PostCommentSetting) error
+	GetMarks(ctx context.Context, postID uint64) ([]*model.PostCommentMark, error)
+	GetMark(ctx context.Context, commentID uint64, kind int8) (*model.PostCommentMark, error)
+	CreateMark(ctx context.Context, mark *model.PostCommentMark) error
+	CreateMarkWithLimit(ctx context.Context, mark *model.PostCommentMark, limit int64) (bool, error)
+	DeleteMark(ctx context.Context, commentID uint64, kind int8) (bool, error)
+	DeleteCommentMarks(ctx context.Context, commentID uint64) error
+}
+
+type postCommentControlRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewPostCommentControlRepo(db *gorm.DB) PostCommentControlRepo {
+	return &postCommentControlRepoImpl{db: db}
+}
+
+// GetSetting 获取笔记的评论设置，未设置时返回 nil
+func (s *postCommentControlRepoImpl) GetSetting(ctx context.Context, postID uint64) (*model.PostCommentSetting, error) {
+	var setting model.PostCommentSetting
+	err := s.db.WithContext(ctx).Where("post_id = ?", postID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func (s *postCommentControlRepoImpl) SaveSetting(ctx context.Context, setting *model.PostCommentSetting) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"comment_mode", "updated_at"}),
+	}).Create(setting).Error
+}
+
+// GetMarks 获取笔记下全部作者标记，按标记时间倒序
+func (s *postCommentControlRepoImpl) GetMarks(ctx context.Context, postID uint64) ([]*model.PostCommentMark, error) {
+	marks := make([]*model.PostCommentMark, 0)
+	err := s.db.WithContext(ctx).
+		Where("post_id = ?", postID).
+		Order("created_at DESC, id DESC").
+		Find(&marks).Error
+	return marks, err
+}
+
+func (s *postCommentControlRepoImpl) GetMark(ctx context.Context, commentID uint64, kind int8) (*model.PostCommentMark, error) {
+	var mark model.PostCommentMark
+	err := s.db.WithContext(ctx).Where("comment_id = ? AND kind = ?", commentID, kind).First(&mark).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mark, nil
+}
+
+func (s *postCommentControlRepoImpl) CreateMark(ctx context.Context, mark *model.PostCommentMark) error {
+	return s.db.WithContext(ctx).Create(mark).Error
+}
+
+// CreateMarkWithLimit 笔记下同类标记未达上限时写入，锁定笔记行使同一笔记的并发写入串行执行，达到上限时返回 false
+func (s *postCommentControlRepoImpl) CreateMarkWithLimit(ctx context.Context, mark *model.PostCommentMark, limit int64) (bool, error) {
+	created := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var post model.Post
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", mark.PostID).
+			First(&post).Error
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		err = tx.Model(&model.PostCommentMark{}).
+			Where("post_id = ? AND kind = ?", mark.PostID, mark.Kind).
+			Count(&count).Error
+		if err != nil || count >= limit {
+			return err
+		}
+		if err = tx.Create(mark).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (s *postCommentControlRepoImpl) DeleteMark(ctx context.Context, commentID uint64, kind int8) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Where("comment_id = ? AND kind = ?", commentID, kind).
+		Delete(&model.PostCommentMark{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteCommentMarks 评论删除后清理其全部标记
+func (s *postCommentControlRepoImpl) DeleteCommentMarks(ctx context.Context, commentID uint64) error {
+	return s.db.WithContext(ctx).
+		Where("comment_id = ?", commentID).
+		Delete(&model.PostCommentMark{}).Error
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PostCommentEditRepo interface {
+	SubmitEdit(ctx context.Context, edit *model.PostCommentEdit) (bool, error)
+	GetPendingEdit(ctx context.Context, commentID uint64) (*model.PostCommentEdit, error)
+	ApplyEdit(ctx context.Context, edit *model.PostCommentEdit, approved bool, now time.Time) error
+	GetEdits(ctx context.Context, commentID uint64, approvedOnly bool) ([]*model.PostCommentEdit, error)
+	GetLastEditTimes(ctx context.Context, commentIDs []uint64) (map[uint64]time.Time, error)
+}
+
+type postCommentEditRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewPostCommentEditRepo(db *gorm.DB) PostCommentEditRepo {
+	return &postCommentEditRepoImpl{db: db}
+}
+
+// SubmitEdit 记录待审核的编辑并标记评论有待审核编辑，评论状态保持不变
+// 评论已不处于通过状态或已有待审核编辑时返回 false
+func (s *postCommentEditRepoImpl) SubmitEdit(ctx context.Context, edit *model.PostCommentEdit) (bool, error) {
+	submitted := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PostComment{}).
+			Where("id = ? AND status = ? AND is_deleted = ? AND edit_pending = ?", edit.CommentID, 1, false, false).
+			Update("edit_pending", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		submitted = true
+		return tx.Create(edit).Error
+	})
+	return submitted, err
+}
+
+// GetPendingEdit 获取评论最近一次待审核的编辑
+func (s *postCommentEditRepoImpl) GetPendingEdit(ctx context.Context, commentID uint64) (*model.PostCommentEdit, error) {
+	var edit model.PostCommentEdit
+	err := s.db.WithContext(ctx).
+		Where("comment_id = ? AND status = ?", commentID, model.CommentEditPending).
+		Order("id DESC").
+		First(&edit).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &edit, nil
+}
+
+// ApplyEdit 写入编辑审核结果并清除待审核标记：通过时替换评论内容，未通过时保留原内容
+// 审核期间评论已被下架或删除的，只记录编辑结果，不改动评论内容
+func (s *postCommentEditRepoImpl) ApplyEdit(ctx context.Context, edit *model.PostCommentEdit, approved bool, now time.Time) error {
+	editStatus := model.CommentEditRejected
+	if approved {
+		editStatus = model.CommentEditApproved
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.PostCommentEdit{}).
+			Where("id = ?", edit.ID).
+			Updates(map[string]any{"status": editStatus, "reviewed_at": now}).Error
+		if err != nil {
+			return err
+		}
+		updates := map[string]any{"edit_pending": false}
+		if approved {
+			updates["content"] = gorm.Expr("IF(status = ? AND is_deleted = ?, ?, content)", 1, false, edit.Content)
+		}
+		return tx.Model(&model.PostComment{}).
+			Where("id = ? AND edit_pending = ?", edit.CommentID, true).
+			Updates(updates).Error
+	})
+}
+
+// GetEdits 按时间倒序获取评论的编辑历史
+func (s *postCommentEditRepoImpl) GetEdits(ctx context.Context, commentID uint64, approvedOnly bool) ([]*model.PostCommentEdit, error) {
+	db := s.db.WithContext(ctx).Where("comment_id = ?", commentID)
+	if approvedOnly {
+		db = db.Where("status = ?", model.CommentEditApproved)
+	}
+	edits := make([]*model.PostCommentEdit, 0)
+	err := db.Order("id DESC").Find(&edits).Error
+	return edits, err
+}
+
+// GetLastEditTimes 批量获取评论最近一次编辑生效的时间，未编辑过的评论不在结果中
+func (s *postCommentEditRepoImpl) GetLastEditTimes(ctx context.Context, commentIDs []uint64) (map[uint64]time.Time, error) {
+	type Result struct {
+		CommentID  uint64
+		ReviewedAt time.Time
+	}
+	var results []Result
+	res := make(map[uint64]time.Time)
+
+	if len(commentIDs) == 0 {
+		return res, nil
+	}
+
+	err := s.db.WithContext(ctx).Model(&model.PostCommentEdit{}).
+		Select("comment_id, MAX(reviewed_at) AS reviewed_at").
+		Where("comment_id IN ? AND status = ?", commentIDs, model.CommentEditApproved).
+		Group("comment_id").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range results {
+		res[r.CommentID] = r.ReviewedAt
+	}
+	return res, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostDiscussionSummaryRepo interface {
+	GetSummary(ctx context.Context, postID uint64) (*model.PostDiscussionSummary, error)
+	SaveSummary(ctx context.Context, summary *model.PostDiscussionSummary) error
+}
+
+type postDiscussionSummaryRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewPostDiscussionSummaryRepo(db *gorm.DB) PostDiscussionSummaryRepo {
+	return &postDiscussionSummaryRepoImpl{db: db}
+}
+
+func (s *postDiscussionSummaryRepoImpl) GetSummary(ctx context.Context, postID uint64) (*model.PostDiscussionSummary, error) {
+	var summary model.PostDiscussionSummary
+	err := s.db.WithContext(ctx).Where("post_id = ?", postID).First(&summary).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// SaveSummary 写入或覆盖笔记的讨论总结
+func (s *postDiscussionSummaryRepoImpl) SaveSummary(ctx context.Context, summary *model.PostDiscussionSummary) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"overview", "viewpoints", "comment_count", "model", "updated_at"}),
+	}).Create(summary).Error
+}
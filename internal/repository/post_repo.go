@@ -25,6 +25,8 @@ type PostRepo interface {
 	GetPostMedias(ctx context.Context, postId uint64) (model.MediaList, error)
 	GetPostTagNames(ctx context.Context, postId uint64) ([]string, error)
 	SyncPostMainTag(ctx context.Context, postID uint64, tagName string) error
+	ScanPosts(ctx context.Context, lastID uint64, limit int) ([]*model.Post, error)
+	CountAllPosts(ctx context.Context) (int64, error)
 }
 
 type PostRepoImpl struct {
@@ -239,3 +241,51 @@ func (s *PostRepoImpl) SyncPostMainTag(ctx context.Context, postID uint64, tagNa
 		TagID:  tag.ID,
 	}).Error
 }
+
+// ScanPosts 按 ID 升序遍历全部笔记（含已删除），并填充纯文本字段，用于索引重建与对账
+func (s *PostRepoImpl) ScanPosts(ctx context.Context, lastID uint64, limit int) ([]*model.Post, error) {
+	posts := make([]*model.Post, 0, limit)
+	err := s.db.WithContext(ctx).
+		Where("id > ?", lastID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil || len(posts) == 0 {
+		return posts, err
+	}
+
+	ids := make([]uint64, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	type Result struct {
+		ID           uint64
+		PlainContent string
+	}
+	var results []Result
+	err = s.db.WithContext(ctx).
+		Table("posts").
+		Select("id, plain_content").
+		Where("id IN ?", ids).
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	plainMap := make(map[uint64]string, len(results))
+	for _, r := range results {
+		plainMap[r.ID] = r.PlainContent
+	}
+	for _, p := range posts {
+		p.PlainContent = plainMap[p.ID]
+	}
+	return posts, nil
+}
+
+// CountAllPosts 统计全部笔记数量（含已删除）
+func (s *PostRepoImpl) CountAllPosts(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&model.Post{}).Count(&count).Error
+	return count, err
+}
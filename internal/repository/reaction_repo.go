@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepo interface {
+	SetReaction(ctx context.Context, reaction *model.Reaction) (string, error)
+	DeleteReaction(ctx context.Context, targetType int8, targetID, userID uint64) (string, error)
+	GetUserReactions(ctx context.Context, userID uint64, targetType int8, targetIDs []uint64) (map[uint64]string, error)
+	GetReactionCounts(ctx context.Context, targetType int8, targetIDs []uint64) (map[uint64]map[string]int64, error)
+	SyncReactionCounts(ctx context.Context, targetType int8, targetID uint64, counts map[string]int64) error
+}
+
+type reactionRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewReactionRepo(db *gorm.DB) ReactionRepo {
+	return &reactionRepoImpl{db: db}
+}
+
+// SetReaction 写入或替换用户的表情回应，返回替换前的表情，此前未回应时返回空串
+func (s *reactionRepoImpl) SetReaction(ctx context.Context, reaction *model.Reaction) (string, error) {
+	var previous string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing model.Reaction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("target_type = ? AND target_id = ? AND user_id = ?", reaction.TargetType, reaction.TargetID, reaction.UserID).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(reaction).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		previous = existing.Emoji
+		if previous == reaction.Emoji {
+			return nil
+		}
+		return tx.Model(&existing).Update("emoji", reaction.Emoji).Error
+	})
+	return previous, err
+}
+
+// DeleteReaction 取消用户的表情回应，返回被取消的表情，未回应时返回空串
+func (s *reactionRepoImpl) DeleteReaction(ctx context.Context, targetType int8, targetID, userID uint64) (string, error) {
+	var previous string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing model.Reaction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("target_type = ? AND target_id = ? AND user_id = ?", targetType, targetID, userID).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		previous = existing.Emoji
+		return tx.Delete(&existing).Error
+	})
+	return previous, err
+}
+
+// GetUserReactions 批量获取用户对一组对象的表情回应
+func (s *reactionRepoImpl) GetUserReactions(ctx context.Context, userID uint64, targetType int8, targetIDs []uint64) (map[uint64]string, error) {
+	res := make(map[uint64]string)
+	if len(targetIDs) == 0 {
+		return res, nil
+	}
+
+	var list []*model.Reaction
+	err := s.db.WithContext(ctx).
+		Select("target_id", "emoji").
+		Where("user_id = ? AND target_type = ? AND target_id IN ?", userID, targetType, targetIDs).
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range list {
+		res[r.TargetID] = r.Emoji
+	}
+	return res, nil
+}
+
+// GetReactionCounts 批量获取已落库的各对象各表情回应数
+func (s *reactionRepoImpl) GetReactionCounts(ctx context.Context, targetType int8, targetIDs []uint64) (map[uint64]map[string]int64, error) {
+	res := make(map[uint64]map[string]int64)
+	if len(targetIDs) == 0 {
+		return res, nil
+	}
+
+	var list []*model.ReactionCount
+	err := s.db.WithContext(ctx).
+		Where("target_type = ? AND target_id IN ?", targetType, targetIDs).
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range list {
+		if res[c.TargetID] == nil {
+			res[c.TargetID] = make(map[string]int64)
+		}
+		res[c.TargetID][c.Emoji] = c.Count
+	}
+	return res, nil
+}
+
+// SyncReactionCounts 用 Redis 中的计数覆盖对象的回应计数，计数为 0 的表情不落库
+func (s *reactionRepoImpl) SyncReactionCounts(ctx context.Context, targetType int8, targetID uint64, counts map[string]int64) error {
+	now := time.Now()
+	list := make([]*model.ReactionCount, 0, len(counts))
+	for emoji, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		list = append(list, &model.ReactionCount{
+			TargetType: targetType,
+			TargetID:   targetID,
+			Emoji:      emoji,
+			Count:      count,
+			UpdatedAt:  now,
+		})
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("target_type = ? AND target_id = ?", targetType, targetID).
+			Delete(&model.ReactionCount{}).Error
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		return tx.Create(&list).Error
+	})
+}
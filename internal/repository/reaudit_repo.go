@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReauditRepo interface {
+	CreateCampaign(ctx context.Context, c *model.ReauditCampaign) error
+	GetCampaign(ctx context.Context, id uint64) (*model.ReauditCampaign, error)
+	GetCampaigns(ctx context.Context, limit, offset int) ([]*model.ReauditCampaign, int64, error)
+	TransitState(ctx context.Context, id uint64, from []int8, to int8) (bool, error)
+	SaveProgress(ctx context.Context, id uint64, cursorID uint64, scanned, changed, failed int) error
+	FinishCampaign(ctx context.Context, id uint64, state int8, errMsg string) error
+	ScanSubjects(ctx context.Context, c *model.ReauditCampaign, lastID uint64, limit int) ([]*model.ReauditSubject, error)
+	CreateItems(ctx context.Context, items []*model.ReauditItem) error
+	GetItems(ctx context.Context, campaignID uint64, applyState *int8, limit, offset int) ([]*model.ReauditItem, int64, error)
+	GetTransitions(ctx context.Context, campaignID uint64) ([]*model.ReauditTransition, error)
+	GetPendingItems(ctx context.Context, campaignID uint64, lastID uint64, limit int) ([]*model.ReauditItem, error)
+	ApplyItem(ctx context.Context, targetType int8, item *model.ReauditItem) (bool, error)
+}
+
+type reauditRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewReauditRepo(db *gorm.DB) ReauditRepo {
+	return &reauditRepoImpl{db: db}
+}
+
+func (s *reauditRepoImpl) CreateCampaign(ctx context.Context, c *model.ReauditCampaign) error {
+	return s.db.WithContext(ctx).Create(c).Error
+}
+
+func (s *reauditRepoImpl) GetCampaign(ctx context.Context, id uint64) (*model.ReauditCampaign, error) {
+	var c model.ReauditCampaign
+	err := s.db.WithContext(ctx).First(&c, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *reauditRepoImpl) GetCampaigns(ctx context.Context, limit, offset int) ([]*model.ReauditCampaign, int64, error) {
+	var total int64
+	db := s.db.WithContext(ctx).Model(&model.ReauditCampaign{})
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*model.ReauditCampaign, 0, limit)
+	err := db.Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+// TransitState 仅在任务处于 from 中任一状态时切换为 to
+func (s *reauditRepoImpl) TransitState(ctx context.Context, id uint64, from []int8, to int8) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Model(&model.ReauditCampaign{}).
+		Where("id = ? AND state IN ?", id, from).
+		Updates(map[string]any{"state": to, "error": ""})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SaveProgress 推进扫描游标并累加计数
+func (s *reauditRepoImpl) SaveProgress(ctx context.Context, id uint64, cursorID uint64, scanned, changed, failed int) error {
+	return s.db.WithContext(ctx).
+		Model(&model.ReauditCampaign{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"cursor_id": cursorID,
+			"scanned":   gorm.Expr("scanned + ?", scanned),
+			"changed":   gorm.Expr("changed + ?", changed),
+			"failed":    gorm.Expr("failed + ?", failed),
+		}).Error
+}
+
+// FinishCampaign 结束复审或应用阶段，记录完成时间与失败原因
+func (s *reauditRepoImpl) FinishCampaign(ctx context.Context, id uint64, state int8, errMsg string) error {
+	return s.db.WithContext(ctx).
+		Model(&model.ReauditCampaign{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"state":       state,
+			"error":       errMsg,
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// ScanSubjects 按任务筛选条件以 ID 升序读取待复审的笔记或评论（不含已删除）
+func (s *reauditRepoImpl) ScanSubjects(ctx context.Context, c *model.ReauditCampaign, lastID uint64, limit int) ([]*model.ReauditSubject, error) {
+	table := "posts"
+	selects := "posts.id, posts.title, posts.plain_content AS content, posts.media_list AS media, posts.status"
+	if c.TargetType == model.ReauditTargetComment {
+		table = "post_comments"
+		selects = "post_comments.id, post_comments.content, post_comments.media_info AS media, post_comments.status"
+	}
+
+	db := s.db.WithContext(ctx).
+		Table(table).
+		Select(selects).
+		Where(table+".id > ? AND "+table+".is_deleted = ?", lastID, false)
+	if c.ContentStatus > 0 {
+		db = db.Where(table+".status = ?", c.ContentStatus)
+	} else {
+		db = db.Where(table+".status IN ?", []int8{1, 2})
+	}
+	if c.StartAt != nil {
+		db = db.Where(table+".created_at >= ?", *c.StartAt)
+	}
+	if c.EndAt != nil {
+		db = db.Where(table+".created_at < ?", *c.EndAt)
+	}
+	if c.Tag != "" && c.TargetType == model.ReauditTargetPost {
+		db = db.Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+			Joins("JOIN tags ON tags.id = post_tags.tag_id").
+			Where("tags.name = ?", c.Tag)
+	}
+
+	list := make([]*model.ReauditSubject, 0, limit)
+	err := db.Order(table + ".id ASC").Limit(limit).Scan(&list).Error
+	return list, err
+}
+
+func (s *reauditRepoImpl) CreateItems(ctx context.Context, items []*model.ReauditItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&items).Error
+}
+
+func (s *reauditRepoImpl) GetItems(ctx context.Context, campaignID uint64, applyState *int8, limit, offset int) ([]*model.ReauditItem, int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.ReauditItem{}).Where("campaign_id = ?", campaignID)
+	if applyState != nil {
+		db = db.Where("apply_state = ?", *applyState)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	list := make([]*model.ReauditItem, 0, limit)
+	err := db.Order("id ASC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+// GetTransitions 统计任务内各类状态变化的数量
+func (s *reauditRepoImpl) GetTransitions(ctx context.Context, campaignID uint64) ([]*model.ReauditTransition, error) {
+	list := make([]*model.ReauditTransition, 0)
+	err := s.db.WithContext(ctx).
+		Model(&model.ReauditItem{}).
+		Select("old_status, new_status, COUNT(*) AS count").
+		Where("campaign_id = ?", campaignID).
+		Group("old_status, new_status").
+		Order("old_status, new_status").
+		Scan(&list).Error
+	return list, err
+}
+
+func (s *reauditRepoImpl) GetPendingItems(ctx context.Context, campaignID uint64, lastID uint64, limit int) ([]*model.ReauditItem, error) {
+	list := make([]*model.ReauditItem, 0, limit)
+	err := s.db.WithContext(ctx).
+		Where("campaign_id = ? AND apply_state = ? AND id > ?", campaignID, model.ReauditItemPending, lastID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// ApplyItem 将复审结果写回内容，仅在内容状态仍为复审前状态时生效，否则标记为跳过
+func (s *reauditRepoImpl) ApplyItem(ctx context.Context, targetType int8, item *model.ReauditItem) (bool, error) {
+	table := "posts"
+	if targetType == model.ReauditTargetComment {
+		table = "post_comments"
+	}
+
+	var applied bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table(table).
+			Where("id = ? AND status = ? AND is_deleted = ?", item.TargetID, item.OldStatus, false).
+			Update("status", item.NewStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		applied = result.RowsAffected > 0
+
+		state := model.ReauditItemSkipped
+		if applied {
+			state = model.ReauditItemApplied
+		}
+		if err := tx.Model(&model.ReauditItem{}).Where("id = ?", item.ID).Update("apply_state", state).Error; err != nil {
+			return err
+		}
+		if !applied {
+			return nil
+		}
+		return tx.Model(&model.ReauditCampaign{}).
+			Where("id = ?", item.CampaignID).
+			Update("applied", gorm.Expr("applied + 1")).Error
+	})
+	return applied, err
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RecommendEventRepo interface {
+	BatchCreateEvents(ctx context.Context, events []*model.RecommendEvent) error
+	GetVariantStats(ctx context.Context, experimentID string, since time.Time) ([]*model.RecommendVariantStat, error)
+}
+
+type recommendEventRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewRecommendEventRepo(db *gorm.DB) RecommendEventRepo {
+	return &recommendEventRepoImpl{db: db}
+}
+
+// BatchCreateEvents 批量写入实验事件
+func (s *recommendEventRepoImpl) BatchCreateEvents(ctx context.Context, events []*model.RecommendEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).CreateInBatches(events, 100).Error
+}
+
+// GetVariantStats 按实验分组聚合曝光、点击、点赞与停留数据
+func (s *recommendEventRepoImpl) GetVariantStats(ctx context.Context, experimentID string, since time.Time) ([]*model.RecommendVariantStat, error) {
+	stats := make([]*model.RecommendVariantStat, 0)
+	err := s.db.WithContext(ctx).
+		Model(&model.RecommendEvent{}).
+		Select("variant, "+
+			"SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS exposures, "+
+			"SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS clicks, "+
+			"SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS likes, "+
+			"SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS dwell_count, "+
+			"SUM(CASE WHEN event_type = ? THEN dwell_ms ELSE 0 END) AS dwell_total",
+			model.RecommendEventExposure, model.RecommendEventClick, model.RecommendEventLike,
+			model.RecommendEventDwell, model.RecommendEventDwell).
+		Where("experiment_id = ? AND created_at >= ?", experimentID, since).
+		Group("variant").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
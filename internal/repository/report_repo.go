@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportRepo interface {
+	CreateReport(ctx context.Context, reportCase *model.ReportCase, report *model.Report) (*model.ReportCase, error)
+	GetReportCase(ctx context.Context, id uint64) (*model.ReportCase, error)
+	GetReportCases(ctx context.Context, statuses []int8, targetType int8, claimedBy uint64, limit, offset int) ([]*model.ReportCase, int64, error)
+	ClaimReportCase(ctx context.Context, id, auditorID uint64, expireBefore time.Time) (bool, error)
+	CloseReportCase(ctx context.Context, id, auditorID uint64, status int8, note string) (bool, error)
+	GetResolvedCaseIDs(ctx context.Context, targetType int8, targetID uint64) ([]uint64, error)
+}
+
+type reportRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewReportRepo(db *gorm.DB) ReportRepo {
+	return &reportRepoImpl{db: db}
+}
+
+// CreateReport 写入举报：合并到对象未结案的工单，不存在时新建，并累加举报人数与优先级
+// 未结案工单由 uk_open_target 保证唯一，并发的首次举报在插入时冲突后合并到同一工单
+func (s *reportRepoImpl) CreateReport(ctx context.Context, reportCase *model.ReportCase, report *model.Report) (*model.ReportCase, error) {
+	var res model.ReportCase
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		candidate := *reportCase
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&candidate).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("target_type = ? AND target_id = ? AND target_seq = ? AND status IN ?",
+				reportCase.TargetType, reportCase.TargetID, reportCase.TargetSeq,
+				[]int8{model.ReportCasePending, model.ReportCaseClaimed}).
+			First(&res).Error
+		if err != nil {
+			return err
+		}
+
+		report.CaseID = res.ID
+		if err = tx.Create(report).Error; err != nil {
+			return err
+		}
+
+		weight := model.ReportReasonWeights[report.Reason]
+		res.ReportCount++
+		res.Priority += weight
+		return tx.Model(&model.ReportCase{}).
+			Where("id = ?", res.ID).
+			Updates(map[string]interface{}{
+				"report_count": gorm.Expr("report_count + 1"),
+				"priority":     gorm.Expr("priority + ?", weight),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetReportCase 获取工单及其举报明细
+func (s *reportRepoImpl) GetReportCase(ctx context.Context, id uint64) (*model.ReportCase, error) {
+	var res model.ReportCase
+	err := s.db.WithContext(ctx).
+		Preload("Reports", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&res, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetReportCases 按优先级分页获取工单，claimedBy 非 0 时只返回该审核员认领的工单
+func (s *reportRepoImpl) GetReportCases(ctx context.Context, statuses []int8, targetType int8, claimedBy uint64, limit, offset int) ([]*model.ReportCase, int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.ReportCase{}).Where("status IN ?", statuses)
+	if targetType > 0 {
+		db = db.Where("target_type = ?", targetType)
+	}
+	if claimedBy > 0 {
+		db = db.Where("claimed_by = ?", claimedBy)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]*model.ReportCase, 0, limit)
+	err := db.Order("priority DESC, created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&list).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// ClaimReportCase 认领工单：待处理、本人已认领或认领已超时的工单可被认领
+func (s *reportRepoImpl) ClaimReportCase(ctx context.Context, id, auditorID uint64, expireBefore time.Time) (bool, error) {
+	now := time.Now()
+	result := s.db.WithContext(ctx).
+		Model(&model.ReportCase{}).
+		Where("id = ?", id).
+		Where("status = ? OR (status = ? AND (claimed_by = ? OR claimed_at < ?))",
+			model.ReportCasePending, model.ReportCaseClaimed, auditorID, expireBefore).
+		Updates(map[string]interface{}{
+			"status":     model.ReportCaseClaimed,
+			"claimed_by": auditorID,
+			"claimed_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CloseReportCase 结案，仅认领人可操作
+func (s *reportRepoImpl) CloseReportCase(ctx context.Context, id, auditorID uint64, status int8, note string) (bool, error) {
+	now := time.Now()
+	result := s.db.WithContext(ctx).
+		Model(&model.ReportCase{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", id, model.ReportCaseClaimed, auditorID).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"handled_by":  auditorID,
+			"handle_note": note,
+			"handled_at":  now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetResolvedCaseIDs 获取对象已处置（举报成立）的工单ID
+func (s *reportRepoImpl) GetResolvedCaseIDs(ctx context.Context, targetType int8, targetID uint64) ([]uint64, error) {
+	ids := make([]uint64, 0)
+	err := s.db.WithContext(ctx).Model(&model.ReportCase{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, model.ReportCaseResolved).
+		Pluck("id", &ids).Error
+	return ids, err
+}
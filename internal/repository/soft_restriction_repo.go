@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SoftRestrictionRepo interface {
+	CreateRestriction(ctx context.Context, r *model.SoftRestriction) error
+	GetActiveRestriction(ctx context.Context, kind int8, targetID uint64, now time.Time) (*model.SoftRestriction, error)
+	ExtendRestriction(ctx context.Context, id uint64, expiresAt time.Time, reason string, operatorID uint64) error
+	LiftRestriction(ctx context.Context, kind int8, targetID uint64, now time.Time) (bool, error)
+	GetRestrictions(ctx context.Context, kind int8, activeOnly bool, now time.Time, limit, offset int) ([]*model.SoftRestriction, int64, error)
+	GetAllActiveRestrictions(ctx context.Context, kind int8, now time.Time) ([]*model.SoftRestriction, error)
+}
+
+type softRestrictionRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewSoftRestrictionRepo(db *gorm.DB) SoftRestrictionRepo {
+	return &softRestrictionRepoImpl{db: db}
+}
+
+func (s *softRestrictionRepoImpl) CreateRestriction(ctx context.Context, r *model.SoftRestriction) error {
+	return s.db.WithContext(ctx).Create(r).Error
+}
+
+// GetActiveRestriction 获取对象当前生效的软处置
+func (s *softRestrictionRepoImpl) GetActiveRestriction(ctx context.Context, kind int8, targetID uint64, now time.Time) (*model.SoftRestriction, error) {
+	var res model.SoftRestriction
+	err := s.db.WithContext(ctx).
+		Where("kind = ? AND target_id = ? AND lifted_at IS NULL AND expires_at > ?", kind, targetID, now).
+		Order("expires_at DESC").
+		First(&res).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ExtendRestriction 重复处置时改写到期时间与原因
+func (s *softRestrictionRepoImpl) ExtendRestriction(ctx context.Context, id uint64, expiresAt time.Time, reason string, operatorID uint64) error {
+	return s.db.WithContext(ctx).
+		Model(&model.SoftRestriction{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"expires_at": expiresAt,
+			"reason":     reason,
+			"created_by": operatorID,
+		}).Error
+}
+
+// LiftRestriction 提前解除对象全部生效中的软处置
+func (s *softRestrictionRepoImpl) LiftRestriction(ctx context.Context, kind int8, targetID uint64, now time.Time) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Model(&model.SoftRestriction{}).
+		Where("kind = ? AND target_id = ? AND lifted_at IS NULL AND expires_at > ?", kind, targetID, now).
+		Update("lifted_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *softRestrictionRepoImpl) GetRestrictions(ctx context.Context, kind int8, activeOnly bool, now time.Time, limit, offset int) ([]*model.SoftRestriction, int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.SoftRestriction{})
+	if kind > 0 {
+		db = db.Where("kind = ?", kind)
+	}
+	if activeOnly {
+		db = db.Where("lifted_at IS NULL AND expires_at > ?", now)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	list := make([]*model.SoftRestriction, 0, limit)
+	err := db.Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+// GetAllActiveRestrictions 获取某类全部生效中的软处置，用于重建缓存
+func (s *softRestrictionRepoImpl) GetAllActiveRestrictions(ctx context.Context, kind int8, now time.Time) ([]*model.SoftRestriction, error) {
+	list := make([]*model.SoftRestriction, 0)
+	err := s.db.WithContext(ctx).
+		Where("kind = ? AND lifted_at IS NULL AND expires_at > ?", kind, now).
+		Find(&list).Error
+	return list, err
+}
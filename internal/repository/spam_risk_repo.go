@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SpamRiskRepo interface {
+	UpsertRisk(ctx context.Context, risk *model.SpamRisk) error
+	GetRisk(ctx context.Context, userID uint64) (*model.SpamRisk, error)
+	GetRisks(ctx context.Context, state int8, minScore int, limit, offset int) ([]*model.SpamRisk, int64, error)
+	ReviewRisk(ctx context.Context, userID uint64, state int8, reviewerID uint64, now time.Time) (bool, error)
+}
+
+type spamRiskRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewSpamRiskRepo(db *gorm.DB) SpamRiskRepo {
+	return &spamRiskRepoImpl{db: db}
+}
+
+// UpsertRisk 写入最新风险分与信号，已确认的记录保持确认状态，其余重新进入待复核
+func (s *spamRiskRepoImpl) UpsertRisk(ctx context.Context, risk *model.SpamRisk) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"score":              risk.Score,
+			"peak_score":         gorm.Expr("GREATEST(peak_score, ?)", risk.Score),
+			"burst_likes":        risk.BurstLikes,
+			"follow_churn":       risk.FollowChurn,
+			"duplicate_comments": risk.DuplicateComments,
+			"new_account_links":  risk.NewAccountLinks,
+			"throttle_level":     risk.ThrottleLevel,
+			"state":              gorm.Expr("IF(state = ?, state, ?)", model.SpamRiskConfirmed, model.SpamRiskPending),
+		}),
+	}).Create(risk).Error
+}
+
+func (s *spamRiskRepoImpl) GetRisk(ctx context.Context, userID uint64) (*model.SpamRisk, error) {
+	var risk model.SpamRisk
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&risk).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &risk, nil
+}
+
+// GetRisks 按风险分倒序分页获取复核列表
+func (s *spamRiskRepoImpl) GetRisks(ctx context.Context, state int8, minScore int, limit, offset int) ([]*model.SpamRisk, int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.SpamRisk{})
+	if state > 0 {
+		db = db.Where("state = ?", state)
+	}
+	if minScore > 0 {
+		db = db.Where("score >= ?", minScore)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	list := make([]*model.SpamRisk, 0, limit)
+	err := db.Order("score DESC, updated_at DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, total, err
+}
+
+// ReviewRisk 复核待处理的风险记录
+func (s *spamRiskRepoImpl) ReviewRisk(ctx context.Context, userID uint64, state int8, reviewerID uint64, now time.Time) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Model(&model.SpamRisk{}).
+		Where("user_id = ? AND state = ?", userID, model.SpamRiskPending).
+		Updates(map[string]any{
+			"state":       state,
+			"reviewed_by": reviewerID,
+			"reviewed_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
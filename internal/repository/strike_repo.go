@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StrikeRepo interface {
+	CreateStrike(ctx context.Context, strike *model.UserStrike) (bool, error)
+	RevokeStrike(ctx context.Context, source int8, targetID uint64) (*model.UserStrike, error)
+	CountActiveStrikes(ctx context.Context, userID uint64, since time.Time) (int64, error)
+	GetStrikesByUser(ctx context.Context, userID uint64, limit int) ([]*model.UserStrike, error)
+	CreatePenalty(ctx context.Context, penalty *model.UserPenalty) error
+	GetActivePenalty(ctx context.Context, userID uint64, now time.Time) (*model.UserPenalty, error)
+	GetExpiredPenalties(ctx context.Context, now time.Time, limit int) ([]*model.UserPenalty, error)
+	LiftPenalty(ctx context.Context, id uint64, now time.Time) (bool, error)
+}
+
+type strikeRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewStrikeRepo(db *gorm.DB) StrikeRepo {
+	return &strikeRepoImpl{db: db}
+}
+
+// CreateStrike 记录违规，同一来源对象已记录过时返回 false
+func (s *strikeRepoImpl) CreateStrike(ctx context.Context, strike *model.UserStrike) (bool, error) {
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(strike)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeStrike 撤销违规记录，返回被撤销的记录，不存在或已撤销时返回 nil
+func (s *strikeRepoImpl) RevokeStrike(ctx context.Context, source int8, targetID uint64) (*model.UserStrike, error) {
+	var strike model.UserStrike
+	err := s.db.WithContext(ctx).
+		Where("source = ? AND target_id = ? AND revoked = ?", source, targetID, false).
+		First(&strike).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.db.WithContext(ctx).
+		Model(&model.UserStrike{}).
+		Where("id = ? AND revoked = ?", strike.ID, false).
+		Update("revoked", true)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	strike.Revoked = true
+	return &strike, nil
+}
+
+// CountActiveStrikes 统计指定时间之后未撤销的违规次数
+func (s *strikeRepoImpl) CountActiveStrikes(ctx context.Context, userID uint64, since time.Time) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).
+		Model(&model.UserStrike{}).
+		Where("user_id = ? AND revoked = ? AND created_at >= ?", userID, false, since).
+		Count(&count).Error
+	return count, err
+}
+
+// GetStrikesByUser 获取用户最近的违规记录
+func (s *strikeRepoImpl) GetStrikesByUser(ctx context.Context, userID uint64, limit int) ([]*model.UserStrike, error) {
+	list := make([]*model.UserStrike, 0, limit)
+	err := s.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (s *strikeRepoImpl) CreatePenalty(ctx context.Context, penalty *model.UserPenalty) error {
+	return s.db.WithContext(ctx).Create(penalty).Error
+}
+
+// GetActivePenalty 获取用户当前生效的最高等级处罚，无处罚时返回 nil
+func (s *strikeRepoImpl) GetActivePenalty(ctx context.Context, userID uint64, now time.Time) (*model.UserPenalty, error) {
+	var res model.UserPenalty
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND lifted_at IS NULL", userID).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("level DESC, expires_at DESC").
+		First(&res).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetExpiredPenalties 获取已到期但尚未解除的处罚
+func (s *strikeRepoImpl) GetExpiredPenalties(ctx context.Context, now time.Time, limit int) ([]*model.UserPenalty, error) {
+	list := make([]*model.UserPenalty, 0, limit)
+	err := s.db.WithContext(ctx).
+		Where("lifted_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// LiftPenalty 解除处罚，已被解除时返回 false
+func (s *strikeRepoImpl) LiftPenalty(ctx context.Context, id uint64, now time.Time) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Model(&model.UserPenalty{}).
+		Where("id = ? AND lifted_at IS NULL", id).
+		Update("lifted_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"Cornerstone/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepo interface {
+	GetTagsByIDs(ctx context.Context, ids []uint64) ([]*model.Tag, error)
+	GetPopularMainTags(ctx context.Context, limit int) ([]*model.Tag, error)
+	GetOnboardingTags(ctx context.Context, onlyEnabled bool) ([]*model.OnboardingTag, error)
+	SaveOnboardingTag(ctx context.Context, tag *model.OnboardingTag) error
+	DeleteOnboardingTag(ctx context.Context, tagID uint64) error
+}
+
+type tagRepoImpl struct {
+	db *gorm.DB
+}
+
+func NewTagRepo(db *gorm.DB) TagRepo {
+	return &tagRepoImpl{db: db}
+}
+
+// GetTagsByIDs 批量获取标签
+func (s *tagRepoImpl) GetTagsByIDs(ctx context.Context, ids []uint64) ([]*model.Tag, error) {
+	tags := make([]*model.Tag, 0)
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetPopularMainTags 按已发布帖子数量获取热门主标签
+func (s *tagRepoImpl) GetPopularMainTags(ctx context.Context, limit int) ([]*model.Tag, error) {
+	tags := make([]*model.Tag, 0)
+	err := s.db.WithContext(ctx).
+		Table("tags").
+		Select("tags.*").
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("JOIN posts ON posts.id = post_tags.post_id").
+		Where("posts.status = ? AND posts.is_deleted = ?", 1, false).
+		Group("tags.id").
+		Order("COUNT(*) DESC").
+		Limit(limit).
+		Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetOnboardingTags 获取兴趣引导标签列表
+func (s *tagRepoImpl) GetOnboardingTags(ctx context.Context, onlyEnabled bool) ([]*model.OnboardingTag, error) {
+	list := make([]*model.OnboardingTag, 0)
+	db := s.db.WithContext(ctx).Preload("Tag")
+	if onlyEnabled {
+		db = db.Where("enabled = ?", true)
+	}
+	err := db.Order("sort_order ASC, tag_id ASC").Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// SaveOnboardingTag 新增或更新兴趣引导标签
+func (s *tagRepoImpl) SaveOnboardingTag(ctx context.Context, tag *model.OnboardingTag) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tag_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sort_order", "enabled", "updated_at"}),
+	}).Omit("Tag").Create(tag).Error
+}
+
+// DeleteOnboardingTag 移除兴趣引导标签
+func (s *tagRepoImpl) DeleteOnboardingTag(ctx context.Context, tagID uint64) error {
+	return s.db.WithContext(ctx).Where("tag_id = ?", tagID).Delete(&model.OnboardingTag{}).Error
+}
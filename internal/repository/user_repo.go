@@ -21,8 +21,11 @@ type UserRepo interface {
 	UpdateUser(ctx context.Context, user *model.User) error
 	UpdateUserIsBan(ctx context.Context, id uint64, isBan bool) (int64, error)
 	UpdateUserDetail(ctx context.Context, detail *model.UserDetail) error
+	ResetUserDetailField(ctx context.Context, id uint64, column string, oldValue string, safeValue string) (bool, error)
 	UpdateUserFollowCount(ctx context.Context, id uint64, followerCount int64, followingCount int64) error
 	DeleteUser(ctx context.Context, id uint64) error
+	ScanUsers(ctx context.Context, lastID uint64, limit int) ([]*model.User, error)
+	CountAllUsers(ctx context.Context) (int64, error)
 }
 
 type UserRepoImpl struct {
@@ -175,6 +178,18 @@ func (s *UserRepoImpl) UpdateUserDetail(ctx context.Context, detail *model.UserD
 	return nil
 }
 
+// ResetUserDetailField 将资料字段回退为安全值，字段已被用户再次修改时不覆盖
+func (s *UserRepoImpl) ResetUserDetailField(ctx context.Context, id uint64, column string, oldValue string, safeValue string) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Model(&model.UserDetail{}).
+		Where("user_id = ? AND "+column+" = ?", id, oldValue).
+		Update(column, safeValue)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (s *UserRepoImpl) UpdateUserFollowCount(ctx context.Context, id uint64, followerCount int64, followingCount int64) error {
 	result := s.db.WithContext(ctx).Model(&model.UserDetail{}).Where("user_id = ?", id).Updates(map[string]interface{}{
 		"followers_count": followerCount,
@@ -235,3 +250,22 @@ func (s *UserRepoImpl) DeleteUser(ctx context.Context, id uint64) error {
 		return nil
 	})
 }
+
+// ScanUsers 按 ID 升序遍历全部用户（含已注销），用于索引重建与对账
+func (s *UserRepoImpl) ScanUsers(ctx context.Context, lastID uint64, limit int) ([]*model.User, error) {
+	users := make([]*model.User, 0, limit)
+	err := s.db.WithContext(ctx).
+		Preload("UserDetail").
+		Where("id > ?", lastID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// CountAllUsers 统计全部用户数量（含已注销）
+func (s *UserRepoImpl) CountAllUsers(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&model.User{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/mongo"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	"fmt"
+	log "log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+)
+
+// appealMaxPerItem 每条内容最多可申诉次数
+const appealMaxPerItem = 2
+
+var appealTargetNames = map[int8]string{
+	model.AppealTargetPost:    "笔记",
+	model.AppealTargetComment: "评论",
+}
+
+type AppealService interface {
+	CreateAppeal(ctx context.Context, userID uint64, req *dto.AppealCreateDTO) error
+	GetMyAppeals(ctx context.Context, userID uint64, page, pageSize int) (*dto.AppealListDTO, error)
+	GetAppealQueue(ctx context.Context, req *dto.AppealQueueQueryDTO) (*dto.AppealListDTO, error)
+	ApproveAppeal(ctx context.Context, auditorID, appealID uint64, req *dto.AppealHandleDTO) error
+	RejectAppeal(ctx context.Context, auditorID, appealID uint64, req *dto.AppealHandleDTO) error
+}
+
+type appealServiceImpl struct {
+	appealRepo       repository.AppealRepo
+	reportRepo       repository.ReportRepo
+	postRepo         repository.PostRepo
+	postActionRepo   repository.PostActionRepo
+	sysBoxRepo       mongo.SysBoxRepo
+	postService      PostService
+	mediaHashService MediaHashService
+	strikeService    StrikeService
+}
+
+func NewAppealService(
+	appealRepo repository.AppealRepo,
+	reportRepo repository.ReportRepo,
+	postRepo repository.PostRepo,
+	postActionRepo repository.PostActionRepo,
+	sysBoxRepo mongo.SysBoxRepo,
+	postService PostService,
+	mediaHashService MediaHashService,
+	strikeService StrikeService,
+) AppealService {
+	return &appealServiceImpl{
+		appealRepo:       appealRepo,
+		reportRepo:       reportRepo,
+		postRepo:         postRepo,
+		postActionRepo:   postActionRepo,
+		sysBoxRepo:       sysBoxRepo,
+		postService:      postService,
+		mediaHashService: mediaHashService,
+		strikeService:    strikeService,
+	}
+}
+
+// CreateAppeal 作者对被拒绝的笔记或评论发起申诉，附带模型审核结论进入审核队列
+func (s *appealServiceImpl) CreateAppeal(ctx context.Context, userID uint64, req *dto.AppealCreateDTO) error {
+	lockKey := consts.AppealLock + strconv.Itoa(int(req.TargetType)) + ":" + strconv.FormatUint(req.TargetID, 10)
+	lockVal := uuid.NewString()
+	ok, err := redis.TryLock(ctx, lockKey, lockVal, 5*time.Second, 0)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrActionDuplicate
+	}
+	defer redis.UnLock(ctx, lockKey, lockVal)
+
+	snapshot, err := s.getRejectedSnapshot(ctx, userID, req.TargetType, req.TargetID)
+	if err != nil {
+		return err
+	}
+
+	appeals, err := s.appealRepo.GetAppealsByTarget(ctx, req.TargetType, req.TargetID)
+	if err != nil {
+		return err
+	}
+	for _, a := range appeals {
+		if a.Status == model.AppealPending {
+			return ErrAppealPending
+		}
+	}
+	if len(appeals) >= appealMaxPerItem {
+		return ErrAppealLimit
+	}
+
+	target := "post"
+	if req.TargetType == model.AppealTargetComment {
+		target = "comment"
+	}
+	decision, err := redis.GetValue(ctx, consts.ModerationDecisionKey+target+":"+strconv.FormatUint(req.TargetID, 10))
+	if err != nil {
+		log.WarnContext(ctx, "get moderation decision failed", "target", target, "id", req.TargetID, "err", err)
+	}
+
+	return s.appealRepo.CreateAppeal(ctx, &model.Appeal{
+		TargetType:  req.TargetType,
+		TargetID:    req.TargetID,
+		UserID:      userID,
+		Message:     strings.TrimSpace(req.Message),
+		Snapshot:    snapshot,
+		LLMDecision: decision,
+		Status:      model.AppealPending,
+	})
+}
+
+// GetMyAppeals 获取用户自己的申诉记录
+func (s *appealServiceImpl) GetMyAppeals(ctx context.Context, userID uint64, page, pageSize int) (*dto.AppealListDTO, error) {
+	list, total, err := s.appealRepo.GetAppealsByUser(ctx, userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return toAppealListDTO(list, total), nil
+}
+
+// GetAppealQueue 审核端获取申诉队列
+func (s *appealServiceImpl) GetAppealQueue(ctx context.Context, req *dto.AppealQueueQueryDTO) (*dto.AppealListDTO, error) {
+	list, total, err := s.appealRepo.GetAppeals(ctx, req.Status, req.TargetType, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return toAppealListDTO(list, total), nil
+}
+
+// ApproveAppeal 申诉通过，恢复内容并重新触发索引
+func (s *appealServiceImpl) ApproveAppeal(ctx context.Context, auditorID, appealID uint64, req *dto.AppealHandleDTO) error {
+	appeal, err := s.closeAppeal(ctx, auditorID, appealID, model.AppealApproved, req.Note)
+	if err != nil {
+		return err
+	}
+
+	switch appeal.TargetType {
+	case model.AppealTargetPost:
+		err = s.postService.UpdatePostStatus(ctx, appeal.TargetID, consts.PostStatusNormal)
+	case model.AppealTargetComment:
+		err = s.postActionRepo.UpdateCommentStatus(ctx, appeal.TargetID, CommentStatusApproved)
+	}
+	if err != nil {
+		if reopenErr := s.appealRepo.ReopenAppeal(ctx, appeal.ID); reopenErr != nil {
+			log.ErrorContext(ctx, "reopen appeal failed", "appealId", appeal.ID, "err", reopenErr)
+		}
+		return err
+	}
+
+	s.revokeStrikes(ctx, appeal)
+
+	s.notifyAuthor(ctx, appeal, fmt.Sprintf("你对%s的申诉已通过，内容已恢复展示。", appealTargetNames[appeal.TargetType]))
+	return nil
+}
+
+// revokeStrikes 撤销内容恢复后不再成立的违规：审核拒绝按内容ID记录，举报下架按工单ID记录
+func (s *appealServiceImpl) revokeStrikes(ctx context.Context, appeal *model.Appeal) {
+	source, reportTarget := model.StrikeSourcePostAudit, model.ReportTargetPost
+	if appeal.TargetType == model.AppealTargetComment {
+		source, reportTarget = model.StrikeSourceCommentAudit, model.ReportTargetComment
+	}
+	if err := s.strikeService.RevokeStrike(ctx, source, appeal.TargetID); err != nil {
+		log.ErrorContext(ctx, "revoke strike failed", "appealId", appeal.ID, "err", err)
+	}
+
+	caseIDs, err := s.reportRepo.GetResolvedCaseIDs(ctx, reportTarget, appeal.TargetID)
+	if err != nil {
+		log.ErrorContext(ctx, "get resolved report cases failed", "appealId", appeal.ID, "err", err)
+		return
+	}
+	for _, caseID := range caseIDs {
+		if err = s.strikeService.RevokeStrike(ctx, model.StrikeSourceReport, caseID); err != nil {
+			log.ErrorContext(ctx, "revoke report strike failed", "appealId", appeal.ID, "caseId", caseID, "err", err)
+		}
+	}
+}
+
+// RejectAppeal 驳回申诉，内容保持拒绝状态
+func (s *appealServiceImpl) RejectAppeal(ctx context.Context, auditorID, appealID uint64, req *dto.AppealHandleDTO) error {
+	appeal, err := s.closeAppeal(ctx, auditorID, appealID, model.AppealRejected, req.Note)
+	if err != nil {
+		return err
+	}
+
+	s.mediaHashService.BlockConfirmedMedia(ctx, auditorID, appeal.TargetType, appeal.TargetID, req.MediaURLs)
+
+	content := fmt.Sprintf("你对%s的申诉未通过。", appealTargetNames[appeal.TargetType])
+	if appeal.HandleNote != "" {
+		content += "处理说明：" + appeal.HandleNote
+	}
+	s.notifyAuthor(ctx, appeal, content)
+	return nil
+}
+
+func (s *appealServiceImpl) closeAppeal(ctx context.Context, auditorID, appealID uint64, status int8, note string) (*model.Appeal, error) {
+	appeal, err := s.appealRepo.GetAppeal(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+
+	note = strings.TrimSpace(note)
+	ok, err := s.appealRepo.CloseAppeal(ctx, appealID, auditorID, status, note)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrAppealHandled
+	}
+	appeal.Status = status
+	appeal.HandleNote = note
+	return appeal, nil
+}
+
+// getRejectedSnapshot 校验内容归属与拒绝状态，返回内容快照
+func (s *appealServiceImpl) getRejectedSnapshot(ctx context.Context, userID uint64, targetType int8, targetID uint64) (string, error) {
+	switch targetType {
+	case model.AppealTargetPost:
+		post, err := s.postRepo.GetPostByAllStatus(ctx, targetID)
+		if err != nil {
+			return "", err
+		}
+		if post == nil {
+			return "", ErrPostNotFound
+		}
+		if post.UserID != userID {
+			return "", UnauthorizedError
+		}
+		if post.Status != llm.ContentSafeDeny {
+			return "", ErrAppealNotAllowed
+		}
+		return util.TruncateRunes(post.Title+"\n"+post.Content, reportSnapshotLen), nil
+
+	case model.AppealTargetComment:
+		comment, err := s.postActionRepo.GetCommentByID(ctx, targetID)
+		if err != nil || comment == nil {
+			return "", ErrPostCommentNotFound
+		}
+		if comment.UserID != userID {
+			return "", UnauthorizedError
+		}
+		if comment.Status != llm.ContentSafeDeny {
+			return "", ErrAppealNotAllowed
+		}
+		return util.TruncateRunes(comment.Content, reportSnapshotLen), nil
+	}
+	return "", ErrParamInvalid
+}
+
+func (s *appealServiceImpl) notifyAuthor(ctx context.Context, appeal *model.Appeal, content string) {
+	err := sendSystemNotice(ctx, s.sysBoxRepo, &mongo.SysBoxModel{
+		ReceiverID: appeal.UserID,
+		Type:       mongo.SysBoxTypeAppealResult,
+		TargetID:   appeal.TargetID,
+		Content:    content,
+		Payload: map[string]any{
+			"appeal_id":   appeal.ID,
+			"target_type": appeal.TargetType,
+			"result":      appeal.Status,
+		},
+	})
+	if err != nil {
+		log.WarnContext(ctx, "send appeal result notice failed", "appealId", appeal.ID, "err", err)
+	}
+}
+
+func toAppealListDTO(list []*model.Appeal, total int64) *dto.AppealListDTO {
+	res := make([]*dto.AppealDTO, 0, len(list))
+	for _, a := range list {
+		d := &dto.AppealDTO{
+			ID:         a.ID,
+			TargetType: a.TargetType,
+			TargetID:   a.TargetID,
+			UserID:     a.UserID,
+			Message:    a.Message,
+			Snapshot:   a.Snapshot,
+			Status:     a.Status,
+			HandleNote: a.HandleNote,
+			CreatedAt:  a.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if a.HandledAt != nil {
+			d.HandledAt = a.HandledAt.UTC().Format(time.RFC3339)
+		}
+		if a.LLMDecision != "" {
+			var decision llm.ModerationDecision
+			if err := json.Unmarshal([]byte(a.LLMDecision), &decision); err == nil {
+				d.LLMDecision = &dto.ModerationDecisionDTO{
+					Status:    decision.Status,
+					Reasons:   decision.Reasons,
+					Tags:      decision.Tags,
+					Summaries: decision.Summaries,
+					AuditedAt: decision.AuditedAt.UTC().Format(time.RFC3339),
+				}
+			}
+		}
+		res = append(res, d)
+	}
+	return &dto.AppealListDTO{List: res, Total: total}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	log "log/slog"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type BlockRuleService interface {
+	GetBlockRules(ctx context.Context, req *dto.BlockRuleQueryDTO) (*dto.BlockRuleListDTO, error)
+	CreateBlockRule(ctx context.Context, adminID uint64, req *dto.BlockRuleSaveDTO) error
+	UpdateBlockRule(ctx context.Context, ruleID uint64, req *dto.BlockRuleSaveDTO) error
+	DeleteBlockRule(ctx context.Context, ruleID uint64) error
+}
+
+type blockRuleServiceImpl struct {
+	blockRuleRepo repository.BlockRuleRepo
+}
+
+func NewBlockRuleService(blockRuleRepo repository.BlockRuleRepo) BlockRuleService {
+	return &blockRuleServiceImpl{
+		blockRuleRepo: blockRuleRepo,
+	}
+}
+
+// GetBlockRules 管理端分页获取屏蔽规则
+func (s *blockRuleServiceImpl) GetBlockRules(ctx context.Context, req *dto.BlockRuleQueryDTO) (*dto.BlockRuleListDTO, error) {
+	list, total, err := s.blockRuleRepo.GetBlockRules(ctx, req.MatchType, strings.TrimSpace(req.Keyword), req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.BlockRuleDTO, 0, len(list))
+	for _, r := range list {
+		res = append(res, &dto.BlockRuleDTO{
+			ID:         r.ID,
+			Pattern:    r.Pattern,
+			MatchType:  r.MatchType,
+			Action:     r.Action,
+			FoldPinyin: r.FoldPinyin,
+			Enabled:    r.Enabled,
+			Remark:     r.Remark,
+			CreatedBy:  r.CreatedBy,
+			UpdatedAt:  r.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return &dto.BlockRuleListDTO{List: res, Total: total}, nil
+}
+
+// CreateBlockRule 新增屏蔽规则并通知热加载
+func (s *blockRuleServiceImpl) CreateBlockRule(ctx context.Context, adminID uint64, req *dto.BlockRuleSaveDTO) error {
+	rule, err := toBlockRule(req)
+	if err != nil {
+		return err
+	}
+	rule.CreatedBy = adminID
+
+	if err = s.blockRuleRepo.CreateBlockRule(ctx, rule); err != nil {
+		if isDuplicateError(err) {
+			return ErrBlockRuleDuplicate
+		}
+		return err
+	}
+	notifyBlocklistReload(ctx)
+	return nil
+}
+
+// UpdateBlockRule 修改屏蔽规则并通知热加载
+func (s *blockRuleServiceImpl) UpdateBlockRule(ctx context.Context, ruleID uint64, req *dto.BlockRuleSaveDTO) error {
+	old, err := s.blockRuleRepo.GetBlockRule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return ErrBlockRuleNotFound
+	}
+
+	rule, err := toBlockRule(req)
+	if err != nil {
+		return err
+	}
+	rule.ID = ruleID
+
+	if err = s.blockRuleRepo.UpdateBlockRule(ctx, rule); err != nil {
+		if isDuplicateError(err) {
+			return ErrBlockRuleDuplicate
+		}
+		return err
+	}
+	notifyBlocklistReload(ctx)
+	return nil
+}
+
+// DeleteBlockRule 删除屏蔽规则并通知热加载
+func (s *blockRuleServiceImpl) DeleteBlockRule(ctx context.Context, ruleID uint64) error {
+	ok, err := s.blockRuleRepo.DeleteBlockRule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrBlockRuleNotFound
+	}
+	notifyBlocklistReload(ctx)
+	return nil
+}
+
+// toBlockRule 校验规则，关键词归一化后不能为空，正则必须可编译
+func toBlockRule(req *dto.BlockRuleSaveDTO) (*model.BlockRule, error) {
+	pattern := strings.TrimSpace(req.Pattern)
+	switch req.MatchType {
+	case model.BlockMatchWord:
+		if util.FoldForMatch(pattern) == "" {
+			return nil, ErrBlockRuleInvalid
+		}
+	case model.BlockMatchRegex:
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, ErrBlockRuleInvalid
+		}
+	default:
+		return nil, ErrBlockRuleInvalid
+	}
+
+	return &model.BlockRule{
+		Pattern:    pattern,
+		MatchType:  req.MatchType,
+		Action:     req.Action,
+		FoldPinyin: req.FoldPinyin && req.MatchType == model.BlockMatchWord,
+		Enabled:    *req.Enabled,
+		Remark:     strings.TrimSpace(req.Remark),
+	}, nil
+}
+
+func notifyBlocklistReload(ctx context.Context) {
+	if err := redis.Publish(ctx, consts.BlocklistReloadChannel, time.Now().Unix()); err != nil {
+		log.WarnContext(ctx, "publish blocklist reload failed", "err", err)
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"Cornerstone/internal/api/config"
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/repository"
+	"context"
+	log "log/slog"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	discussionMinComments      = 30               // 评论数达到该值才生成讨论总结
+	discussionRefreshThreshold = 50               // 评论数较上次生成增长该值后重新生成
+	discussionMinSamples       = 10               // 可用于总结的已通过评论下限
+	discussionCommentLimit     = 200              // 送入模型的热门评论数
+	discussionContentMaxRunes  = 200              // 单条评论送入模型的最大长度
+	discussionMaxViewpoints    = 5                // 最多展示的观点数
+	discussionMaxCommentIDs    = 3                // 每个观点最多展示的代表评论数
+	discussionCooldown         = 10 * time.Minute // 同一笔记两次生成的最小间隔，模型失败时同样生效
+	discussionGenerateTimeout  = 2 * time.Minute
+	discussionCacheTTL         = 24 * time.Hour
+)
+
+type DiscussionSummaryService interface {
+	GetSummary(ctx context.Context, postID uint64) *dto.DiscussionSummaryDTO
+	RefreshSummary(ctx context.Context, postID uint64) error
+}
+
+type discussionSummaryServiceImpl struct {
+	summaryRepo repository.PostDiscussionSummaryRepo
+	actionRepo  repository.PostActionRepo
+	controlRepo repository.PostCommentControlRepo
+	postRepo    repository.PostRepo
+	actionSvc   PostActionService
+}
+
+func NewDiscussionSummaryService(
+	summaryRepo repository.PostDiscussionSummaryRepo,
+	actionRepo repository.PostActionRepo,
+	controlRepo repository.PostCommentControlRepo,
+	postRepo repository.PostRepo,
+	actionSvc PostActionService,
+) DiscussionSummaryService {
+	return &discussionSummaryServiceImpl{
+		summaryRepo: summaryRepo,
+		actionRepo:  actionRepo,
+		controlRepo: controlRepo,
+		postRepo:    postRepo,
+		actionSvc:   actionSvc,
+	}
+}
+
+// GetSummary 获取笔记的讨论总结，缺失或评论数增长超过阈值时异步重新生成，读取失败时不返回总结
+func (s *discussionSummaryServiceImpl) GetSummary(ctx context.Context, postID uint64) *dto.DiscussionSummaryDTO {
+	count, err := s.actionSvc.GetPostCommentCount(ctx, postID)
+	if err != nil || count < discussionMinComments {
+		return nil
+	}
+
+	summary, err := s.loadSummary(ctx, postID)
+	if err != nil {
+		log.WarnContext(ctx, "load discussion summary failed", "postID", postID, "err", err)
+		return nil
+	}
+	if summary == nil || count-summary.CommentCount >= discussionRefreshThreshold {
+		s.triggerRefresh(ctx, postID)
+	}
+	return summary
+}
+
+// RefreshSummary 用热门的已通过评论重新生成讨论总结
+func (s *discussionSummaryServiceImpl) RefreshSummary(ctx context.Context, postID uint64) error {
+	post, err := s.postRepo.GetPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return ErrPostNotFound
+	}
+	count, err := s.actionSvc.GetPostCommentCount(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	// 影子封禁用户与作者隐藏的评论不参与总结
+	exclude := &repository.CommentExclusion{UserIDs: getShadowBannedUsers(ctx, 0)}
+	marks, err := s.controlRepo.GetMarks(ctx, postID)
+	if err != nil {
+		return err
+	}
+	for _, m := range marks {
+		if m.Kind == model.CommentMarkHidden {
+			exclude.CommentIDs = append(exclude.CommentIDs, m.CommentID)
+		}
+	}
+
+	comments, err := s.actionRepo.GetTopApprovedComments(ctx, postID, exclude, discussionCommentLimit)
+	if err != nil {
+		return err
+	}
+	if len(comments) < discussionMinSamples {
+		return nil
+	}
+
+	input := &llm.DiscussionInput{
+		Title:    post.Title,
+		Comments: make([]*llm.DiscussionComment, 0, len(comments)),
+	}
+	for _, c := range comments {
+		content := []rune(c.Content)
+		if len(content) > discussionContentMaxRunes {
+			content = content[:discussionContentMaxRunes]
+		}
+		input.Comments = append(input.Comments, &llm.DiscussionComment{
+			ID:      c.ID,
+			Content: string(content),
+			Likes:   c.LikesCount,
+			IsReply: c.RootID != 0,
+		})
+	}
+
+	result, err := llm.SummarizeDiscussion(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	viewpoints := make(model.DiscussionViewpoints, 0, len(result.Viewpoints))
+	for _, v := range result.Viewpoints {
+		if len(viewpoints) == discussionMaxViewpoints {
+			break
+		}
+		ids := v.CommentIDs
+		if len(ids) > discussionMaxCommentIDs {
+			ids = ids[:discussionMaxCommentIDs]
+		}
+		viewpoints = append(viewpoints, &model.DiscussionViewpoint{Viewpoint: v.Viewpoint, CommentIDs: ids})
+	}
+
+	now := time.Now()
+	summary := &model.PostDiscussionSummary{
+		PostID:       postID,
+		Overview:     result.Overview,
+		Viewpoints:   viewpoints,
+		CommentCount: count,
+		Model:        config.Cfg.LLM.TextModel,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err = s.summaryRepo.SaveSummary(ctx, summary); err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "discussion summary refreshed", "postID", postID, "comments", len(comments), "viewpoints", len(viewpoints))
+	s.cacheSummary(ctx, postID, toDiscussionSummaryDTO(summary))
+	return nil
+}
+
+// loadSummary 优先读取缓存，缓存缺失时回源数据库并回填
+func (s *discussionSummaryServiceImpl) loadSummary(ctx context.Context, postID uint64) (*dto.DiscussionSummaryDTO, error) {
+	key := consts.PostDiscussionSummaryKey + strconv.FormatUint(postID, 10)
+	if val, err := redis.GetValue(ctx, key); err == nil && val != "" {
+		var cached dto.DiscussionSummaryDTO
+		if err = json.Unmarshal([]byte(val), &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	summary, err := s.summaryRepo.GetSummary(ctx, postID)
+	if err != nil || summary == nil {
+		return nil, err
+	}
+	res := toDiscussionSummaryDTO(summary)
+	s.cacheSummary(ctx, postID, res)
+	return res, nil
+}
+
+func (s *discussionSummaryServiceImpl) cacheSummary(ctx context.Context, postID uint64, summary *dto.DiscussionSummaryDTO) {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return
+	}
+	key := consts.PostDiscussionSummaryKey + strconv.FormatUint(postID, 10)
+	_ = redis.SetWithExpiration(ctx, key, data, discussionCacheTTL)
+}
+
+// triggerRefresh 异步重新生成总结，生成锁到期前同一笔记不会重复请求模型
+func (s *discussionSummaryServiceImpl) triggerRefresh(ctx context.Context, postID uint64) {
+	lockKey := consts.PostDiscussionLockKey + strconv.FormatUint(postID, 10)
+	ok, err := redis.TryLock(ctx, lockKey, time.Now().Unix(), discussionCooldown, 0)
+	if err != nil || !ok {
+		return
+	}
+
+	go func() {
+		bgCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), discussionGenerateTimeout)
+		defer cancel()
+		if err := s.RefreshSummary(bgCtx, postID); err != nil {
+			log.WarnContext(bgCtx, "refresh discussion summary failed", "postID", postID, "err", err)
+		}
+	}()
+}
+
+func toDiscussionSummaryDTO(summary *model.PostDiscussionSummary) *dto.DiscussionSummaryDTO {
+	res := &dto.DiscussionSummaryDTO{
+		Overview:     summary.Overview,
+		Viewpoints:   make([]*dto.DiscussionViewpointDTO, 0, len(summary.Viewpoints)),
+		CommentCount: summary.CommentCount,
+		UpdatedAt:    summary.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, v := range summary.Viewpoints {
+		res.Viewpoints = append(res.Viewpoints, &dto.DiscussionViewpointDTO{
+			Viewpoint:  v.Viewpoint,
+			CommentIDs: v.CommentIDs,
+		})
+	}
+	return res
+}
@@ -37,6 +37,39 @@ var (
 	ErrSysBoxNotFound          = errors.New("系统通知不存在")
 	ErrTargetUserInvalid       = errors.New("目标用户无效")
 	ErrConversation            = errors.New("会话异常")
+	ErrOnboardingTagInvalid    = errors.New("兴趣标签无效")
+	ErrReindexRunning          = errors.New("索引重建任务正在进行")
+	ErrReindexLockLost         = errors.New("索引重建任务锁已失效")
+	ErrReconcileRunning        = errors.New("索引对账任务正在进行")
+	ErrReportSelf              = errors.New("不能举报自己")
+	ErrReportCaseNotFound      = errors.New("举报工单不存在")
+	ErrReportCaseClaimed       = errors.New("举报工单已被他人认领")
+	ErrReportCaseNotClaimed    = errors.New("请先认领举报工单")
+	ErrAppealNotAllowed        = errors.New("内容未被拒绝，无需申诉")
+	ErrAppealPending           = errors.New("申诉正在处理中")
+	ErrAppealLimit             = errors.New("申诉次数已达上限")
+	ErrAppealNotFound          = errors.New("申诉不存在")
+	ErrAppealHandled           = errors.New("申诉已被处理")
+	ErrBlockRuleNotFound       = errors.New("屏蔽规则不存在")
+	ErrBlockRuleInvalid        = errors.New("屏蔽规则无效")
+	ErrBlockRuleDuplicate      = errors.New("屏蔽规则已存在")
+	ErrMediaBlocked            = errors.New("图片涉嫌违规，无法上传")
+	ErrMediaHashNotFound       = errors.New("无法获取该媒体的图片指纹")
+	ErrMediaHashBlockNotFound  = errors.New("图片指纹不存在")
+	ErrReauditInvalid          = errors.New("复审条件无效")
+	ErrReauditNotFound         = errors.New("复审任务不存在")
+	ErrReauditStateInvalid     = errors.New("复审任务当前状态不允许该操作")
+	ErrSoftRestrictionNotFound = errors.New("软处置不存在或已失效")
+	ErrSpamRiskNotFound        = errors.New("风险记录不存在或已复核")
+	ErrCommentDisabled         = errors.New("作者已关闭评论")
+	ErrCommentFollowersOnly    = errors.New("作者仅允许粉丝评论")
+	ErrCommentPinInvalid       = errors.New("仅可置顶未隐藏的一级评论")
+	ErrCommentPinLimit         = errors.New("置顶评论数量已达上限")
+	ErrCommentEditExpired      = errors.New("评论发布超过30分钟，无法编辑")
+	ErrCommentEditPending      = errors.New("评论审核中，请稍后再编辑")
+	ErrCommentEditNotAllowed   = errors.New("评论当前状态不可编辑")
+	ErrReactionInvalid         = errors.New("不支持的表情回应")
+	ErrMessageNotFound         = errors.New("消息不存在")
 	UnauthorizedError          = errors.New("权限不足")
 	UnExpectedError            = errors.New("系统异常，请稍后重试")
 )
@@ -67,6 +100,39 @@ var ErrorMap = map[error]int{
 	ErrSysBoxNotFound:          NotFound,
 	ErrTargetUserInvalid:       BadRequest,
 	ErrConversation:            BadRequest,
+	ErrOnboardingTagInvalid:    BadRequest,
+	ErrReindexRunning:          BadRequest,
+	ErrReindexLockLost:         InternalServerError,
+	ErrReconcileRunning:        BadRequest,
+	ErrReportSelf:              BadRequest,
+	ErrReportCaseNotFound:      NotFound,
+	ErrReportCaseClaimed:       BadRequest,
+	ErrReportCaseNotClaimed:    BadRequest,
+	ErrAppealNotAllowed:        BadRequest,
+	ErrAppealPending:           BadRequest,
+	ErrAppealLimit:             BadRequest,
+	ErrAppealNotFound:          NotFound,
+	ErrAppealHandled:           BadRequest,
+	ErrBlockRuleNotFound:       NotFound,
+	ErrBlockRuleInvalid:        BadRequest,
+	ErrBlockRuleDuplicate:      BadRequest,
+	ErrMediaBlocked:            BadRequest,
+	ErrMediaHashNotFound:       BadRequest,
+	ErrMediaHashBlockNotFound:  NotFound,
+	ErrReauditInvalid:          BadRequest,
+	ErrReauditNotFound:         NotFound,
+	ErrReauditStateInvalid:     BadRequest,
+	ErrSoftRestrictionNotFound: NotFound,
+	ErrSpamRiskNotFound:        NotFound,
+	ErrCommentDisabled:         Unauthorized,
+	ErrCommentFollowersOnly:    Unauthorized,
+	ErrCommentPinInvalid:       BadRequest,
+	ErrCommentPinLimit:         BadRequest,
+	ErrCommentEditExpired:      BadRequest,
+	ErrCommentEditPending:      BadRequest,
+	ErrCommentEditNotAllowed:   BadRequest,
+	ErrReactionInvalid:         BadRequest,
+	ErrMessageNotFound:         NotFound,
 	UnauthorizedError:          Unauthorized,
 	UnExpectedError:            InternalServerError,
 }
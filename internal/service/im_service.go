@@ -26,6 +26,7 @@ type IMService interface {
 	SyncMessages(ctx context.Context, userID uint64, convID uint64, lastSeq uint64, pageSize int) ([]*dto.MessageDTO, error)
 	GetConversationList(ctx context.Context, userID uint64) ([]*dto.ConversationDTO, error)
 	MarkAsRead(ctx context.Context, userID uint64, convID uint64, seq uint64) error
+	ReactToMessage(ctx context.Context, userID uint64, req *dto.MessageReactionReq) error
 	Close()
 }
 
@@ -333,6 +334,51 @@ func (s *imServiceImpl) MarkAsRead(ctx context.Context, userID uint64, convID ui
 	return nil
 }
 
+// ReactToMessage 对会话中的消息做出或取消表情回应，并推送给对方
+func (s *imServiceImpl) ReactToMessage(ctx context.Context, userID uint64, req *dto.MessageReactionReq) error {
+	emoji := ""
+	if req.Action == 1 {
+		if !isValidReaction(req.Emoji) {
+			return ErrReactionInvalid
+		}
+		emoji = req.Emoji
+	}
+
+	isMember, err := s.convRepo.IsMember(ctx, req.ConversationID, userID)
+	if err != nil || !isMember {
+		return UnauthorizedError
+	}
+	conv, err := s.convRepo.GetConversation(ctx, req.ConversationID)
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	if emoji != "" {
+		found, err = s.messageRepo.SetReaction(ctx, req.ConversationID, req.Seq, userID, emoji)
+	} else {
+		found, err = s.messageRepo.RemoveReaction(ctx, req.ConversationID, req.Seq, userID)
+	}
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrMessageNotFound
+	}
+
+	peerID, err := s.parsePeerID(conv.PeerKey, userID)
+	if err != nil {
+		return err
+	}
+	go func() {
+		err := s.publishMessageReaction(req.ConversationID, req.Seq, userID, peerID, emoji)
+		if err != nil {
+			log.Error("Failed to publish message reaction", "err", err)
+		}
+	}()
+	return nil
+}
+
 // publishMessageToRedis 发布消息到接收者的用户频道
 func (s *imServiceImpl) publishMessageToRedis(ctx context.Context, msg *mongo.Message, targetUserID uint64) error {
 	data, err := json.Marshal(s.toMessageDTO(msg))
@@ -362,6 +408,26 @@ func (s *imServiceImpl) publishReadReceipt(convID, fromUID, toPeerID, seq uint64
 	return redis.Publish(ctx, channel, data)
 }
 
+// publishMessageReaction 发布消息表情回应到对方频道
+func (s *imServiceImpl) publishMessageReaction(convID, seq, fromUID, toPeerID uint64, emoji string) error {
+	reaction := &dto.MessageReactionDTO{
+		ConversationID: convID,
+		Seq:            seq,
+		UserID:         fromUID,
+		Emoji:          emoji,
+		Type:           "MESSAGE_REACTION",
+	}
+	data, err := json.Marshal(reaction)
+	if err != nil {
+		return err
+	}
+	channel := consts.IMUserKey + strconv.FormatUint(toPeerID, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return redis.Publish(ctx, channel, data)
+}
+
 func (s *imServiceImpl) Close() {
 	close(s.stopChan)
 	s.wg.Wait()
@@ -427,6 +493,7 @@ func (s *imServiceImpl) toMessageDTO(m *mongo.Message) *dto.MessageDTO {
 		Content:        m.Content,
 		Payload:        dtoPayload,
 		Seq:            m.Seq,
+		Reactions:      m.Reactions,
 		CreatedAt:      m.CreatedAt.UTC(),
 	}
 }
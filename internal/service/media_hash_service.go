@@ -0,0 +1,321 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/minio"
+	"Cornerstone/internal/pkg/processor"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	"fmt"
+	"io"
+	log "log/slog"
+	"strings"
+	"time"
+)
+
+type MediaHashService interface {
+	CheckUpload(ctx context.Context, reader io.ReadSeeker) (*util.ImageHash, error)
+	SaveUploadHash(ctx context.Context, fileKey string, hash *util.ImageHash)
+	GetHashBlocks(ctx context.Context, req *dto.MediaHashBlockQueryDTO) (*dto.MediaHashBlockListDTO, error)
+	AddHashBlock(ctx context.Context, auditorID uint64, req *dto.MediaHashBlockCreateDTO) error
+	DeleteHashBlock(ctx context.Context, blockID uint64) error
+	BlockConfirmedMedia(ctx context.Context, auditorID uint64, targetType int8, targetID uint64, mediaURLs []string)
+}
+
+// verdictLookupLimit 判定违规媒体时读取的审核记录数
+const verdictLookupLimit = 100
+
+type mediaHashServiceImpl struct {
+	mediaHashRepo  repository.MediaHashRepo
+	postRepo       repository.PostRepo
+	postActionRepo repository.PostActionRepo
+	moderationRepo repository.ModerationRepo
+	blocklist      *processor.MediaHashBlocklist
+}
+
+func NewMediaHashService(
+	mediaHashRepo repository.MediaHashRepo,
+	postRepo repository.PostRepo,
+	postActionRepo repository.PostActionRepo,
+	moderationRepo repository.ModerationRepo,
+	blocklist *processor.MediaHashBlocklist,
+) MediaHashService {
+	return &mediaHashServiceImpl{
+		mediaHashRepo:  mediaHashRepo,
+		postRepo:       postRepo,
+		postActionRepo: postActionRepo,
+		moderationRepo: moderationRepo,
+		blocklist:      blocklist,
+	}
+}
+
+// CheckUpload 计算上传图片的感知哈希并匹配指纹库，无法解码的格式跳过校验
+func (s *mediaHashServiceImpl) CheckUpload(ctx context.Context, reader io.ReadSeeker) (*util.ImageHash, error) {
+	hash, err := util.ComputeImageHash(reader)
+	if err != nil {
+		log.WarnContext(ctx, "compute upload image hash failed", "err", err)
+		return nil, nil
+	}
+	if hit := s.blocklist.Match(hash); hit != nil {
+		log.WarnContext(ctx, "upload image hit media hash blocklist", "blockId", hit.BlockID, "pDistance", hit.PDistance, "dDistance", hit.DDistance)
+		return nil, ErrMediaBlocked
+	}
+	return hash, nil
+}
+
+// SaveUploadHash 留存上传图片的哈希，送审与拒绝确认时使用
+func (s *mediaHashServiceImpl) SaveUploadHash(ctx context.Context, fileKey string, hash *util.ImageHash) {
+	if hash == nil {
+		return
+	}
+	err := s.mediaHashRepo.SaveMediaHashes(ctx, []*model.MediaHash{{
+		MediaURL: fileKey,
+		PHash:    hash.PHash,
+		DHash:    hash.DHash,
+	}})
+	if err != nil {
+		log.WarnContext(ctx, "save upload image hash failed", "fileKey", fileKey, "err", err)
+	}
+}
+
+// GetHashBlocks 审核端分页获取指纹库
+func (s *mediaHashServiceImpl) GetHashBlocks(ctx context.Context, req *dto.MediaHashBlockQueryDTO) (*dto.MediaHashBlockListDTO, error) {
+	list, total, err := s.mediaHashRepo.GetHashBlocks(ctx, req.Source, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.MediaHashBlockDTO, 0, len(list))
+	for _, b := range list {
+		res = append(res, &dto.MediaHashBlockDTO{
+			ID:         b.ID,
+			PHash:      fmt.Sprintf("%016x", b.PHash),
+			DHash:      fmt.Sprintf("%016x", b.DHash),
+			Source:     b.Source,
+			MediaURL:   b.MediaURL,
+			Frame:      b.Frame,
+			TargetType: b.TargetType,
+			TargetID:   b.TargetID,
+			Remark:     b.Remark,
+			CreatedBy:  b.CreatedBy,
+			CreatedAt:  b.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return &dto.MediaHashBlockListDTO{List: res, Total: total}, nil
+}
+
+// AddHashBlock 审核员将指定媒体加入指纹库
+func (s *mediaHashServiceImpl) AddHashBlock(ctx context.Context, auditorID uint64, req *dto.MediaHashBlockCreateDTO) error {
+	mediaURL := strings.TrimSpace(req.MediaURL)
+	hashes, err := s.collectHashes(ctx, []string{mediaURL}, []string{mediaURL})
+	if err != nil {
+		return err
+	}
+	if len(hashes) == 0 {
+		return ErrMediaHashNotFound
+	}
+
+	blocks := make([]*model.MediaHashBlock, 0, len(hashes))
+	for _, h := range hashes {
+		blocks = append(blocks, &model.MediaHashBlock{
+			PHash:     h.PHash,
+			DHash:     h.DHash,
+			Source:    model.MediaHashSourceManual,
+			MediaURL:  h.MediaURL,
+			Frame:     h.Frame,
+			Remark:    strings.TrimSpace(req.Remark),
+			CreatedBy: auditorID,
+		})
+	}
+	if _, err = s.mediaHashRepo.CreateHashBlocks(ctx, blocks); err != nil {
+		return err
+	}
+	notifyMediaHashReload(ctx)
+	return nil
+}
+
+// DeleteHashBlock 将指纹移出指纹库
+func (s *mediaHashServiceImpl) DeleteHashBlock(ctx context.Context, blockID uint64) error {
+	ok, err := s.mediaHashRepo.DeleteHashBlock(ctx, blockID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrMediaHashBlockNotFound
+	}
+	notifyMediaHashReload(ctx)
+	return nil
+}
+
+// BlockConfirmedMedia 拒绝经人工确认后，异步将违规媒体加入指纹库：审核员指定了媒体时只处理指定媒体，
+// 否则只处理模型审核或指纹库判定为违规的单个媒体，因文字、屏蔽词或举报被拒绝的内容不影响其图片
+func (s *mediaHashServiceImpl) BlockConfirmedMedia(ctx context.Context, auditorID uint64, targetType int8, targetID uint64, mediaURLs []string) {
+	go func() {
+		bgCtx := context.WithoutCancel(ctx)
+		if err := s.blockTargetMedia(bgCtx, auditorID, targetType, targetID, mediaURLs); err != nil {
+			log.WarnContext(bgCtx, "block confirmed media failed", "targetType", targetType, "targetId", targetID, "err", err)
+		}
+	}()
+}
+
+func (s *mediaHashServiceImpl) blockTargetMedia(ctx context.Context, auditorID uint64, targetType int8, targetID uint64, mediaURLs []string) error {
+	flagged := make(map[string]bool, len(mediaURLs))
+	for _, u := range mediaURLs {
+		flagged[u] = true
+	}
+	if len(flagged) == 0 {
+		denied, err := s.deniedMedia(ctx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		flagged = denied
+	}
+	if len(flagged) == 0 {
+		return nil
+	}
+
+	var mediaList model.MediaList
+	switch targetType {
+	case model.ModerationTargetPost:
+		post, err := s.postRepo.GetPostByAllStatus(ctx, targetID)
+		if err != nil || post == nil {
+			return err
+		}
+		mediaList = post.MediaList
+	case model.ModerationTargetComment:
+		comment, err := s.postActionRepo.GetCommentByID(ctx, targetID)
+		if err != nil || comment == nil {
+			return err
+		}
+		mediaList = comment.MediaInfo
+	default:
+		return nil
+	}
+
+	var urls, images []string
+	for _, m := range mediaList {
+		if flagged[m.MediaURL] {
+			urls = append(urls, m.MediaURL)
+			if strings.HasPrefix(m.MimeType, consts.MimePrefixImage) {
+				images = append(images, m.MediaURL)
+			}
+		}
+		if m.CoverURL != nil && *m.CoverURL != "" && flagged[*m.CoverURL] {
+			urls = append(urls, *m.CoverURL)
+			images = append(images, *m.CoverURL)
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	hashes, err := s.collectHashes(ctx, urls, images)
+	if err != nil {
+		return err
+	}
+	blocks := make([]*model.MediaHashBlock, 0, len(hashes))
+	for _, h := range hashes {
+		blocks = append(blocks, &model.MediaHashBlock{
+			PHash:      h.PHash,
+			DHash:      h.DHash,
+			Source:     model.MediaHashSourceConfirm,
+			MediaURL:   h.MediaURL,
+			Frame:      h.Frame,
+			TargetType: targetType,
+			TargetID:   targetID,
+			CreatedBy:  auditorID,
+		})
+	}
+	added, err := s.mediaHashRepo.CreateHashBlocks(ctx, blocks)
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		notifyMediaHashReload(ctx)
+	}
+	return nil
+}
+
+// deniedMedia 从审核记录中找出被单独判定违规的媒体，视频抽帧归到所属视频；
+// 一批多张图片的拒绝结论无法定位到具体图片，不作为依据
+func (s *mediaHashServiceImpl) deniedMedia(ctx context.Context, targetType int8, targetID uint64) (map[string]bool, error) {
+	verdicts, err := s.moderationRepo.GetVerdicts(ctx, targetType, targetID, verdictLookupLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]bool)
+	for _, v := range verdicts {
+		if v.Status != llm.ContentSafeDeny {
+			continue
+		}
+		switch v.Source {
+		case processor.VerdictSourceImageBatch, processor.VerdictSourceVideoFrame, processor.VerdictSourceMediaHash:
+		default:
+			continue
+		}
+		urls := make(map[string]struct{}, len(v.Refs))
+		for _, ref := range v.Refs {
+			url, _, _ := strings.Cut(ref, "#")
+			urls[url] = struct{}{}
+		}
+		if len(urls) != 1 {
+			continue
+		}
+		for url := range urls {
+			res[url] = true
+		}
+	}
+	return res, nil
+}
+
+// collectHashes 获取媒体已留存的哈希，图片缺失时从 MinIO 读取后补算
+func (s *mediaHashServiceImpl) collectHashes(ctx context.Context, urls, images []string) ([]*model.MediaHash, error) {
+	hashes, err := s.mediaHashRepo.GetMediaHashes(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]bool, len(hashes))
+	for _, h := range hashes {
+		stored[h.MediaURL] = true
+	}
+
+	var computed []*model.MediaHash
+	for _, url := range images {
+		if stored[url] {
+			continue
+		}
+		hash, err := computeStoredImageHash(ctx, url)
+		if err != nil {
+			log.WarnContext(ctx, "compute stored image hash failed", "url", url, "err", err)
+			continue
+		}
+		stored[url] = true
+		computed = append(computed, &model.MediaHash{MediaURL: url, PHash: hash.PHash, DHash: hash.DHash})
+	}
+	if err = s.mediaHashRepo.SaveMediaHashes(ctx, computed); err != nil {
+		log.WarnContext(ctx, "save computed image hashes failed", "err", err)
+	}
+	return append(hashes, computed...), nil
+}
+
+func computeStoredImageHash(ctx context.Context, objectName string) (*util.ImageHash, error) {
+	obj, err := minio.GetFile(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = obj.Close() }()
+	return util.ComputeImageHash(obj)
+}
+
+func notifyMediaHashReload(ctx context.Context) {
+	if err := redis.Publish(ctx, consts.MediaHashReloadChannel, time.Now().Unix()); err != nil {
+		log.WarnContext(ctx, "publish media hash reload failed", "err", err)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/repository"
+	"context"
+	"time"
+)
+
+// moderationVerdictLimit 单个内容最多返回的审核子项数
+const moderationVerdictLimit = 100
+
+type ModerationService interface {
+	GetVerdicts(ctx context.Context, req *dto.ModerationVerdictQueryDTO) ([]*dto.ModerationAuditDTO, error)
+}
+
+type moderationServiceImpl struct {
+	moderationRepo repository.ModerationRepo
+}
+
+func NewModerationService(moderationRepo repository.ModerationRepo) ModerationService {
+	return &moderationServiceImpl{
+		moderationRepo: moderationRepo,
+	}
+}
+
+// GetVerdicts 获取内容的模型审核记录，按审核批次分组，最近一次在前
+func (s *moderationServiceImpl) GetVerdicts(ctx context.Context, req *dto.ModerationVerdictQueryDTO) ([]*dto.ModerationAuditDTO, error) {
+	list, err := s.moderationRepo.GetVerdicts(ctx, req.TargetType, req.TargetID, moderationVerdictLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.ModerationAuditDTO, 0)
+	audits := make(map[string]*dto.ModerationAuditDTO)
+	for _, v := range list {
+		audit, ok := audits[v.AuditID]
+		if !ok {
+			audit = &dto.ModerationAuditDTO{
+				AuditID:   v.AuditID,
+				CreatedAt: v.CreatedAt.UTC().Format(time.RFC3339),
+				Verdicts:  make([]*dto.ModerationVerdictDTO, 0),
+			}
+			audits[v.AuditID] = audit
+			res = append(res, audit)
+		}
+		if v.Status > audit.Status {
+			audit.Status = v.Status
+		}
+		audit.Verdicts = append(audit.Verdicts, &dto.ModerationVerdictDTO{
+			Source:        v.Source,
+			Refs:          v.Refs,
+			Input:         v.Input,
+			Status:        v.Status,
+			Reason:        v.Reason,
+			Confidence:    v.Confidence,
+			Model:         v.Model,
+			PromptVersion: v.PromptVersion,
+			LatencyMs:     v.LatencyMs,
+			Raw:           v.Raw,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/minio"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/repository"
+	"context"
+	log "log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	// onboardingFallbackSize 未配置引导标签时，按热度补齐的数量
+	onboardingFallbackSize = 12
+	// onboardingCoverSize 每个标签展示的示例封面数量
+	onboardingCoverSize = 3
+)
+
+type OnboardingService interface {
+	GetOnboardingTags(ctx context.Context) ([]*dto.OnboardingTagDTO, error)
+	GetOnboardingStatus(ctx context.Context, userID uint64) (*dto.OnboardingStatusDTO, error)
+	SubmitInterests(ctx context.Context, userID uint64, tagIDs []uint64) error
+
+	GetOnboardingTagsAdmin(ctx context.Context) ([]*dto.OnboardingTagAdminDTO, error)
+	SaveOnboardingTag(ctx context.Context, req *dto.OnboardingTagSaveDTO) error
+	DeleteOnboardingTag(ctx context.Context, tagID uint64) error
+}
+
+type onboardingServiceImpl struct {
+	tagRepo          repository.TagRepo
+	userInterestRepo repository.UserInterestRepo
+	postESRepo       es.PostRepo
+}
+
+func NewOnboardingService(tagRepo repository.TagRepo, userInterestRepo repository.UserInterestRepo, postESRepo es.PostRepo) OnboardingService {
+	return &onboardingServiceImpl{
+		tagRepo:          tagRepo,
+		userInterestRepo: userInterestRepo,
+		postESRepo:       postESRepo,
+	}
+}
+
+// GetOnboardingTags 获取兴趣引导标签及示例封面
+func (s *onboardingServiceImpl) GetOnboardingTags(ctx context.Context) ([]*dto.OnboardingTagDTO, error) {
+	if val, err := redis.GetValue(ctx, consts.OnboardingTagsKey); err == nil && val != "" {
+		var res []*dto.OnboardingTagDTO
+		if err = json.Unmarshal([]byte(val), &res); err == nil {
+			return res, nil
+		}
+	}
+
+	tags, err := s.getCandidateTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := shadowExclusion(ctx, 0)
+	res := make([]*dto.OnboardingTagDTO, 0, len(tags))
+	for _, tag := range tags {
+		item := &dto.OnboardingTagDTO{
+			TagID:  tag.ID,
+			Name:   tag.Name,
+			Covers: make([]string, 0, onboardingCoverSize),
+		}
+		if tag.Description != nil {
+			item.Description = *tag.Description
+		}
+
+		posts, err := s.postESRepo.GetPostByTag(ctx, tag.Name, true, 0, onboardingCoverSize, exclude)
+		if err != nil {
+			log.WarnContext(ctx, "get onboarding tag covers failed", "tag", tag.Name, "err", err)
+		}
+		for _, p := range posts {
+			if cover := getPostCover(p); cover != "" {
+				item.Covers = append(item.Covers, cover)
+			}
+		}
+		res = append(res, item)
+	}
+
+	if bs, err := json.Marshal(res); err == nil {
+		_ = redis.SetWithExpiration(ctx, consts.OnboardingTagsKey, string(bs), 10*time.Minute)
+	}
+	return res, nil
+}
+
+// GetOnboardingStatus 没有任何兴趣画像的用户需要展示兴趣引导
+func (s *onboardingServiceImpl) GetOnboardingStatus(ctx context.Context, userID uint64) (*dto.OnboardingStatusDTO, error) {
+	key := consts.UserInterestKey + strconv.FormatUint(userID, 10)
+	if exists, _ := redis.Exists(ctx, key); exists {
+		return &dto.OnboardingStatusDTO{NeedOnboarding: false}, nil
+	}
+
+	snapshot, err := s.userInterestRepo.GetUserInterests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OnboardingStatusDTO{
+		NeedOnboarding: snapshot == nil || len(snapshot.Interests) == 0,
+	}, nil
+}
+
+// SubmitInterests 用户选择的标签同时写入 Redis 兴趣 ZSET 与 MySQL 兴趣快照
+func (s *onboardingServiceImpl) SubmitInterests(ctx context.Context, userID uint64, tagIDs []uint64) error {
+	candidates, err := s.getCandidateTags(ctx)
+	if err != nil {
+		return err
+	}
+	allowed := make(map[uint64]string, len(candidates))
+	for _, t := range candidates {
+		allowed[t.ID] = t.Name
+	}
+
+	names := make([]string, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		name, ok := allowed[id]
+		if !ok {
+			return ErrOnboardingTagInvalid
+		}
+		names = append(names, name)
+	}
+
+	interests := make(model.InterestMap)
+	snapshot, err := s.userInterestRepo.GetUserInterests(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if snapshot != nil {
+		for tag, score := range snapshot.Interests {
+			interests[tag] = score
+		}
+	}
+
+	now := time.Now().Unix()
+	for _, name := range names {
+		interests[name] = now
+	}
+
+	err = s.userInterestRepo.SaveUserInterests(ctx, &model.UserInterestTags{
+		UserID:    userID,
+		Interests: interests,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	key := consts.UserInterestKey + strconv.FormatUint(userID, 10)
+	for tag, score := range interests {
+		_ = redis.ZAdd(ctx, key, float64(score), tag)
+	}
+	_ = redis.ZRemRangeByRank(ctx, key, 0, -101)
+	_ = redis.Expire(ctx, key, 24*time.Hour)
+
+	return nil
+}
+
+// GetOnboardingTagsAdmin 管理端获取全部引导标签
+func (s *onboardingServiceImpl) GetOnboardingTagsAdmin(ctx context.Context) ([]*dto.OnboardingTagAdminDTO, error) {
+	list, err := s.tagRepo.GetOnboardingTags(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.OnboardingTagAdminDTO, 0, len(list))
+	for _, item := range list {
+		d := &dto.OnboardingTagAdminDTO{
+			TagID:     item.TagID,
+			SortOrder: item.SortOrder,
+			Enabled:   item.Enabled,
+		}
+		if item.Tag != nil {
+			d.Name = item.Tag.Name
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+// SaveOnboardingTag 新增或修改引导标签
+func (s *onboardingServiceImpl) SaveOnboardingTag(ctx context.Context, req *dto.OnboardingTagSaveDTO) error {
+	tags, err := s.tagRepo.GetTagsByIDs(ctx, []uint64{req.TagID})
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return ErrOnboardingTagInvalid
+	}
+
+	err = s.tagRepo.SaveOnboardingTag(ctx, &model.OnboardingTag{
+		TagID:     req.TagID,
+		SortOrder: req.SortOrder,
+		Enabled:   *req.Enabled,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = redis.DeleteKey(ctx, consts.OnboardingTagsKey)
+	return nil
+}
+
+// DeleteOnboardingTag 移除引导标签
+func (s *onboardingServiceImpl) DeleteOnboardingTag(ctx context.Context, tagID uint64) error {
+	if err := s.tagRepo.DeleteOnboardingTag(ctx, tagID); err != nil {
+		return err
+	}
+	_ = redis.DeleteKey(ctx, consts.OnboardingTagsKey)
+	return nil
+}
+
+// getCandidateTags 优先使用管理员配置的标签，未配置时按热度补齐
+func (s *onboardingServiceImpl) getCandidateTags(ctx context.Context) ([]*model.Tag, error) {
+	list, err := s.tagRepo.GetOnboardingTags(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*model.Tag, 0, len(list))
+	for _, item := range list {
+		if item.Tag != nil {
+			tags = append(tags, item.Tag)
+		}
+	}
+	if len(tags) > 0 {
+		return tags, nil
+	}
+
+	return s.tagRepo.GetPopularMainTags(ctx, onboardingFallbackSize)
+}
+
+// getPostCover 获取帖子首个媒体的封面，视频取封面图，图片取原图
+func getPostCover(post *es.PostES) string {
+	if len(post.Media) == 0 {
+		return ""
+	}
+	media := post.Media[0]
+	if media.Cover != nil && *media.Cover != "" {
+		return minio.GetPublicURL(*media.Cover)
+	}
+	if strings.HasPrefix(media.Type, consts.MimePrefixImage) {
+		return minio.GetPublicURL(media.URL)
+	}
+	return ""
+}
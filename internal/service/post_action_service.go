@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	log "log/slog"
+	"slices"
 	"strconv"
 	"time"
 
@@ -40,9 +41,19 @@ type PostActionService interface {
 	CreateComment(ctx context.Context, userID uint64, req *dto.CommentCreateDTO) error
 	DeleteComment(ctx context.Context, userID, commentID uint64) error
 	GetPostCommentCount(ctx context.Context, postID uint64) (int64, error)
-	GetCommentsByPostID(ctx context.Context, postID uint64, page, pageSize int) ([]*dto.CommentDTO, error)
+	GetCommentsByPostID(ctx context.Context, postID uint64, req *dto.CommentQueryDTO) (*dto.CommentListDTO, error)
 	GetSubComments(ctx context.Context, rootID uint64, page, pageSize int) ([]*dto.CommentDTO, error)
 
+	GetCommentMode(ctx context.Context, postID uint64) (int8, error)
+	GetCommentSettings(ctx context.Context, userID, postID uint64) (*dto.CommentSettingsDTO, error)
+	UpdateCommentSettings(ctx context.Context, userID, postID uint64, req *dto.CommentSettingsUpdateDTO) error
+	PinComment(ctx context.Context, userID, commentID uint64) error
+	UnpinComment(ctx context.Context, userID, commentID uint64) error
+	HideComment(ctx context.Context, userID, commentID uint64) error
+	UnhideComment(ctx context.Context, userID, commentID uint64) error
+	EditComment(ctx context.Context, userID, commentID uint64, req *dto.CommentEditDTO) error
+	GetCommentEdits(ctx context.Context, commentID uint64) ([]*dto.CommentEditHistoryDTO, error)
+
 	LikeComment(ctx context.Context, userID, commentID uint64) error
 	CancelLikeComment(ctx context.Context, userID, commentID uint64) error
 	GetCommentLikeCount(ctx context.Context, commentID uint64) (int64, error)
@@ -50,14 +61,17 @@ type PostActionService interface {
 
 	TrackPostView(ctx context.Context, userID, postID uint64) error
 	GetPostViewCount(ctx context.Context, postID uint64) (int64, error)
-
-	ReportPost(ctx context.Context, userID, postID uint64) error
 }
 
 type postActionServiceImpl struct {
-	actionRepo repository.PostActionRepo
-	postRepo   repository.PostRepo
-	userRepo   repository.UserRepo
+	actionRepo         repository.PostActionRepo
+	postRepo           repository.PostRepo
+	userRepo           repository.UserRepo
+	recommendEventRepo repository.RecommendEventRepo
+	controlRepo        repository.PostCommentControlRepo
+	editRepo           repository.PostCommentEditRepo
+	userFollowRepo     repository.UserFollowRepo
+	reactionSvc        ReactionService
 }
 
 const cacheExpiration = 7 * 24 * time.Hour
@@ -66,18 +80,32 @@ func NewPostActionService(
 	actionRepo repository.PostActionRepo,
 	postRepo repository.PostRepo,
 	userRepo repository.UserRepo,
+	recommendEventRepo repository.RecommendEventRepo,
+	controlRepo repository.PostCommentControlRepo,
+	editRepo repository.PostCommentEditRepo,
+	userFollowRepo repository.UserFollowRepo,
+	reactionSvc ReactionService,
 ) PostActionService {
 	return &postActionServiceImpl{
-		actionRepo: actionRepo,
-		postRepo:   postRepo,
-		userRepo:   userRepo,
+		actionRepo:         actionRepo,
+		postRepo:           postRepo,
+		userRepo:           userRepo,
+		recommendEventRepo: recommendEventRepo,
+		controlRepo:        controlRepo,
+		editRepo:           editRepo,
+		userFollowRepo:     userFollowRepo,
+		reactionSvc:        reactionSvc,
 	}
 }
 
 func (s *postActionServiceImpl) LikePost(ctx context.Context, userID, postID uint64) error {
-	return s.performAction(s.getPostCheck(ctx, postID), func() error {
+	err := s.performAction(s.getPostCheck(ctx, postID), func() error {
 		return s.actionRepo.CreateLike(ctx, &model.Like{UserID: userID, PostID: postID, CreatedAt: time.Now()})
 	})
+	if err == nil {
+		recordRecommendAction(ctx, s.recommendEventRepo, userID, postID, model.RecommendEventLike, 0)
+	}
+	return err
 }
 
 func (s *postActionServiceImpl) CancelLikePost(ctx context.Context, userID, postID uint64) error {
@@ -123,11 +151,13 @@ func (s *postActionServiceImpl) GetPostLikeStates(ctx context.Context, postIDs [
 		isLikedResults = s.batchCheckPostIsLiked(ctx, userID, postIDs)
 	}
 
+	reactions := s.reactionSvc.GetReactionSummaries(ctx, userID, model.ReactionTargetPost, postIDs)
+
 	var missIDs []uint64
 	var missIndices []int
 
 	for i, id := range postIDs {
-		res[i] = &dto.PostLikeStateDTO{}
+		res[i] = &dto.PostLikeStateDTO{Reactions: reactions[id]}
 
 		if userID > 0 {
 			res[i].IsLiked = isLikedResults[i]
@@ -206,6 +236,9 @@ func (s *postActionServiceImpl) CreateComment(ctx context.Context, userID uint64
 	if err != nil || post == nil {
 		return ErrPostNotFound
 	}
+	if err = s.checkCommentPermission(ctx, userID, post); err != nil {
+		return err
+	}
 
 	var hdelKeys []string
 
@@ -227,6 +260,14 @@ func (s *postActionServiceImpl) CreateComment(ctx context.Context, userID uint64
 		if parent.PostID != req.PostID {
 			return ErrPostCommentNotFound
 		}
+		// 被作者隐藏的评论对他人不可见，也不能被回复
+		hidden, err := s.isCommentHiddenFrom(ctx, userID, parent.ID, parent.RootID)
+		if err != nil {
+			return err
+		}
+		if hidden {
+			return ErrPostCommentNotFound
+		}
 
 		finalParentID = parent.ID
 		replyUserID = parent.UserID
@@ -278,13 +319,23 @@ func (s *postActionServiceImpl) DeleteComment(ctx context.Context, userID, comme
 		return ErrPostCommentNotFound
 	}
 
+	// 笔记作者可以删除自己笔记下他人的评论
 	if comment.UserID != userID {
-		return UnauthorizedError
+		post, err := s.postRepo.GetPostByAllStatus(ctx, comment.PostID)
+		if err != nil {
+			return err
+		}
+		if post == nil || post.UserID != userID {
+			return UnauthorizedError
+		}
 	}
 
 	if err = s.actionRepo.DeleteComment(ctx, commentID); err != nil {
 		return err
 	}
+	if err = s.controlRepo.DeleteCommentMarks(ctx, commentID); err != nil {
+		log.WarnContext(ctx, "delete comment marks failed", "commentID", commentID, "err", err)
+	}
 
 	if len(comment.MediaInfo) > 0 {
 		go func() {
@@ -313,46 +364,101 @@ func (s *postActionServiceImpl) GetPostCommentCount(ctx context.Context, postID
 	return realCount, nil
 }
 
-func (s *postActionServiceImpl) GetCommentsByPostID(ctx context.Context, postID uint64, page, pageSize int) ([]*dto.CommentDTO, error) {
-	rootComments, err := s.actionRepo.GetRootCommentsByPostID(ctx, postID, pageSize, (page-1)*pageSize)
+func (s *postActionServiceImpl) GetCommentsByPostID(ctx context.Context, postID uint64, req *dto.CommentQueryDTO) (*dto.CommentListDTO, error) {
+	currentUserID, _ := ctx.Value("user_id").(uint64)
+	exclude, pinnedIDs, err := s.getCommentVisibility(ctx, postID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rootComments, nextCursor, err := s.getRootCommentPage(ctx, postID, exclude, req)
 	if err != nil {
 		return nil, err
 	}
 
-	var allIDs []uint64
+	// 置顶评论只在首页最前面展示一次
+	pinned := make(map[uint64]bool, len(pinnedIDs))
+	for _, id := range pinnedIDs {
+		pinned[id] = true
+	}
+	if len(pinnedIDs) > 0 {
+		unpinned := rootComments[:0]
+		for _, rc := range rootComments {
+			if !pinned[rc.ID] {
+				unpinned = append(unpinned, rc)
+			}
+		}
+		rootComments = unpinned
+
+		if req.Cursor == "" {
+			pinnedComments, err := s.actionRepo.GetRootCommentsByIDs(ctx, pinnedIDs, exclude)
+			if err != nil {
+				return nil, err
+			}
+			sortCommentsByIDs(pinnedComments, pinnedIDs)
+			rootComments = append(pinnedComments, rootComments...)
+		}
+	}
+
 	rootIDs := make([]uint64, 0, len(rootComments))
 	for _, rc := range rootComments {
-		allIDs = append(allIDs, rc.ID)
 		rootIDs = append(rootIDs, rc.ID)
-		for _, sc := range rc.SubComments {
-			allIDs = append(allIDs, sc.ID)
-		}
+	}
+	candidates, err := s.actionRepo.GetReplyPreviewCandidates(ctx, rootIDs, exclude, commentReplyCandidateSize)
+	if err != nil {
+		log.WarnContext(ctx, "get reply preview candidates failed", "postID", postID, "err", err)
 	}
 
-	currentUserID, _ := ctx.Value("user_id").(uint64)
+	allIDs := append([]uint64{}, rootIDs...)
+	allComments := append([]*model.PostComment{}, rootComments...)
+	for _, sc := range candidates {
+		allIDs = append(allIDs, sc.ID)
+	}
+	allComments = append(allComments, candidates...)
 
-	likesMap := s.batchGetCommentLikes(ctx, allIDs, rootComments)
-	countMap, _ := s.actionRepo.GetSubCommentCounts(ctx, rootIDs)
+	likesMap := s.batchGetCommentLikes(ctx, allIDs, allComments)
+	previews := rankReplyPreviews(candidates, likesMap)
+	countMap, _ := s.actionRepo.GetSubCommentCounts(ctx, rootIDs, exclude)
 	isLikedMap := s.batchGetCommentIsLiked(ctx, currentUserID, allIDs)
+	editedAt := s.batchGetCommentEditedAt(ctx, allIDs)
+	reactions := s.reactionSvc.GetReactionSummaries(ctx, currentUserID, model.ReactionTargetComment, allIDs)
 
 	res := make([]*dto.CommentDTO, 0, len(rootComments))
 	for _, rc := range rootComments {
 		rootDTO := s.convertToCommentDTO(rc, likesMap[rc.ID], isLikedMap[rc.ID])
 		rootDTO.SubCommentCount = int64(countMap[rc.ID])
-
-		if len(rc.SubComments) > 0 {
-			rootDTO.SubComments = make([]*dto.CommentDTO, 0, len(rc.SubComments))
-			for _, sc := range rc.SubComments {
-				rootDTO.SubComments = append(rootDTO.SubComments, s.convertToCommentDTO(sc, likesMap[sc.ID], isLikedMap[sc.ID]))
-			}
+		rootDTO.IsPinned = pinned[rc.ID]
+		rootDTO.Reactions = reactions[rc.ID]
+		setEdited(rootDTO, editedAt)
+
+		subs := previews[rc.ID]
+		rootDTO.SubComments = make([]*dto.CommentDTO, 0, len(subs))
+		for _, sc := range subs {
+			subDTO := s.convertToCommentDTO(sc, likesMap[sc.ID], isLikedMap[sc.ID])
+			subDTO.Reactions = reactions[sc.ID]
+			setEdited(subDTO, editedAt)
+			rootDTO.SubComments = append(rootDTO.SubComments, subDTO)
 		}
 		res = append(res, rootDTO)
 	}
-	return res, nil
+	return &dto.CommentListDTO{List: res, NextCursor: nextCursor, HasMore: nextCursor != ""}, nil
 }
 
 func (s *postActionServiceImpl) GetSubComments(ctx context.Context, rootID uint64, page, pageSize int) ([]*dto.CommentDTO, error) {
-	subs, err := s.actionRepo.GetSubCommentsByRootID(ctx, rootID, pageSize, (page-1)*pageSize)
+	currentUserID, _ := ctx.Value("user_id").(uint64)
+	root, err := s.actionRepo.GetCommentByID(ctx, rootID)
+	if err != nil || root == nil {
+		return nil, ErrPostCommentNotFound
+	}
+	exclude, _, err := s.getCommentVisibility(ctx, root.PostID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(exclude.CommentIDs, rootID) {
+		return nil, ErrPostCommentNotFound
+	}
+
+	subs, err := s.actionRepo.GetSubCommentsByRootID(ctx, rootID, exclude, pageSize, (page-1)*pageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -362,14 +468,17 @@ func (s *postActionServiceImpl) GetSubComments(ctx context.Context, rootID uint6
 		subIDs = append(subIDs, sc.ID)
 	}
 
-	currentUserID, _ := ctx.Value("user_id").(uint64)
-
 	likesMap := s.batchGetCommentLikes(ctx, subIDs, subs)
 	isLikedMap := s.batchGetCommentIsLiked(ctx, currentUserID, subIDs)
+	editedAt := s.batchGetCommentEditedAt(ctx, subIDs)
+	reactions := s.reactionSvc.GetReactionSummaries(ctx, currentUserID, model.ReactionTargetComment, subIDs)
 
 	res := make([]*dto.CommentDTO, 0, len(subs))
 	for _, sc := range subs {
-		res = append(res, s.convertToCommentDTO(sc, likesMap[sc.ID], isLikedMap[sc.ID]))
+		item := s.convertToCommentDTO(sc, likesMap[sc.ID], isLikedMap[sc.ID])
+		item.Reactions = reactions[sc.ID]
+		setEdited(item, editedAt)
+		res = append(res, item)
 	}
 	return res, nil
 }
@@ -444,27 +553,6 @@ func (s *postActionServiceImpl) GetPostViewCount(ctx context.Context, postID uin
 	return realCount, nil
 }
 
-func (s *postActionServiceImpl) ReportPost(ctx context.Context, userID, postID uint64) error {
-	post, err := s.postRepo.GetPost(ctx, postID)
-	if err != nil || post == nil {
-		return ErrPostNotFound
-	}
-
-	reportLockKey := consts.ReportLock + strconv.FormatUint(userID, 10) + ":" + strconv.FormatUint(postID, 10)
-	set, err := redis.TryLock(ctx, reportLockKey, "1", 24*time.Hour, 0)
-	if err != nil || !set {
-		return ErrActionDuplicate
-	}
-
-	countKey := consts.PostReportKey + strconv.FormatUint(postID, 10)
-	_ = redis.Incr(ctx, countKey)
-	count, _ := redis.GetInt64(ctx, countKey)
-	if count >= 50 {
-		_ = s.postRepo.UpdatePostStatus(ctx, postID, 3)
-	}
-	return nil
-}
-
 func (s *postActionServiceImpl) expandPostList(ctx context.Context, ids []uint64, pageSize int) (*dto.PostWaterfallDTO, error) {
 	hasMore := len(ids) > pageSize
 	if hasMore {
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/repository"
+	"context"
+	log "log/slog"
+	"time"
+)
+
+// commentPinLimit 每篇笔记最多置顶的评论数
+const commentPinLimit = 3
+
+func (s *postActionServiceImpl) GetCommentMode(ctx context.Context, postID uint64) (int8, error) {
+	setting, err := s.controlRepo.GetSetting(ctx, postID)
+	if err != nil || setting == nil {
+		return model.CommentModeAll, err
+	}
+	return setting.CommentMode, nil
+}
+
+// GetCommentSettings 作者查看笔记的评论设置与已置顶、隐藏的评论
+func (s *postActionServiceImpl) GetCommentSettings(ctx context.Context, userID, postID uint64) (*dto.CommentSettingsDTO, error) {
+	if _, err := s.getAuthoredPost(ctx, userID, postID); err != nil {
+		return nil, err
+	}
+	mode, err := s.GetCommentMode(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	marks, err := s.controlRepo.GetMarks(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.CommentSettingsDTO{
+		CommentMode:      mode,
+		PinnedCommentIDs: make([]uint64, 0),
+		HiddenCommentIDs: make([]uint64, 0),
+	}
+	for _, m := range marks {
+		switch m.Kind {
+		case model.CommentMarkPinned:
+			res.PinnedCommentIDs = append(res.PinnedCommentIDs, m.CommentID)
+		case model.CommentMarkHidden:
+			res.HiddenCommentIDs = append(res.HiddenCommentIDs, m.CommentID)
+		}
+	}
+	return res, nil
+}
+
+// UpdateCommentSettings 作者设置谁可以评论自己的笔记，已有评论不受影响
+func (s *postActionServiceImpl) UpdateCommentSettings(ctx context.Context, userID, postID uint64, req *dto.CommentSettingsUpdateDTO) error {
+	if _, err := s.getAuthoredPost(ctx, userID, postID); err != nil {
+		return err
+	}
+	now := time.Now()
+	return s.controlRepo.SaveSetting(ctx, &model.PostCommentSetting{
+		PostID:      postID,
+		CommentMode: req.CommentMode,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+}
+
+// PinComment 作者置顶自己笔记下的一级评论
+func (s *postActionServiceImpl) PinComment(ctx context.Context, userID, commentID uint64) error {
+	comment, err := s.getAuthoredComment(ctx, userID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.RootID != 0 || comment.Status != CommentStatusApproved {
+		return ErrCommentPinInvalid
+	}
+	hidden, err := s.controlRepo.GetMark(ctx, commentID, model.CommentMarkHidden)
+	if err != nil {
+		return err
+	}
+	if hidden != nil {
+		return ErrCommentPinInvalid
+	}
+
+	created, err := s.controlRepo.CreateMarkWithLimit(ctx, &model.PostCommentMark{
+		PostID:    comment.PostID,
+		CommentID: comment.ID,
+		UserID:    comment.UserID,
+		Kind:      model.CommentMarkPinned,
+		CreatedAt: time.Now(),
+	}, commentPinLimit)
+	if isDuplicateError(err) {
+		return ErrActionDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrCommentPinLimit
+	}
+	return nil
+}
+
+func (s *postActionServiceImpl) UnpinComment(ctx context.Context, userID, commentID uint64) error {
+	if _, err := s.getAuthoredComment(ctx, userID, commentID); err != nil {
+		return err
+	}
+	_, err := s.controlRepo.DeleteMark(ctx, commentID, model.CommentMarkPinned)
+	return err
+}
+
+// HideComment 作者隐藏评论，隐藏后仅评论者本人可见，置顶同时取消
+func (s *postActionServiceImpl) HideComment(ctx context.Context, userID, commentID uint64) error {
+	comment, err := s.getAuthoredComment(ctx, userID, commentID)
+	if err != nil {
+		return err
+	}
+	if err = s.createCommentMark(ctx, comment, model.CommentMarkHidden); err != nil {
+		return err
+	}
+	if _, err = s.controlRepo.DeleteMark(ctx, commentID, model.CommentMarkPinned); err != nil {
+		log.WarnContext(ctx, "unpin hidden comment failed", "commentID", commentID, "err", err)
+	}
+	return nil
+}
+
+func (s *postActionServiceImpl) UnhideComment(ctx context.Context, userID, commentID uint64) error {
+	if _, err := s.getAuthoredComment(ctx, userID, commentID); err != nil {
+		return err
+	}
+	_, err := s.controlRepo.DeleteMark(ctx, commentID, model.CommentMarkHidden)
+	return err
+}
+
+func (s *postActionServiceImpl) createCommentMark(ctx context.Context, comment *model.PostComment, kind int8) error {
+	err := s.controlRepo.CreateMark(ctx, &model.PostCommentMark{
+		PostID:    comment.PostID,
+		CommentID: comment.ID,
+		UserID:    comment.UserID,
+		Kind:      kind,
+		CreatedAt: time.Now(),
+	})
+	if isDuplicateError(err) {
+		return ErrActionDuplicate
+	}
+	return err
+}
+
+// getAuthoredPost 获取笔记并校验操作者是笔记作者
+func (s *postActionServiceImpl) getAuthoredPost(ctx context.Context, userID, postID uint64) (*model.Post, error) {
+	post, err := s.postRepo.GetPostByAllStatus(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	if post.UserID != userID {
+		return nil, UnauthorizedError
+	}
+	return post, nil
+}
+
+// getAuthoredComment 获取评论并校验操作者是评论所在笔记的作者
+func (s *postActionServiceImpl) getAuthoredComment(ctx context.Context, userID, commentID uint64) (*model.PostComment, error) {
+	comment, err := s.actionRepo.GetCommentByID(ctx, commentID)
+	if err != nil || comment == nil {
+		return nil, ErrPostCommentNotFound
+	}
+	if _, err = s.getAuthoredPost(ctx, userID, comment.PostID); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// checkCommentPermission 按作者的评论设置校验用户能否评论，作者本人不受限制
+func (s *postActionServiceImpl) checkCommentPermission(ctx context.Context, userID uint64, post *model.Post) error {
+	if post.UserID == userID {
+		return nil
+	}
+	mode, err := s.GetCommentMode(ctx, post.ID)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case model.CommentModeOff:
+		return ErrCommentDisabled
+	case model.CommentModeFollowers:
+		follow, err := s.userFollowRepo.GetUserFollow(ctx, userID, post.UserID)
+		if err != nil {
+			return err
+		}
+		if follow == nil {
+			return ErrCommentFollowersOnly
+		}
+	}
+	return nil
+}
+
+// isCommentHiddenFrom 评论或其根评论是否被作者隐藏且查看者不是评论者
+func (s *postActionServiceImpl) isCommentHiddenFrom(ctx context.Context, viewerID uint64, commentIDs ...uint64) (bool, error) {
+	for _, id := range commentIDs {
+		if id == 0 {
+			continue
+		}
+		mark, err := s.controlRepo.GetMark(ctx, id, model.CommentMarkHidden)
+		if err != nil {
+			return false, err
+		}
+		if mark != nil && mark.UserID != viewerID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getCommentVisibility 汇总查看者不可见的评论，并返回笔记的置顶评论（按置顶时间倒序）
+func (s *postActionServiceImpl) getCommentVisibility(ctx context.Context, postID, viewerID uint64) (*repository.CommentExclusion, []uint64, error) {
+	// 影子封禁用户的评论仅本人可见
+	exclude := &repository.CommentExclusion{UserIDs: getShadowBannedUsers(ctx, viewerID)}
+
+	marks, err := s.controlRepo.GetMarks(ctx, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var pinnedIDs []uint64
+	for _, m := range marks {
+		switch m.Kind {
+		case model.CommentMarkPinned:
+			pinnedIDs = append(pinnedIDs, m.CommentID)
+		case model.CommentMarkHidden:
+			// 作者隐藏的评论仅评论者本人可见
+			if m.UserID != viewerID {
+				exclude.CommentIDs = append(exclude.CommentIDs, m.CommentID)
+			}
+		}
+	}
+	return exclude, pinnedIDs, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/repository"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type fakePostRepo struct {
+	repository.PostRepo
+	posts map[uint64]*model.Post
+}
+
+func (f *fakePostRepo) GetPostByAllStatus(_ context.Context, id uint64) (*model.Post, error) {
+	return f.posts[id], nil
+}
+
+func (f *fakePostRepo) GetPostByIds(_ context.Context, ids []uint64) ([]*model.Post, error) {
+	var res []*model.Post
+	for _, id := range ids {
+		if p, ok := f.posts[id]; ok {
+			res = append(res, p)
+		}
+	}
+	return res, nil
+}
+
+type fakePostActionRepo struct {
+	repository.PostActionRepo
+	comments map[uint64]*model.PostComment
+}
+
+func (f *fakePostActionRepo) GetCommentByID(_ context.Context, id uint64) (*model.PostComment, error) {
+	return f.comments[id], nil
+}
+
+// fakeCommentControlRepo 标记以 (评论, 类型) 唯一，与数据表唯一索引一致
+type fakeCommentControlRepo struct {
+	repository.PostCommentControlRepo
+	marks []*model.PostCommentMark
+}
+
+func (f *fakeCommentControlRepo) GetMark(_ context.Context, commentID uint64, kind int8) (*model.PostCommentMark, error) {
+	for _, m := range f.marks {
+		if m.CommentID == commentID && m.Kind == kind {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeCommentControlRepo) CreateMark(ctx context.Context, mark *model.PostCommentMark) error {
+	if existing, _ := f.GetMark(ctx, mark.CommentID, mark.Kind); existing != nil {
+		return &mysql.MySQLError{Number: 1062}
+	}
+	f.marks = append(f.marks, mark)
+	return nil
+}
+
+func (f *fakeCommentControlRepo) CreateMarkWithLimit(ctx context.Context, mark *model.PostCommentMark, limit int64) (bool, error) {
+	var count int64
+	for _, m := range f.marks {
+		if m.PostID == mark.PostID && m.Kind == mark.Kind {
+			count++
+		}
+	}
+	if count >= limit {
+		return false, nil
+	}
+	return true, f.CreateMark(ctx, mark)
+}
+
+func (f *fakeCommentControlRepo) DeleteMark(_ context.Context, commentID uint64, kind int8) (bool, error) {
+	for i, m := range f.marks {
+		if m.CommentID == commentID && m.Kind == kind {
+			f.marks = append(f.marks[:i], f.marks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+const (
+	testPostID   = 1
+	testAuthorID = 10
+)
+
+func newTestCommentControl() (*postActionServiceImpl, *fakePostActionRepo, *fakeCommentControlRepo) {
+	actions := &fakePostActionRepo{comments: make(map[uint64]*model.PostComment)}
+	for id := uint64(1); id <= commentPinLimit+2; id++ {
+		actions.comments[id] = &model.PostComment{ID: id, PostID: testPostID, UserID: 20, Status: CommentStatusApproved}
+	}
+	controls := &fakeCommentControlRepo{}
+	svc := &postActionServiceImpl{
+		actionRepo:  actions,
+		postRepo:    &fakePostRepo{posts: map[uint64]*model.Post{testPostID: {ID: testPostID, UserID: testAuthorID}}},
+		controlRepo: controls,
+	}
+	return svc, actions, controls
+}
+
+func TestPinCommentLimit(t *testing.T) {
+	svc, _, controls := newTestCommentControl()
+	ctx := context.Background()
+
+	for id := uint64(1); id <= commentPinLimit; id++ {
+		if err := svc.PinComment(ctx, testAuthorID, id); err != nil {
+			t.Fatalf("pin comment %d: %v", id, err)
+		}
+	}
+	if err := svc.PinComment(ctx, testAuthorID, commentPinLimit+1); !errors.Is(err, ErrCommentPinLimit) {
+		t.Fatalf("pin over limit err = %v, want ErrCommentPinLimit", err)
+	}
+	if len(controls.marks) != commentPinLimit {
+		t.Fatalf("pinned = %d, want %d", len(controls.marks), commentPinLimit)
+	}
+
+	// 取消一条置顶后腾出名额
+	if err := svc.UnpinComment(ctx, testAuthorID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PinComment(ctx, testAuthorID, commentPinLimit+1); err != nil {
+		t.Fatalf("pin after unpin: %v", err)
+	}
+}
+
+func TestPinCommentDuplicate(t *testing.T) {
+	svc, _, _ := newTestCommentControl()
+	ctx := context.Background()
+
+	if err := svc.PinComment(ctx, testAuthorID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PinComment(ctx, testAuthorID, 1); !errors.Is(err, ErrActionDuplicate) {
+		t.Fatalf("duplicate pin err = %v, want ErrActionDuplicate", err)
+	}
+}
+
+func TestPinCommentInvalid(t *testing.T) {
+	svc, actions, _ := newTestCommentControl()
+	ctx := context.Background()
+
+	actions.comments[1].RootID = 2
+	if err := svc.PinComment(ctx, testAuthorID, 1); !errors.Is(err, ErrCommentPinInvalid) {
+		t.Fatalf("pin reply err = %v, want ErrCommentPinInvalid", err)
+	}
+
+	actions.comments[2].Status = 0
+	if err := svc.PinComment(ctx, testAuthorID, 2); !errors.Is(err, ErrCommentPinInvalid) {
+		t.Fatalf("pin unapproved err = %v, want ErrCommentPinInvalid", err)
+	}
+
+	if err := svc.HideComment(ctx, testAuthorID, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PinComment(ctx, testAuthorID, 3); !errors.Is(err, ErrCommentPinInvalid) {
+		t.Fatalf("pin hidden err = %v, want ErrCommentPinInvalid", err)
+	}
+
+	if err := svc.PinComment(ctx, testAuthorID+1, 4); !errors.Is(err, UnauthorizedError) {
+		t.Fatalf("pin by non-author err = %v, want UnauthorizedError", err)
+	}
+}
+
+func TestHideCommentUnpins(t *testing.T) {
+	svc, _, controls := newTestCommentControl()
+	ctx := context.Background()
+
+	if err := svc.PinComment(ctx, testAuthorID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.HideComment(ctx, testAuthorID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if pinned, _ := controls.GetMark(ctx, 1, model.CommentMarkPinned); pinned != nil {
+		t.Fatal("hidden comment should no longer be pinned")
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"context"
+	log "log/slog"
+	"time"
+)
+
+// commentEditWindow 评论发布后允许编辑的时长
+const commentEditWindow = 30 * time.Minute
+
+// EditComment 评论者在编辑窗口内修改评论文字，新内容审核通过前仍展示原内容
+func (s *postActionServiceImpl) EditComment(ctx context.Context, userID, commentID uint64, req *dto.CommentEditDTO) error {
+	comment, err := s.actionRepo.GetCommentByID(ctx, commentID)
+	if err != nil || comment == nil {
+		return ErrPostCommentNotFound
+	}
+	if comment.UserID != userID {
+		return UnauthorizedError
+	}
+	if time.Since(comment.CreatedAt) > commentEditWindow {
+		return ErrCommentEditExpired
+	}
+	switch {
+	case comment.Status == CommentStatusPending || comment.EditPending:
+		return ErrCommentEditPending
+	case comment.Status != CommentStatusApproved:
+		return ErrCommentEditNotAllowed
+	}
+	if req.Content == comment.Content {
+		return nil
+	}
+
+	submitted, err := s.editRepo.SubmitEdit(ctx, &model.PostCommentEdit{
+		CommentID:       commentID,
+		UserID:          userID,
+		PreviousContent: comment.Content,
+		Content:         req.Content,
+		Status:          model.CommentEditPending,
+		CreatedAt:       time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if !submitted {
+		return ErrCommentEditPending
+	}
+	return nil
+}
+
+// GetCommentEdits 获取评论的编辑历史，评论者本人可看到待审核与未通过的编辑
+func (s *postActionServiceImpl) GetCommentEdits(ctx context.Context, commentID uint64) ([]*dto.CommentEditHistoryDTO, error) {
+	currentUserID, _ := ctx.Value("user_id").(uint64)
+	comment, err := s.actionRepo.GetCommentByID(ctx, commentID)
+	if err != nil || comment == nil {
+		return nil, ErrPostCommentNotFound
+	}
+
+	isWriter := comment.UserID == currentUserID
+	if !isWriter {
+		hidden, err := s.isCommentHiddenFrom(ctx, currentUserID, commentID)
+		if err != nil {
+			return nil, err
+		}
+		if hidden || isShadowBanned(ctx, comment.UserID) {
+			return nil, ErrPostCommentNotFound
+		}
+	}
+
+	edits, err := s.editRepo.GetEdits(ctx, commentID, !isWriter)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.CommentEditHistoryDTO, 0, len(edits))
+	for _, e := range edits {
+		item := &dto.CommentEditHistoryDTO{
+			ID:              e.ID,
+			PreviousContent: e.PreviousContent,
+			Content:         e.Content,
+			Status:          e.Status,
+			CreatedAt:       e.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if e.ReviewedAt != nil {
+			reviewedAt := e.ReviewedAt.UTC().Format(time.RFC3339)
+			item.ReviewedAt = &reviewedAt
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// batchGetCommentEditedAt 批量获取评论最近一次编辑生效的时间，查询失败时不展示编辑标记
+func (s *postActionServiceImpl) batchGetCommentEditedAt(ctx context.Context, commentIDs []uint64) map[uint64]time.Time {
+	editedAt, err := s.editRepo.GetLastEditTimes(ctx, commentIDs)
+	if err != nil {
+		log.WarnContext(ctx, "get comment edit times failed", "err", err)
+		return map[uint64]time.Time{}
+	}
+	return editedAt
+}
+
+// setEdited 为编辑过的评论设置“已编辑”标记
+func setEdited(item *dto.CommentDTO, editedAt map[uint64]time.Time) {
+	if t, ok := editedAt[item.ID]; ok {
+		item.IsEdited = true
+		item.EditedAt = t.UTC().Format(time.RFC3339)
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+const (
+	CommentSortHot    = "hot"
+	CommentSortNewest = "newest"
+	CommentSortOldest = "oldest"
+)
+
+const (
+	commentHotSnapshotTTL     = 60 * time.Second // 热度排名快照有效期，过期后按最新点赞与回复数重建
+	commentHotReplyWeight     = 2                // 一条回复折合的点赞数
+	commentHotDecayPeriod     = 45000            // 时间衰减周期（秒），晚发布一个周期的评论与热度高 10 倍的评论同分
+	commentHotLikeBatchSize   = 500              // 重建快照时每批读取的点赞计数数量
+	commentHotMemberWidth     = 20               // 快照成员 ID 补零宽度，同分成员按 ID 倒序排列
+	commentReplyPreviewSize   = 2                // 每条根评论内嵌的热门回复数
+	commentReplyCandidateSize = 10               // 每条根评论参与热门回复排序的候选数
+)
+
+// commentHotScore 热度 = log10(点赞 + 回复折算 + 1) + 发布时间 / 衰减周期，
+// 分值与当前时间无关，快照重建后 (score, id) 游标仍然有效
+func commentHotScore(likes, replies int, createdAt time.Time) float64 {
+	engagement := math.Max(float64(likes+replies*commentHotReplyWeight+1), 1)
+	return math.Log10(engagement) + float64(createdAt.Unix())/commentHotDecayPeriod
+}
+
+// hotMember 快照成员使用定宽 ID，使同分成员的字典序与数值序一致
+func hotMember(id uint64) string {
+	return fmt.Sprintf("%0*d", commentHotMemberWidth, id)
+}
+
+// getHotRootComments 从热度排名快照中读取 (score, id) 游标之后的根评论，快照缺失时重建；
+// cursorID 为 0 时从头读取
+func (s *postActionServiceImpl) getHotRootComments(ctx context.Context, postID uint64, cursorScore float64, cursorID uint64, limit int) ([]redisv9.Z, bool, error) {
+	key := consts.PostCommentHotKey + strconv.FormatUint(postID, 10)
+	exists, err := redis.Exists(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		if err = s.buildHotSnapshot(ctx, postID, key); err != nil {
+			return nil, false, err
+		}
+	}
+
+	rdb := redis.GetRdbClient()
+	maxScore := "+inf"
+	var skip int64
+	if cursorID > 0 {
+		maxScore = strconv.FormatFloat(cursorScore, 'f', -1, 64)
+		// 与游标同分的成员按 ID 倒序排列，跳过 ID 不小于游标的部分
+		ties, err := rdb.ZRangeByScore(ctx, key, &redisv9.ZRangeBy{Min: maxScore, Max: maxScore}).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		cursorMember := hotMember(cursorID)
+		for _, m := range ties {
+			if m >= cursorMember {
+				skip++
+			}
+		}
+	}
+
+	// 多取一条用于判断是否还有下一页
+	members, err := rdb.ZRangeArgsWithScores(ctx, redisv9.ZRangeArgs{
+		Key:     key,
+		Start:   maxScore,
+		Stop:    "-inf",
+		ByScore: true,
+		Rev:     true,
+		Offset:  skip,
+		Count:   int64(limit + 1),
+	}).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	hasMore := len(members) > limit
+	if hasMore {
+		members = members[:limit]
+	}
+	return members, hasMore, nil
+}
+
+// buildHotSnapshot 用 Redis 点赞计数为全部根评论打分并写入快照，计数缺失时回退落库值
+func (s *postActionServiceImpl) buildHotSnapshot(ctx context.Context, postID uint64, key string) error {
+	stats, err := s.actionRepo.GetRootCommentHotStats(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	likeKeys := make([]string, len(stats))
+	for i, st := range stats {
+		likeKeys[i] = consts.PostCommentLikeKey + strconv.FormatUint(st.ID, 10)
+	}
+	cacheData := make(map[string]string, len(likeKeys))
+	for i := 0; i < len(likeKeys); i += commentHotLikeBatchSize {
+		batch, _ := redis.MGetValue(ctx, likeKeys[i:min(i+commentHotLikeBatchSize, len(likeKeys))]...)
+		for k, v := range batch {
+			cacheData[k] = v
+		}
+	}
+
+	pipe := redis.GetRdbClient().TxPipeline()
+	pipe.Del(ctx, key)
+	members := make([]redisv9.Z, 0, len(stats)+1)
+	for i, st := range stats {
+		likes := st.LikesCount
+		if val, ok := cacheData[likeKeys[i]]; ok {
+			likes, _ = strconv.Atoi(val)
+		}
+		members = append(members, redisv9.Z{
+			Score:  commentHotScore(likes, st.ReplyCount, st.CreatedAt),
+			Member: hotMember(st.ID),
+		})
+	}
+	// 无评论时写入占位成员，避免空帖子每次都回源
+	if len(members) == 0 {
+		members = append(members, redisv9.Z{Score: -1, Member: hotMember(0)})
+	}
+	pipe.ZAdd(ctx, key, members...)
+	pipe.Expire(ctx, key, commentHotSnapshotTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// getRootCommentPage 按排序方式读取一页根评论并生成下一页游标
+func (s *postActionServiceImpl) getRootCommentPage(ctx context.Context, postID uint64, exclude *repository.CommentExclusion, req *dto.CommentQueryDTO) ([]*model.PostComment, string, error) {
+	sortValues, err := util.DecodeCursor(req.Cursor)
+	if err != nil {
+		return nil, "", ErrParamInvalid
+	}
+
+	if req.Sort == CommentSortHot {
+		var cursorScore float64
+		var cursorID uint64
+		if len(sortValues) > 0 {
+			if len(sortValues) != 2 {
+				return nil, "", ErrParamInvalid
+			}
+			score, ok1 := sortValues[0].(float64)
+			id, ok2 := sortValues[1].(float64)
+			if !ok1 || !ok2 || id <= 0 {
+				return nil, "", ErrParamInvalid
+			}
+			cursorScore, cursorID = score, uint64(id)
+		}
+
+		members, hasMore, err := s.getHotRootComments(ctx, postID, cursorScore, cursorID, req.PageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		ids := make([]uint64, 0, len(members))
+		for _, m := range members {
+			member, _ := m.Member.(string)
+			if id, err := strconv.ParseUint(member, 10, 64); err == nil && id > 0 {
+				ids = append(ids, id)
+			}
+		}
+		comments, err := s.actionRepo.GetRootCommentsByIDs(ctx, ids, exclude)
+		if err != nil {
+			return nil, "", err
+		}
+		sortCommentsByIDs(comments, ids)
+
+		// 游标取快照中本页最后一个成员，被过滤掉的评论不影响翻页位置
+		var nextCursor string
+		if hasMore && len(members) > 0 {
+			last := members[len(members)-1]
+			member, _ := last.Member.(string)
+			lastID, _ := strconv.ParseUint(member, 10, 64)
+			nextCursor = util.EncodeCursor([]interface{}{last.Score, lastID})
+		}
+		return comments, nextCursor, nil
+	}
+
+	var cursorTime time.Time
+	var cursorID uint64
+	if len(sortValues) > 0 {
+		if len(sortValues) != 2 {
+			return nil, "", ErrParamInvalid
+		}
+		ts, ok1 := sortValues[0].(float64)
+		id, ok2 := sortValues[1].(float64)
+		if !ok1 || !ok2 || id <= 0 {
+			return nil, "", ErrParamInvalid
+		}
+		cursorTime, cursorID = time.Unix(int64(ts), 0), uint64(id)
+	}
+
+	comments, err := s.actionRepo.GetRootCommentsByPostID(ctx, postID, exclude, req.Sort == CommentSortOldest, cursorTime, cursorID, req.PageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+	var nextCursor string
+	if len(comments) > req.PageSize {
+		comments = comments[:req.PageSize]
+		last := comments[len(comments)-1]
+		nextCursor = util.EncodeCursor([]interface{}{last.CreatedAt.Unix(), last.ID})
+	}
+	return comments, nextCursor, nil
+}
+
+// sortCommentsByIDs 按 ids 的顺序重排评论
+func sortCommentsByIDs(comments []*model.PostComment, ids []uint64) {
+	rank := make(map[uint64]int, len(ids))
+	for i, id := range ids {
+		rank[id] = i
+	}
+	sort.Slice(comments, func(i, j int) bool { return rank[comments[i].ID] < rank[comments[j].ID] })
+}
+
+// rankReplyPreviews 按热度挑选每条根评论的前几条回复
+func rankReplyPreviews(candidates []*model.PostComment, likesMap map[uint64]int) map[uint64][]*model.PostComment {
+	scores := make(map[uint64]float64, len(candidates))
+	for _, c := range candidates {
+		scores[c.ID] = commentHotScore(likesMap[c.ID], 0, c.CreatedAt)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return scores[candidates[i].ID] > scores[candidates[j].ID] })
+
+	previews := make(map[uint64][]*model.PostComment)
+	for _, c := range candidates {
+		if len(previews[c.RootID]) < commentReplyPreviewSize {
+			previews[c.RootID] = append(previews[c.RootID], c)
+		}
+	}
+	return previews
+}
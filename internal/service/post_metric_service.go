@@ -21,17 +21,25 @@ type PostMetricService interface {
 	GetPostMetricsBy7Days(ctx context.Context, postID uint64, userID uint64) (*dto.PostTrendDTO, error)
 	// GetPostMetricsBy30Days 获取最近30天全维度趋势数据
 	GetPostMetricsBy30Days(ctx context.Context, postID uint64, userID uint64) (*dto.PostTrendDTO, error)
+	// GetExperimentMetrics 获取推荐实验各分组的指标对比
+	GetExperimentMetrics(ctx context.Context, experimentID string, days int) (*dto.ExperimentMetricDTO, error)
 }
 
 type postMetricServiceImpl struct {
-	postMetricRepo repository.PostMetricRepo
-	postRepo       repository.PostRepo
+	postMetricRepo     repository.PostMetricRepo
+	postRepo           repository.PostRepo
+	recommendEventRepo repository.RecommendEventRepo
 }
 
-func NewPostMetricService(postMetricRepo repository.PostMetricRepo, postRepo repository.PostRepo) PostMetricService {
+func NewPostMetricService(
+	postMetricRepo repository.PostMetricRepo,
+	postRepo repository.PostRepo,
+	recommendEventRepo repository.RecommendEventRepo,
+) PostMetricService {
 	return &postMetricServiceImpl{
-		postMetricRepo: postMetricRepo,
-		postRepo:       postRepo,
+		postMetricRepo:     postMetricRepo,
+		postRepo:           postRepo,
+		recommendEventRepo: recommendEventRepo,
 	}
 }
 
@@ -77,6 +85,42 @@ func (s *postMetricServiceImpl) GetPostMetricsBy30Days(ctx context.Context, post
 	})
 }
 
+// GetExperimentMetrics 按分组计算 CTR、点赞率与平均停留时长
+func (s *postMetricServiceImpl) GetExperimentMetrics(ctx context.Context, experimentID string, days int) (*dto.ExperimentMetricDTO, error) {
+	if experimentID == "" || days <= 0 || days > 30 {
+		return nil, ErrParamInvalid
+	}
+
+	since := util.GetMidnight(time.Now()).AddDate(0, 0, -days+1)
+	stats, err := s.recommendEventRepo.GetVariantStats(ctx, experimentID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ExperimentMetricDTO{
+		ExperimentID: experimentID,
+		Days:         days,
+		Variants:     make([]*dto.ExperimentVariantMetricDTO, 0, len(stats)),
+	}
+	for _, st := range stats {
+		item := &dto.ExperimentVariantMetricDTO{
+			Variant:   st.Variant,
+			Exposures: st.Exposures,
+			Clicks:    st.Clicks,
+			Likes:     st.Likes,
+		}
+		if st.Exposures > 0 {
+			item.CTR = float64(st.Clicks) / float64(st.Exposures)
+			item.LikeRate = float64(st.Likes) / float64(st.Exposures)
+		}
+		if st.DwellCount > 0 {
+			item.AvgDwellMs = float64(st.DwellTotal) / float64(st.DwellCount)
+		}
+		res.Variants = append(res.Variants, item)
+	}
+	return res, nil
+}
+
 // getPostMetrics 聚合查询与数据平滑逻辑
 func (s *postMetricServiceImpl) getPostMetrics(
 	ctx context.Context,
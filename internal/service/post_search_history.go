@@ -0,0 +1,412 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"context"
+	"fmt"
+	log "log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+const (
+	// searchHistoryCap 每个用户保留的搜索历史条数
+	searchHistoryCap = 20
+	// searchHistoryTTL 搜索历史过期时间
+	searchHistoryTTL = 30 * 24 * time.Hour
+	// searchKeywordMaxLen 计入历史与热搜的关键词最大长度
+	searchKeywordMaxLen = 30
+
+	// hotSearchSize 热搜榜展示数量
+	hotSearchSize = 10
+	// hotSearchDedupTTL 同一用户同一关键词在该时间内只计一次热度
+	hotSearchDedupTTL = time.Hour
+	// hotSearchDecay 热度每小时衰减系数，半衰期约 13.5 小时
+	hotSearchDecay = 0.95
+	// hotSearchMinScore 热度低于该值的词被淘汰
+	hotSearchMinScore = 0.1
+	// hotSearchKeep 热搜 ZSET 最多保留的词数
+	hotSearchKeep = 500
+
+	// suggestionSize 搜索建议最大条数
+	suggestionSize = 10
+	// suggestionHistorySize 搜索建议中历史记录的最大条数
+	suggestionHistorySize = 3
+	// suggestionHotSize 搜索建议中热搜词的最大条数
+	suggestionHotSize = 3
+)
+
+// 搜索建议来源
+const (
+	SuggestionSourceKeyword = "keyword"
+	SuggestionSourceHistory = "history"
+	SuggestionSourceHot     = "hot"
+	SuggestionSourceSearch  = "search"
+)
+
+// Suggestion 搜索建议，合并用户历史、热搜词与 ES 补全；关键词为空时返回历史与热搜
+func (s *postServiceImpl) Suggestion(ctx context.Context, userID uint64, keyword string) ([]*dto.SearchSuggestionDTO, error) {
+	keyword = normalizeSearchKeyword(keyword)
+	merger := newSuggestionMerger(suggestionSize)
+
+	if keyword == "" {
+		history, err := s.GetSearchHistory(ctx, userID)
+		if err != nil {
+			log.WarnContext(ctx, "get search history failed", "err", err)
+		}
+		merger.add(SuggestionSourceHistory, history, suggestionSize/2)
+
+		hot, err := s.getHotKeywords(ctx, hotSearchSize)
+		if err != nil {
+			log.WarnContext(ctx, "get hot search failed", "err", err)
+		}
+		for _, h := range hot {
+			merger.add(SuggestionSourceHot, []string{h.Keyword}, 1)
+		}
+		return merger.list, nil
+	}
+
+	esSuggestions, err := s.postESRepo.GetSuggestions(ctx, keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	merger.add(SuggestionSourceKeyword, []string{keyword}, 1)
+
+	history, err := s.GetSearchHistory(ctx, userID)
+	if err != nil {
+		log.WarnContext(ctx, "get search history failed", "err", err)
+	}
+	merger.add(SuggestionSourceHistory, filterKeywords(history, keyword, strings.HasPrefix), suggestionHistorySize)
+
+	hot, err := s.getHotKeywords(ctx, hotSearchSize*5)
+	if err != nil {
+		log.WarnContext(ctx, "get hot search failed", "err", err)
+	}
+	hotWords := make([]string, 0, len(hot))
+	for _, h := range hot {
+		hotWords = append(hotWords, h.Keyword)
+	}
+	merger.add(SuggestionSourceHot, filterKeywords(hotWords, keyword, strings.Contains), suggestionHotSize)
+
+	merger.add(SuggestionSourceSearch, esSuggestions, suggestionSize)
+
+	return merger.list, nil
+}
+
+// GetSearchHistory 获取用户搜索历史，按最近搜索倒序
+func (s *postServiceImpl) GetSearchHistory(ctx context.Context, userID uint64) ([]string, error) {
+	if userID == 0 {
+		return []string{}, nil
+	}
+	key := consts.SearchHistoryKey + strconv.FormatUint(userID, 10)
+	return redis.ZRevRange(ctx, key, 0, searchHistoryCap-1)
+}
+
+// DeleteSearchHistory 删除单条搜索历史，关键词为空时清空全部
+func (s *postServiceImpl) DeleteSearchHistory(ctx context.Context, userID uint64, keyword string) error {
+	key := consts.SearchHistoryKey + strconv.FormatUint(userID, 10)
+	keyword = normalizeSearchKeyword(keyword)
+	if keyword == "" {
+		return redis.DeleteKey(ctx, key)
+	}
+	return redis.GetRdbClient().ZRem(ctx, key, keyword).Err()
+}
+
+// GetHotSearches 获取热搜榜，置顶词按位置插入
+func (s *postServiceImpl) GetHotSearches(ctx context.Context) ([]*dto.HotSearchDTO, error) {
+	return s.getHotKeywords(ctx, hotSearchSize)
+}
+
+// GetHotSearchesAdmin 管理端获取热搜词、置顶词与屏蔽词
+func (s *postServiceImpl) GetHotSearchesAdmin(ctx context.Context) (*dto.HotSearchAdminDTO, error) {
+	hot, err := redis.ZRevRangeWithScores(ctx, consts.SearchHotKey, 0, hotSearchSize*5-1)
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := s.getPinnedKeywords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	suppressed, err := redis.GetSet(ctx, consts.SearchHotBlockedKey)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(suppressed)
+
+	res := &dto.HotSearchAdminDTO{
+		Hot:        make([]*dto.HotSearchScoreDTO, 0, len(hot)),
+		Pinned:     pinned,
+		Suppressed: suppressed,
+	}
+	for _, z := range hot {
+		member, _ := z.Member.(string)
+		res.Hot = append(res.Hot, &dto.HotSearchScoreDTO{Keyword: member, Score: z.Score})
+	}
+	return res, nil
+}
+
+// PinHotSearch 将关键词置顶到热搜榜指定位置，同一位置只保留一个词
+func (s *postServiceImpl) PinHotSearch(ctx context.Context, keyword string, position int) error {
+	keyword = normalizeSearchKeyword(keyword)
+	if keyword == "" {
+		return ErrParamInvalid
+	}
+	variants, err := pinnedVariants(ctx, keyword)
+	if err != nil {
+		return err
+	}
+	rdb := redis.GetRdbClient()
+	pipe := rdb.TxPipeline()
+	score := strconv.Itoa(position)
+	pipe.ZRemRangeByScore(ctx, consts.SearchHotPinnedKey, score, score)
+	if len(variants) > 0 {
+		pipe.ZRem(ctx, consts.SearchHotPinnedKey, variants...)
+	}
+	pipe.ZAdd(ctx, consts.SearchHotPinnedKey, redisv9.Z{Score: float64(position), Member: keyword})
+	pipe.SRem(ctx, consts.SearchHotBlockedKey, strings.ToLower(keyword))
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// UnpinHotSearch 取消置顶，忽略大小写
+func (s *postServiceImpl) UnpinHotSearch(ctx context.Context, keyword string) error {
+	variants, err := pinnedVariants(ctx, normalizeSearchKeyword(keyword))
+	if err != nil || len(variants) == 0 {
+		return err
+	}
+	return redis.GetRdbClient().ZRem(ctx, consts.SearchHotPinnedKey, variants...).Err()
+}
+
+// SuppressHotSearch 屏蔽关键词，包含屏蔽词的热搜词不再展示与计数
+func (s *postServiceImpl) SuppressHotSearch(ctx context.Context, keyword string) error {
+	keyword = strings.ToLower(normalizeSearchKeyword(keyword))
+	if keyword == "" {
+		return ErrParamInvalid
+	}
+	// 置顶词保留原始大小写，按忽略大小写移除
+	variants, err := pinnedVariants(ctx, keyword)
+	if err != nil {
+		return err
+	}
+	rdb := redis.GetRdbClient()
+	pipe := rdb.TxPipeline()
+	pipe.SAdd(ctx, consts.SearchHotBlockedKey, keyword)
+	pipe.ZRem(ctx, consts.SearchHotKey, keyword)
+	if len(variants) > 0 {
+		pipe.ZRem(ctx, consts.SearchHotPinnedKey, variants...)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// UnsuppressHotSearch 解除屏蔽
+func (s *postServiceImpl) UnsuppressHotSearch(ctx context.Context, keyword string) error {
+	return redis.SRem(ctx, consts.SearchHotBlockedKey, strings.ToLower(normalizeSearchKeyword(keyword)))
+}
+
+// DecayHotSearches 热度整体按系数衰减，并淘汰低热度与超出容量的词
+func (s *postServiceImpl) DecayHotSearches(ctx context.Context) error {
+	rdb := redis.GetRdbClient()
+	key := consts.SearchHotKey
+	if err := rdb.ZUnionStore(ctx, key, &redisv9.ZStore{
+		Keys:    []string{key},
+		Weights: []float64{hotSearchDecay},
+	}).Err(); err != nil {
+		return err
+	}
+	if err := rdb.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%f", hotSearchMinScore)).Err(); err != nil {
+		return err
+	}
+	return redis.ZRemRangeByRank(ctx, key, 0, -(hotSearchKeep + 1))
+}
+
+// recordSearch 记录搜索历史并累加热度，匿名与影子封禁用户不计入热度，防止刷榜
+func (s *postServiceImpl) recordSearch(ctx context.Context, userID uint64, keyword string) {
+	keyword = normalizeSearchKeyword(keyword)
+	if userID == 0 || keyword == "" || utf8.RuneCountInString(keyword) > searchKeywordMaxLen {
+		return
+	}
+
+	rdb := redis.GetRdbClient()
+	uid := strconv.FormatUint(userID, 10)
+
+	historyKey := consts.SearchHistoryKey + uid
+	pipe := rdb.TxPipeline()
+	pipe.ZAdd(ctx, historyKey, redisv9.Z{Score: float64(time.Now().UnixMilli()), Member: keyword})
+	pipe.ZRemRangeByRank(ctx, historyKey, 0, -(searchHistoryCap + 1))
+	pipe.Expire(ctx, historyKey, searchHistoryTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.WarnContext(ctx, "record search history failed", "err", err)
+	}
+
+	blocked, err := s.getBlockedKeywords(ctx)
+	if err != nil {
+		log.WarnContext(ctx, "get hot search blocklist failed", "err", err)
+		return
+	}
+	if isBlockedKeyword(keyword, blocked) {
+		return
+	}
+	// 影子封禁用户的搜索不计入热搜
+	if isShadowBanned(ctx, userID) {
+		return
+	}
+
+	hotKeyword := strings.ToLower(keyword)
+	ok, err := rdb.SetNX(ctx, consts.SearchHotDedupKey+uid+":"+hotKeyword, 1, hotSearchDedupTTL).Result()
+	if err != nil || !ok {
+		return
+	}
+	if err = rdb.ZIncrBy(ctx, consts.SearchHotKey, 1, hotKeyword).Err(); err != nil {
+		log.WarnContext(ctx, "incr hot search failed", "err", err)
+	}
+}
+
+// getHotKeywords 取热度最高的关键词，过滤屏蔽词后将置顶词插入指定位置
+func (s *postServiceImpl) getHotKeywords(ctx context.Context, size int) ([]*dto.HotSearchDTO, error) {
+	pinned, err := s.getPinnedKeywords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := s.getBlockedKeywords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pinnedSet := make(map[string]struct{}, len(pinned))
+	for _, p := range pinned {
+		pinnedSet[strings.ToLower(p.Keyword)] = struct{}{}
+	}
+
+	candidates, err := redis.ZRevRange(ctx, consts.SearchHotKey, 0, int64(size+len(pinned)+len(blocked))*2)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*dto.HotSearchDTO, 0, size)
+	for _, kw := range candidates {
+		if len(list) >= size {
+			break
+		}
+		if _, ok := pinnedSet[kw]; ok {
+			continue
+		}
+		if isBlockedKeyword(kw, blocked) {
+			continue
+		}
+		list = append(list, &dto.HotSearchDTO{Keyword: kw})
+	}
+
+	for _, p := range pinned {
+		item := &dto.HotSearchDTO{Keyword: p.Keyword, Pinned: true}
+		idx := p.Position - 1
+		if idx > len(list) {
+			idx = len(list)
+		}
+		list = append(list[:idx], append([]*dto.HotSearchDTO{item}, list[idx:]...)...)
+	}
+	if len(list) > size {
+		list = list[:size]
+	}
+	return list, nil
+}
+
+// getPinnedKeywords 按位置升序获取置顶词
+func (s *postServiceImpl) getPinnedKeywords(ctx context.Context) ([]*dto.HotSearchPinnedDTO, error) {
+	zs, err := redis.GetRdbClient().ZRangeWithScores(ctx, consts.SearchHotPinnedKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.HotSearchPinnedDTO, 0, len(zs))
+	for _, z := range zs {
+		member, _ := z.Member.(string)
+		res = append(res, &dto.HotSearchPinnedDTO{Keyword: member, Position: int(z.Score)})
+	}
+	return res, nil
+}
+
+func (s *postServiceImpl) getBlockedKeywords(ctx context.Context) ([]string, error) {
+	return redis.GetSet(ctx, consts.SearchHotBlockedKey)
+}
+
+// isBlockedKeyword 关键词包含任一屏蔽词即视为屏蔽
+func isBlockedKeyword(keyword string, blocked []string) bool {
+	lower := strings.ToLower(keyword)
+	for _, b := range blocked {
+		if b != "" && strings.Contains(lower, strings.ToLower(b)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSearchKeyword 去除首尾空白并合并连续空白
+// pinnedVariants 获取与关键词忽略大小写相同的置顶词
+func pinnedVariants(ctx context.Context, keyword string) ([]any, error) {
+	members, err := redis.GetRdbClient().ZRange(ctx, consts.SearchHotPinnedKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]any, 0, 1)
+	for _, m := range members {
+		if strings.EqualFold(m, keyword) {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+func normalizeSearchKeyword(keyword string) string {
+	return strings.Join(strings.Fields(keyword), " ")
+}
+
+// filterKeywords 按匹配函数筛选与输入相关的关键词，忽略大小写
+func filterKeywords(words []string, keyword string, match func(s, sub string) bool) []string {
+	lower := strings.ToLower(keyword)
+	res := make([]string, 0, len(words))
+	for _, w := range words {
+		if match(strings.ToLower(w), lower) {
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// suggestionMerger 按来源顺序合并搜索建议，忽略大小写去重
+type suggestionMerger struct {
+	list []*dto.SearchSuggestionDTO
+	seen map[string]struct{}
+	size int
+}
+
+func newSuggestionMerger(size int) *suggestionMerger {
+	return &suggestionMerger{
+		list: make([]*dto.SearchSuggestionDTO, 0, size),
+		seen: make(map[string]struct{}, size),
+		size: size,
+	}
+}
+
+func (m *suggestionMerger) add(source string, words []string, limit int) {
+	added := 0
+	for _, w := range words {
+		if len(m.list) >= m.size || added >= limit {
+			return
+		}
+		key := strings.ToLower(w)
+		if _, ok := m.seen[key]; ok || w == "" {
+			continue
+		}
+		m.seen[key] = struct{}{}
+		m.list = append(m.list, &dto.SearchSuggestionDTO{Keyword: w, Source: source})
+		added++
+	}
+}
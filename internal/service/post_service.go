@@ -7,6 +7,7 @@ import (
 	"Cornerstone/internal/pkg/es"
 	"Cornerstone/internal/pkg/llm"
 	"Cornerstone/internal/pkg/minio"
+	"Cornerstone/internal/pkg/recommend"
 	"Cornerstone/internal/pkg/redis"
 	"Cornerstone/internal/pkg/util"
 	"Cornerstone/internal/repository"
@@ -27,8 +28,17 @@ const MaxOffsetLimit = 10000
 
 type PostService interface {
 	RecommendPost(ctx context.Context, sessionID string, cursor string, pageSize int) (*dto.PostWaterfallDTO, error)
-	SearchPost(ctx context.Context, keyword string, page, pageSize int) (*dto.PostWaterfallDTO, error)
-	Suggestion(ctx context.Context, keyword string) ([]string, error)
+	SearchPost(ctx context.Context, req *dto.PostSearchDTO) (*dto.PostWaterfallDTO, error)
+	Suggestion(ctx context.Context, userID uint64, keyword string) ([]*dto.SearchSuggestionDTO, error)
+	GetSearchHistory(ctx context.Context, userID uint64) ([]string, error)
+	DeleteSearchHistory(ctx context.Context, userID uint64, keyword string) error
+	GetHotSearches(ctx context.Context) ([]*dto.HotSearchDTO, error)
+	GetHotSearchesAdmin(ctx context.Context) (*dto.HotSearchAdminDTO, error)
+	PinHotSearch(ctx context.Context, keyword string, position int) error
+	UnpinHotSearch(ctx context.Context, keyword string) error
+	SuppressHotSearch(ctx context.Context, keyword string) error
+	UnsuppressHotSearch(ctx context.Context, keyword string) error
+	DecayHotSearches(ctx context.Context) error
 	SearchPostMe(ctx context.Context, userID uint64, keyword string, page, pageSize int) (*dto.PostWaterfallDTO, error)
 	LastestPost(ctx context.Context, page, pageSize int) (*dto.PostWaterfallDTO, error)
 	CreatePost(ctx context.Context, userID uint64, postDTO *dto.PostBaseDTO) error
@@ -44,19 +54,34 @@ type PostService interface {
 	UpdatePostContent(ctx context.Context, userID uint64, postID uint64, postDTO *dto.PostBaseDTO) error
 	UpdatePostCounts(ctx context.Context, pid uint64, likes int64, comments int64, collects int64, views int64) error
 	DeletePost(ctx context.Context, userID uint64, postID uint64) error
+	ReportRecommendDwell(ctx context.Context, userID uint64, postID uint64, dwellMs int) error
+	DebugRecommendScore(ctx context.Context, userID uint64, postID uint64) (*dto.RecommendDebugDTO, error)
 }
 
 type postServiceImpl struct {
-	postESRepo       es.PostRepo
-	postDBRepo       repository.PostRepo
-	userInterestRepo repository.UserInterestRepo
+	postESRepo         es.PostRepo
+	postDBRepo         repository.PostRepo
+	userInterestRepo   repository.UserInterestRepo
+	recommendEventRepo repository.RecommendEventRepo
+	userFollowRepo     repository.UserFollowRepo
+	discussionSvc      DiscussionSummaryService
 }
 
-func NewPostService(postESRepo es.PostRepo, postDBRepo repository.PostRepo, userInterestRepo repository.UserInterestRepo) PostService {
+func NewPostService(
+	postESRepo es.PostRepo,
+	postDBRepo repository.PostRepo,
+	userInterestRepo repository.UserInterestRepo,
+	recommendEventRepo repository.RecommendEventRepo,
+	userFollowRepo repository.UserFollowRepo,
+	discussionSvc DiscussionSummaryService,
+) PostService {
 	return &postServiceImpl{
-		postESRepo:       postESRepo,
-		postDBRepo:       postDBRepo,
-		userInterestRepo: userInterestRepo,
+		postESRepo:         postESRepo,
+		postDBRepo:         postDBRepo,
+		userInterestRepo:   userInterestRepo,
+		recommendEventRepo: recommendEventRepo,
+		userFollowRepo:     userFollowRepo,
+		discussionSvc:      discussionSvc,
 	}
 }
 
@@ -109,6 +134,8 @@ func (s *postServiceImpl) RecommendPost(ctx context.Context, sessionID string, c
 	}
 	// 生成随机种子
 	seed := util.HashSessionID(sessionID)
+	// 实验分桶
+	assign := recommend.Assign(userID, sessionID)
 	var vector []float32
 	var interestText string
 	if len(tags) > 0 {
@@ -125,13 +152,14 @@ func (s *postServiceImpl) RecommendPost(ctx context.Context, sessionID string, c
 	}
 
 	fetchSize := pageSize * 2
+	exclude := recommendExclusion(ctx, userID)
 	var candidates []*es.PostES
 	isFallbackMode := false
 	if len(lastSortValues) == 1 {
 		isFallbackMode = true
 	}
 	if !isFallbackMode {
-		candidates, err = s.postESRepo.RecommendPosts(ctx, interestText, vector, lastSortValues, fetchSize, seed)
+		candidates, err = s.postESRepo.RecommendPosts(ctx, interestText, vector, lastSortValues, fetchSize, seed, &assign.Params, exclude)
 		if err != nil {
 			candidates = []*es.PostES{}
 		}
@@ -140,6 +168,7 @@ func (s *postServiceImpl) RecommendPost(ctx context.Context, sessionID string, c
 	targetSize := pageSize + 1
 	finalPosts := make([]*es.PostES, 0, targetSize)
 	addedMap := make(map[uint64]struct{})
+	fallbackMap := make(map[uint64]struct{})
 	viewedKey := consts.UserViewedKey + strconv.FormatUint(userID, 10)
 	rdb := redis.GetRdbClient()
 
@@ -164,9 +193,9 @@ func (s *postServiceImpl) RecommendPost(ctx context.Context, sessionID string, c
 		var err error
 
 		if isFallbackMode {
-			latestPosts, err = s.postESRepo.GetLatestPostsByCursor(ctx, lastSortValues, needed*2)
+			latestPosts, err = s.postESRepo.GetLatestPostsByCursor(ctx, lastSortValues, needed*2, exclude)
 		} else {
-			latestPosts, err = s.postESRepo.GetLatestPosts(ctx, 0, needed*3)
+			latestPosts, err = s.postESRepo.GetLatestPosts(ctx, 0, needed*3, exclude)
 		}
 
 		if err == nil {
@@ -181,6 +210,7 @@ func (s *postServiceImpl) RecommendPost(ctx context.Context, sessionID string, c
 
 				finalPosts = append(finalPosts, p)
 				addedMap[p.ID] = struct{}{}
+				fallbackMap[p.ID] = struct{}{}
 				if len(finalPosts) >= targetSize {
 					break
 				}
@@ -206,6 +236,12 @@ func (s *postServiceImpl) RecommendPost(ctx context.Context, sessionID string, c
 			pipe.Expire(bgCtx, viewedKey, 72*time.Hour)
 			_, _ = pipe.Exec(bgCtx)
 		}(finalPosts)
+
+		exposedIDs := make([]uint64, 0, len(finalPosts))
+		for _, p := range finalPosts {
+			exposedIDs = append(exposedIDs, p.ID)
+		}
+		recordRecommendExposure(ctx, s.recommendEventRepo, assign, userID, exposedIDs)
 	}
 
 	dtoItems, err := s.batchToPostDTOByES(finalPosts)
@@ -213,6 +249,18 @@ func (s *postServiceImpl) RecommendPost(ctx context.Context, sessionID string, c
 		return nil, err
 	}
 
+	// 推荐理由
+	reasonInput := &recommend.ReasonInput{
+		InterestTags: tags,
+		Following:    s.getFollowingSet(ctx, userID),
+		Params:       assign.Params,
+	}
+	for i, p := range finalPosts {
+		_, reasonInput.Fallback = fallbackMap[p.ID]
+		code, detail := recommend.ResolveReason(p, reasonInput)
+		dtoItems[i].Reason = &dto.RecommendReasonDTO{Code: code, Detail: detail}
+	}
+
 	// 计算 Next Cursor
 	var nextCursor string
 	if len(finalPosts) > 0 {
@@ -223,15 +271,17 @@ func (s *postServiceImpl) RecommendPost(ctx context.Context, sessionID string, c
 	}
 
 	return &dto.PostWaterfallDTO{
-		List:       dtoItems,
-		NextCursor: nextCursor,
-		HasMore:    hasMore,
+		List:         dtoItems,
+		NextCursor:   nextCursor,
+		HasMore:      hasMore,
+		ExperimentID: assign.ExperimentID,
+		Variant:      assign.Variant,
 	}, nil
 }
 
-// SearchPost 搜索流
-func (s *postServiceImpl) SearchPost(ctx context.Context, keyword string, page, pageSize int) (*dto.PostWaterfallDTO, error) {
-	if (page-1)*pageSize >= MaxOffsetLimit {
+// SearchPost 搜索流，支持过滤、排序与聚合
+func (s *postServiceImpl) SearchPost(ctx context.Context, req *dto.PostSearchDTO) (*dto.PostWaterfallDTO, error) {
+	if (req.Page-1)*req.PageSize >= MaxOffsetLimit {
 		return &dto.PostWaterfallDTO{
 			List:       []*dto.PostDTO{},
 			HasMore:    false,
@@ -239,44 +289,47 @@ func (s *postServiceImpl) SearchPost(ctx context.Context, keyword string, page,
 		}, nil
 	}
 
-	vector, err := llm.GetVectorByString(ctx, keyword)
+	filter, err := toSearchFilter(req)
 	if err != nil {
 		return nil, err
 	}
+	userID, _ := ctx.Value("user_id").(uint64)
+	filter.Exclude = shadowExclusion(ctx, userID)
 
-	from := (page - 1) * pageSize
+	var vector []float32
+	if req.Keyword != "" {
+		vector, err = llm.GetVectorByString(ctx, req.Keyword)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return getWaterfallPosts(pageSize,
+	from := (req.Page - 1) * req.PageSize
+
+	res, err := getWaterfallPosts(req.PageSize,
 		func() ([]*es.PostES, error) {
-			return s.postESRepo.HybridSearch(ctx, keyword, vector, from, pageSize+1)
+			return s.postESRepo.FilteredSearch(ctx, req.Keyword, vector, filter, req.Sort, from, req.PageSize+1)
 		},
 		s.batchToPostDTOByES,
 	)
-}
-
-// Suggestion 搜索建议
-func (s *postServiceImpl) Suggestion(ctx context.Context, keyword string) ([]string, error) {
-	keyword = strings.TrimSpace(keyword)
-
-	if keyword == "" {
-		return []string{}, nil
-	}
-
-	esSuggestions, err := s.postESRepo.GetSuggestions(ctx, keyword)
 	if err != nil {
 		return nil, err
 	}
 
-	finalSuggestions := make([]string, 0, len(esSuggestions)+1)
-	finalSuggestions = append(finalSuggestions, keyword)
+	if req.Page == 1 && req.Keyword != "" {
+		go s.recordSearch(context.WithoutCancel(ctx), userID, req.Keyword)
+	}
 
-	for _, sug := range esSuggestions {
-		if !strings.EqualFold(sug, keyword) {
-			finalSuggestions = append(finalSuggestions, sug)
+	if req.Facets && req.Page == 1 {
+		facets, err := s.postESRepo.SearchFacets(ctx, req.Keyword, vector, filter, facetInterval(filter))
+		if err != nil {
+			log.WarnContext(ctx, "search facets failed", "err", err)
+		} else {
+			res.Facets = toSearchFacetsDTO(facets)
 		}
 	}
 
-	return finalSuggestions, nil
+	return res, nil
 }
 
 func (s *postServiceImpl) SearchPostMe(ctx context.Context, userID uint64, keyword string, page, pageSize int) (*dto.PostWaterfallDTO, error) {
@@ -313,10 +366,12 @@ func (s *postServiceImpl) LastestPost(ctx context.Context, page, pageSize int) (
 	}
 
 	from := (page - 1) * pageSize
+	userID, _ := ctx.Value("user_id").(uint64)
+	exclude := shadowExclusion(ctx, userID)
 
 	return getWaterfallPosts(pageSize,
 		func() ([]*es.PostES, error) {
-			return s.postESRepo.GetLatestPosts(ctx, from, pageSize+1)
+			return s.postESRepo.GetLatestPosts(ctx, from, pageSize+1, exclude)
 		},
 		s.batchToPostDTOByES,
 	)
@@ -389,7 +444,14 @@ func (s *postServiceImpl) GetPost(ctx context.Context, userID uint64, PostID uin
 		s.RecordInterest(context.Background(), uid, tags, 1)
 	}(userID, post.AITags)
 
-	return s.toPostDTOByES(post)
+	recordRecommendAction(ctx, s.recommendEventRepo, userID, PostID, model.RecommendEventClick, 0)
+
+	res, err := s.toPostDTOByES(post)
+	if err != nil {
+		return nil, err
+	}
+	res.DiscussionSummary = s.discussionSvc.GetSummary(ctx, PostID)
+	return res, nil
 }
 
 // GetPostByIds 批量获取帖子
@@ -433,9 +495,12 @@ func (s *postServiceImpl) GetPostSelf(ctx context.Context, userId uint64, page,
 
 // GetPostByTag 根据标签获取帖子
 func (s *postServiceImpl) GetPostByTag(ctx context.Context, tag string, isMain bool, page, pageSize int) (*dto.PostWaterfallDTO, error) {
+	userID, _ := ctx.Value("user_id").(uint64)
+	exclude := shadowExclusion(ctx, userID)
+
 	return getWaterfallPosts(pageSize,
 		func() ([]*es.PostES, error) {
-			return s.postESRepo.GetPostByTag(ctx, tag, isMain, page-1, pageSize)
+			return s.postESRepo.GetPostByTag(ctx, tag, isMain, page-1, pageSize, exclude)
 		},
 		s.batchToPostDTOByES,
 	)
@@ -587,6 +652,190 @@ func (s *postServiceImpl) DeletePost(ctx context.Context, userID uint64, postID
 	return nil
 }
 
+// ReportRecommendDwell 上报推荐帖子的停留时长
+func (s *postServiceImpl) ReportRecommendDwell(ctx context.Context, userID uint64, postID uint64, dwellMs int) error {
+	if postID == 0 || dwellMs <= 0 {
+		return ErrParamInvalid
+	}
+	recordRecommendAction(ctx, s.recommendEventRepo, userID, postID, model.RecommendEventDwell, dwellMs)
+	return nil
+}
+
+// DebugRecommendScore 复现用户与帖子在推荐流中的各项打分
+func (s *postServiceImpl) DebugRecommendScore(ctx context.Context, userID uint64, postID uint64) (*dto.RecommendDebugDTO, error) {
+	post, err := s.postESRepo.GetPostById(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+
+	userIDStr := strconv.FormatUint(userID, 10)
+	tags, _ := redis.ZRevRange(ctx, consts.UserInterestKey+userIDStr, 0, 9)
+	if len(tags) == 0 {
+		snapshot, err := s.userInterestRepo.GetUserInterests(ctx, userID)
+		if err == nil && snapshot != nil {
+			tags = topInterestTags(snapshot.Interests, 10)
+		}
+	}
+
+	assign := recommend.Assign(userID, "")
+	res := &dto.RecommendDebugDTO{
+		UserID:       userID,
+		PostID:       postID,
+		ExperimentID: assign.ExperimentID,
+		Variant:      assign.Variant,
+		KnnBoost:     assign.Params.KnnBoost,
+		TextBoost:    assign.Params.TextBoost,
+		RandomWeight: assign.Params.RandomWeight,
+		InterestTags: tags,
+		PostMainTag:  post.MainTag,
+		PostAITags:   post.AITags,
+		MatchedTag:   recommend.MatchInterestTag(post, tags),
+		LikesCount:   post.LikesCount,
+		Trending:     post.LikesCount >= recommend.TrendingLikesThreshold,
+	}
+	if assign.Params.RandomWeight > 0 {
+		res.RandomScoreMax = 1 + assign.Params.RandomWeight
+	}
+
+	following := s.getFollowingSet(ctx, userID)
+	_, res.FollowedAuthor = following[post.UserID]
+	res.Viewed, _ = redis.GetRdbClient().SIsMember(ctx, consts.UserViewedKey+userIDStr, postID).Result()
+
+	if len(tags) > 0 {
+		interestText := strings.Join(tags, " ")
+		res.TextScore, res.TextExplanation, err = s.postESRepo.ExplainRecommendText(ctx, postID, interestText, &assign.Params)
+		if err != nil {
+			log.WarnContext(ctx, "explain recommend text failed", "err", err)
+		}
+
+		vector, err := llm.GetVectorByString(ctx, interestText)
+		if err != nil {
+			log.WarnContext(ctx, "llm get vector failed", "err", err)
+		} else if len(post.ContentVector) > 0 {
+			res.VectorSimilarity = recommend.CosineSimilarity(vector, post.ContentVector)
+			res.KnnScore = recommend.KnnScore(res.VectorSimilarity, assign.Params)
+		}
+	}
+
+	// 以复现出的分数模拟检索结果，推导推荐理由
+	simulated := *post
+	simulated.Score = res.TextScore + res.KnnScore
+	if res.RandomScoreMax > 0 {
+		simulated.Score += 1
+	}
+	if res.TextScore > 0 {
+		simulated.MatchedQueries = []string{es.RecommendTextQueryName}
+	}
+	code, detail := recommend.ResolveReason(&simulated, &recommend.ReasonInput{
+		InterestTags: tags,
+		Following:    following,
+		Params:       assign.Params,
+	})
+	res.Reason = &dto.RecommendReasonDTO{Code: code, Detail: detail}
+
+	return res, nil
+}
+
+// getFollowingSet 获取用户关注列表，优先读取 Redis 缓存
+func (s *postServiceImpl) getFollowingSet(ctx context.Context, userID uint64) map[uint64]struct{} {
+	res := make(map[uint64]struct{})
+	if userID == 0 {
+		return res
+	}
+
+	key := consts.UserFollowingKey + strconv.FormatUint(userID, 10)
+	members, err := redis.ZRevRange(ctx, key, 0, -1)
+	if err == nil && len(members) > 0 {
+		ids, _ := util.StrSliceToUInt64Slice(members)
+		for _, id := range ids {
+			res[id] = struct{}{}
+		}
+		return res
+	}
+
+	follows, err := s.userFollowRepo.GetUserFollowing(ctx, userID, MaxFollowingCount, 0)
+	if err != nil {
+		log.WarnContext(ctx, "get user following failed", "err", err)
+		return res
+	}
+	for _, f := range follows {
+		res[f.FollowingID] = struct{}{}
+	}
+	return res
+}
+
+// toSearchFilter 解析搜索过滤条件，结束日期按整天包含
+func toSearchFilter(req *dto.PostSearchDTO) (*es.SearchFilter, error) {
+	filter := &es.SearchFilter{
+		MainTag:   req.MainTag,
+		AITags:    req.AITags,
+		AuthorID:  req.AuthorID,
+		MediaType: req.MediaType,
+		MinLikes:  req.MinLikes,
+	}
+	if req.StartDate != "" {
+		t, err := time.ParseInLocation(time.DateOnly, req.StartDate, time.Local)
+		if err != nil {
+			return nil, ErrParamInvalid
+		}
+		filter.StartTime = &t
+	}
+	if req.EndDate != "" {
+		t, err := time.ParseInLocation(time.DateOnly, req.EndDate, time.Local)
+		if err != nil {
+			return nil, ErrParamInvalid
+		}
+		t = t.AddDate(0, 0, 1)
+		filter.EndTime = &t
+	}
+	if filter.StartTime != nil && filter.EndTime != nil && !filter.StartTime.Before(*filter.EndTime) {
+		return nil, ErrParamInvalid
+	}
+	return filter, nil
+}
+
+// facetInterval 日期范围不超过两个月时按天聚合，否则按月聚合
+func facetInterval(filter *es.SearchFilter) string {
+	if filter.StartTime != nil && filter.EndTime != nil && filter.EndTime.Sub(*filter.StartTime) <= 62*24*time.Hour {
+		return "day"
+	}
+	return "month"
+}
+
+func toSearchFacetsDTO(facets *es.SearchFacets) *dto.SearchFacetsDTO {
+	convert := func(buckets []es.FacetBucket) []*dto.SearchFacetBucketDTO {
+		res := make([]*dto.SearchFacetBucketDTO, 0, len(buckets))
+		for _, b := range buckets {
+			res = append(res, &dto.SearchFacetBucketDTO{Key: b.Key, Count: b.Count})
+		}
+		return res
+	}
+	return &dto.SearchFacetsDTO{
+		MainTags:   convert(facets.MainTags),
+		AITags:     convert(facets.AITags),
+		MediaTypes: convert(facets.MediaTypes),
+		Dates:      convert(facets.DateHistogram),
+	}
+}
+
+// topInterestTags 按得分取前 n 个兴趣标签
+func topInterestTags(interests model.InterestMap, n int) []string {
+	tags := make([]string, 0, len(interests))
+	for tag := range interests {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return interests[tags[i]] > interests[tags[j]]
+	})
+	if len(tags) > n {
+		tags = tags[:n]
+	}
+	return tags
+}
+
 func (s *postServiceImpl) RecordInterest(ctx context.Context, userID uint64, aiTags []string, actionType int) {
 	if userID == 0 || len(aiTags) == 0 {
 		return
@@ -704,6 +953,13 @@ func (s *postServiceImpl) toPostDTOByES(post *es.PostES) (*dto.PostDTO, error) {
 	}
 	out.Medias = mediaBaseDTO
 
+	if post.Highlight != nil {
+		out.Highlight = &dto.PostHighlightDTO{
+			Title:    post.Highlight.Title,
+			Snippets: post.Highlight.Snippets,
+		}
+	}
+
 	return out, nil
 }
 
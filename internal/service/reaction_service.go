@@ -0,0 +1,276 @@
+package service
+
+import (
+	"Cornerstone/internal/api/config"
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/repository"
+	"context"
+	"errors"
+	log "log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// defaultReactions 未配置表情集合时使用的默认表情
+var defaultReactions = []string{"like", "love", "haha", "wow", "sad", "angry"}
+
+// reactionIncrScript 计数缓存存在时才调整计数并标记待回写，缓存已过期时返回 0，
+// 避免在空 key 上写出只含单个表情的哈希，回写时覆盖其他表情的计数
+var reactionIncrScript = redisv9.NewScript(`
+if redis.call('exists', KEYS[1]) == 0 then
+	return 0
+end
+if ARGV[1] ~= '' then
+	redis.call('hincrby', KEYS[1], ARGV[1], 1)
+end
+if ARGV[2] ~= '' then
+	redis.call('hincrby', KEYS[1], ARGV[2], -1)
+end
+redis.call('expire', KEYS[1], ARGV[3])
+redis.call('sadd', KEYS[2], ARGV[4])
+return 1
+`)
+
+type ReactionService interface {
+	GetReactionSet() []string
+	React(ctx context.Context, userID uint64, targetType int8, targetID uint64, emoji string) error
+	Unreact(ctx context.Context, userID uint64, targetType int8, targetID uint64) error
+	GetReactionSummaries(ctx context.Context, userID uint64, targetType int8, targetIDs []uint64) map[uint64]*dto.ReactionSummaryDTO
+	GetReactionCounts(ctx context.Context, targetType int8, targetID uint64) (map[string]int64, error)
+	SyncReactionCounts(ctx context.Context, targetType int8, targetID uint64, counts map[string]int64) error
+}
+
+type reactionServiceImpl struct {
+	reactionRepo repository.ReactionRepo
+	actionRepo   repository.PostActionRepo
+	postRepo     repository.PostRepo
+}
+
+func NewReactionService(reactionRepo repository.ReactionRepo, actionRepo repository.PostActionRepo, postRepo repository.PostRepo) ReactionService {
+	return &reactionServiceImpl{
+		reactionRepo: reactionRepo,
+		actionRepo:   actionRepo,
+		postRepo:     postRepo,
+	}
+}
+
+// GetReactionSet 当前可用的表情集合
+func (s *reactionServiceImpl) GetReactionSet() []string {
+	return reactionSet()
+}
+
+// React 对笔记或评论做出表情回应，已回应其他表情时替换
+func (s *reactionServiceImpl) React(ctx context.Context, userID uint64, targetType int8, targetID uint64, emoji string) error {
+	if !isValidReaction(emoji) {
+		return ErrReactionInvalid
+	}
+	if err := s.checkTarget(ctx, targetType, targetID); err != nil {
+		return err
+	}
+	// 先确保计数已加载，避免增量写入不完整的缓存
+	if _, err := s.loadCounts(ctx, targetType, []uint64{targetID}); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	previous, err := s.reactionRepo.SetReaction(ctx, &model.Reaction{
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserID:     userID,
+		Emoji:      emoji,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return err
+	}
+	if previous == emoji {
+		return nil
+	}
+	return s.incrCounts(ctx, targetType, targetID, emoji, previous)
+}
+
+// Unreact 取消表情回应，未回应时直接返回
+func (s *reactionServiceImpl) Unreact(ctx context.Context, userID uint64, targetType int8, targetID uint64) error {
+	if err := s.checkTarget(ctx, targetType, targetID); err != nil {
+		return err
+	}
+	if _, err := s.loadCounts(ctx, targetType, []uint64{targetID}); err != nil {
+		return err
+	}
+
+	previous, err := s.reactionRepo.DeleteReaction(ctx, targetType, targetID, userID)
+	if err != nil || previous == "" {
+		return err
+	}
+	return s.incrCounts(ctx, targetType, targetID, "", previous)
+}
+
+// GetReactionSummaries 批量获取对象的回应汇总及当前用户的回应，读取失败时返回空汇总
+func (s *reactionServiceImpl) GetReactionSummaries(ctx context.Context, userID uint64, targetType int8, targetIDs []uint64) map[uint64]*dto.ReactionSummaryDTO {
+	res := make(map[uint64]*dto.ReactionSummaryDTO, len(targetIDs))
+	for _, id := range targetIDs {
+		res[id] = &dto.ReactionSummaryDTO{Counts: make(map[string]int64)}
+	}
+	if len(targetIDs) == 0 {
+		return res
+	}
+
+	countsMap, err := s.loadCounts(ctx, targetType, targetIDs)
+	if err != nil {
+		log.WarnContext(ctx, "load reaction counts failed", "targetType", targetType, "err", err)
+	}
+	for id, counts := range countsMap {
+		summary := res[id]
+		for emoji, count := range counts {
+			if count > 0 {
+				summary.Counts[emoji] = count
+				summary.Total += count
+			}
+		}
+	}
+
+	if userID > 0 {
+		mine, err := s.reactionRepo.GetUserReactions(ctx, userID, targetType, targetIDs)
+		if err != nil {
+			log.WarnContext(ctx, "get user reactions failed", "userID", userID, "err", err)
+		}
+		for id, emoji := range mine {
+			if summary, ok := res[id]; ok {
+				summary.MyReaction = emoji
+			}
+		}
+	}
+	return res
+}
+
+// GetReactionCounts 读取对象在 Redis 中的回应计数，供定时任务回写
+func (s *reactionServiceImpl) GetReactionCounts(ctx context.Context, targetType int8, targetID uint64) (map[string]int64, error) {
+	countsMap, err := s.loadCounts(ctx, targetType, []uint64{targetID})
+	if err != nil {
+		return nil, err
+	}
+	return countsMap[targetID], nil
+}
+
+func (s *reactionServiceImpl) SyncReactionCounts(ctx context.Context, targetType int8, targetID uint64, counts map[string]int64) error {
+	return s.reactionRepo.SyncReactionCounts(ctx, targetType, targetID, counts)
+}
+
+// loadCounts 批量读取回应计数，缓存缺失时回退落库值并回填，回填时写入全部表情以标记已加载
+func (s *reactionServiceImpl) loadCounts(ctx context.Context, targetType int8, targetIDs []uint64) (map[uint64]map[string]int64, error) {
+	pipe := redis.GetRdbClient().Pipeline()
+	cmds := make([]*redisv9.MapStringStringCmd, len(targetIDs))
+	for i, id := range targetIDs {
+		cmds[i] = pipe.HGetAll(ctx, reactionCountKey(targetType, id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	res := make(map[uint64]map[string]int64, len(targetIDs))
+	var missIDs []uint64
+	for i, id := range targetIDs {
+		values := cmds[i].Val()
+		if len(values) == 0 {
+			missIDs = append(missIDs, id)
+			continue
+		}
+		counts := make(map[string]int64, len(values))
+		for emoji, val := range values {
+			counts[emoji], _ = strconv.ParseInt(val, 10, 64)
+		}
+		res[id] = counts
+	}
+	if len(missIDs) == 0 {
+		return res, nil
+	}
+
+	dbCounts, err := s.reactionRepo.GetReactionCounts(ctx, targetType, missIDs)
+	if err != nil {
+		return res, err
+	}
+	pipe = redis.GetRdbClient().Pipeline()
+	for _, id := range missIDs {
+		counts := dbCounts[id]
+		if counts == nil {
+			counts = make(map[string]int64)
+		}
+		fields := make(map[string]any, len(counts))
+		for _, emoji := range reactionSet() {
+			fields[emoji] = 0
+		}
+		for emoji, count := range counts {
+			fields[emoji] = count
+		}
+		res[id] = counts
+
+		key := reactionCountKey(targetType, id)
+		pipe.HSet(ctx, key, fields)
+		pipe.Expire(ctx, key, cacheExpiration)
+	}
+	_, err = pipe.Exec(ctx)
+	return res, err
+}
+
+// incrCounts 调整回应计数并标记待回写，缓存在加载后过期的，先回源重新加载再调整
+func (s *reactionServiceImpl) incrCounts(ctx context.Context, targetType int8, targetID uint64, added, removed string) error {
+	keys := []string{reactionCountKey(targetType, targetID), consts.ReactionDirtyKey}
+	args := []any{added, removed, int64(cacheExpiration / time.Second), reactionMember(targetType, targetID)}
+	for i := 0; i < 2; i++ {
+		ok, err := reactionIncrScript.Run(ctx, redis.GetRdbClient(), keys, args...).Int()
+		if err != nil || ok == 1 {
+			return err
+		}
+		if _, err = s.loadCounts(ctx, targetType, []uint64{targetID}); err != nil {
+			return err
+		}
+	}
+	return errors.New("reaction count cache missing after reload")
+}
+
+// checkTarget 校验回应对象存在
+func (s *reactionServiceImpl) checkTarget(ctx context.Context, targetType int8, targetID uint64) error {
+	switch targetType {
+	case model.ReactionTargetPost:
+		posts, err := s.postRepo.GetPostByIds(ctx, []uint64{targetID})
+		if err != nil || len(posts) == 0 {
+			return ErrPostNotFound
+		}
+	case model.ReactionTargetComment:
+		comment, err := s.actionRepo.GetCommentByID(ctx, targetID)
+		if err != nil || comment == nil {
+			return ErrPostCommentNotFound
+		}
+	default:
+		return ErrParamInvalid
+	}
+	return nil
+}
+
+// reactionSet 配置的表情集合，未配置时使用默认集合
+func reactionSet() []string {
+	if config.Cfg != nil && len(config.Cfg.Reaction.Emojis) > 0 {
+		return config.Cfg.Reaction.Emojis
+	}
+	return defaultReactions
+}
+
+// isValidReaction 表情是否在可用集合中，笔记、评论与私信共用
+func isValidReaction(emoji string) bool {
+	return slices.Contains(reactionSet(), emoji)
+}
+
+func reactionMember(targetType int8, targetID uint64) string {
+	return strconv.Itoa(int(targetType)) + ":" + strconv.FormatUint(targetID, 10)
+}
+
+func reactionCountKey(targetType int8, targetID uint64) string {
+	return consts.ReactionCountKey + reactionMember(targetType, targetID)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type reactionKey struct {
+	targetType int8
+	targetID   uint64
+	userID     uint64
+}
+
+// fakeReactionRepo counts 为已落库的计数，只在回写时更新
+type fakeReactionRepo struct {
+	reactions map[reactionKey]string
+	counts    map[uint64]map[string]int64
+}
+
+func (f *fakeReactionRepo) SetReaction(_ context.Context, r *model.Reaction) (string, error) {
+	key := reactionKey{r.TargetType, r.TargetID, r.UserID}
+	previous := f.reactions[key]
+	f.reactions[key] = r.Emoji
+	return previous, nil
+}
+
+func (f *fakeReactionRepo) DeleteReaction(_ context.Context, targetType int8, targetID, userID uint64) (string, error) {
+	key := reactionKey{targetType, targetID, userID}
+	previous := f.reactions[key]
+	delete(f.reactions, key)
+	return previous, nil
+}
+
+func (f *fakeReactionRepo) GetUserReactions(_ context.Context, userID uint64, targetType int8, targetIDs []uint64) (map[uint64]string, error) {
+	res := make(map[uint64]string)
+	for _, id := range targetIDs {
+		if emoji, ok := f.reactions[reactionKey{targetType, id, userID}]; ok {
+			res[id] = emoji
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeReactionRepo) GetReactionCounts(_ context.Context, _ int8, targetIDs []uint64) (map[uint64]map[string]int64, error) {
+	res := make(map[uint64]map[string]int64)
+	for _, id := range targetIDs {
+		if counts, ok := f.counts[id]; ok {
+			res[id] = counts
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeReactionRepo) SyncReactionCounts(_ context.Context, _ int8, targetID uint64, counts map[string]int64) error {
+	f.counts[targetID] = counts
+	return nil
+}
+
+const testReactionPostID = 1
+
+func newTestReactionService(t *testing.T) (*reactionServiceImpl, *fakeReactionRepo) {
+	t.Helper()
+	newTestRedis(t)
+	repo := &fakeReactionRepo{
+		reactions: make(map[reactionKey]string),
+		counts:    map[uint64]map[string]int64{testReactionPostID: {"like": 5}},
+	}
+	posts := &fakePostRepo{posts: map[uint64]*model.Post{testReactionPostID: {ID: testReactionPostID}}}
+	return NewReactionService(repo, &fakePostActionRepo{}, posts).(*reactionServiceImpl), repo
+}
+
+func cachedReactionCounts(t *testing.T, targetID uint64) map[string]string {
+	t.Helper()
+	values, err := redis.HGetAll(context.Background(), reactionCountKey(model.ReactionTargetPost, targetID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func assertReactionCounts(t *testing.T, targetID uint64, want map[string]string) {
+	t.Helper()
+	got := cachedReactionCounts(t, targetID)
+	for emoji, count := range want {
+		if got[emoji] != count {
+			t.Fatalf("cached %s = %q, want %q (all: %v)", emoji, got[emoji], count, got)
+		}
+	}
+}
+
+func TestReactUpdatesCachedCounts(t *testing.T) {
+	svc, _ := newTestReactionService(t)
+	ctx := context.Background()
+	const userID = 3
+
+	if err := svc.React(ctx, userID, model.ReactionTargetPost, testReactionPostID, "like"); err != nil {
+		t.Fatal(err)
+	}
+	// 首次加载写入全部表情，未回应过的表情计为 0
+	if got := cachedReactionCounts(t, testReactionPostID); len(got) != len(defaultReactions) {
+		t.Fatalf("cached fields = %v, want every reaction", got)
+	}
+	assertReactionCounts(t, testReactionPostID, map[string]string{"like": "6", "love": "0"})
+
+	dirty, err := redis.GetSet(ctx, consts.ReactionDirtyKey)
+	if err != nil || len(dirty) != 1 || dirty[0] != reactionMember(model.ReactionTargetPost, testReactionPostID) {
+		t.Fatalf("dirty set = %v, err = %v", dirty, err)
+	}
+
+	// 替换表情时旧表情减一、新表情加一，重复回应同一表情不变
+	if err = svc.React(ctx, userID, model.ReactionTargetPost, testReactionPostID, "love"); err != nil {
+		t.Fatal(err)
+	}
+	if err = svc.React(ctx, userID, model.ReactionTargetPost, testReactionPostID, "love"); err != nil {
+		t.Fatal(err)
+	}
+	assertReactionCounts(t, testReactionPostID, map[string]string{"like": "5", "love": "1"})
+
+	if err = svc.Unreact(ctx, userID, model.ReactionTargetPost, testReactionPostID); err != nil {
+		t.Fatal(err)
+	}
+	if err = svc.Unreact(ctx, userID, model.ReactionTargetPost, testReactionPostID); err != nil {
+		t.Fatal(err)
+	}
+	assertReactionCounts(t, testReactionPostID, map[string]string{"like": "5", "love": "0"})
+}
+
+func TestReactionIncrScriptSkipsMissingCache(t *testing.T) {
+	newTestRedis(t)
+	ctx := context.Background()
+
+	keys := []string{reactionCountKey(model.ReactionTargetPost, testReactionPostID), consts.ReactionDirtyKey}
+	args := []any{"like", "", int64(cacheExpiration / time.Second), reactionMember(model.ReactionTargetPost, testReactionPostID)}
+	ok, err := reactionIncrScript.Run(ctx, redis.GetRdbClient(), keys, args...).Int()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok != 0 {
+		t.Fatalf("script result = %d, want 0 for a missing cache", ok)
+	}
+	// 不能在空 key 上写出只含单个表情的哈希
+	if exists, _ := redis.Exists(ctx, keys[0]); exists {
+		t.Fatal("script should not create a partial count hash")
+	}
+	if exists, _ := redis.Exists(ctx, keys[1]); exists {
+		t.Fatal("script should not mark a missing cache dirty")
+	}
+}
+
+func TestIncrCountsReloadsExpiredCache(t *testing.T) {
+	svc, _ := newTestReactionService(t)
+	ctx := context.Background()
+
+	// 缓存在加载后过期：先回源重新加载再调整，不丢失已落库的计数
+	if err := svc.incrCounts(ctx, model.ReactionTargetPost, testReactionPostID, "love", ""); err != nil {
+		t.Fatal(err)
+	}
+	assertReactionCounts(t, testReactionPostID, map[string]string{"like": "5", "love": "1", "haha": "0"})
+}
+
+func TestReactValidation(t *testing.T) {
+	svc, _ := newTestReactionService(t)
+	ctx := context.Background()
+
+	if err := svc.React(ctx, 3, model.ReactionTargetPost, testReactionPostID, "unknown"); !errors.Is(err, ErrReactionInvalid) {
+		t.Fatalf("invalid emoji err = %v, want ErrReactionInvalid", err)
+	}
+	if err := svc.React(ctx, 3, model.ReactionTargetPost, 404, "like"); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("missing post err = %v, want ErrPostNotFound", err)
+	}
+	if err := svc.React(ctx, 3, model.ReactionTargetComment, 404, "like"); !errors.Is(err, ErrPostCommentNotFound) {
+		t.Fatalf("missing comment err = %v, want ErrPostCommentNotFound", err)
+	}
+}
@@ -0,0 +1,408 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/processor"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	log "log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// reauditBatchSize 每批复审条数，批次之间检查暂停并保存进度
+	reauditBatchSize = 20
+	// reauditApplyBatchSize 每批应用的变更条数
+	reauditApplyBatchSize = 100
+	// reauditDefaultConcurrency 默认并发复审数
+	reauditDefaultConcurrency = 2
+	// reauditLockTTL 任务锁有效期，每批处理后续期
+	reauditLockTTL = 5 * time.Minute
+)
+
+type ReauditService interface {
+	CreateCampaign(ctx context.Context, adminID uint64, req *dto.ReauditCreateDTO) (*dto.ReauditCampaignDTO, error)
+	GetCampaigns(ctx context.Context, req *dto.ReauditQueryDTO) (*dto.ReauditCampaignListDTO, error)
+	GetCampaign(ctx context.Context, id uint64) (*dto.ReauditCampaignDTO, error)
+	GetCampaignItems(ctx context.Context, id uint64, req *dto.ReauditItemQueryDTO) (*dto.ReauditItemListDTO, error)
+	PauseCampaign(ctx context.Context, id uint64) error
+	ResumeCampaign(ctx context.Context, id uint64) error
+	ApplyCampaign(ctx context.Context, id uint64) error
+}
+
+type reauditServiceImpl struct {
+	reauditRepo      repository.ReauditRepo
+	contentProcessor processor.ContentLLMProcessor
+}
+
+func NewReauditService(reauditRepo repository.ReauditRepo, contentProcessor processor.ContentLLMProcessor) ReauditService {
+	return &reauditServiceImpl{
+		reauditRepo:      reauditRepo,
+		contentProcessor: contentProcessor,
+	}
+}
+
+// CreateCampaign 创建复审任务并在后台开始预演，预演只记录状态差异，不修改内容
+func (s *reauditServiceImpl) CreateCampaign(ctx context.Context, adminID uint64, req *dto.ReauditCreateDTO) (*dto.ReauditCampaignDTO, error) {
+	if req.StartAt != nil && req.EndAt != nil && !req.EndAt.After(*req.StartAt) {
+		return nil, ErrReauditInvalid
+	}
+	tag := strings.TrimSpace(req.Tag)
+	if tag != "" && req.TargetType != model.ReauditTargetPost {
+		return nil, ErrReauditInvalid
+	}
+	concurrency := req.Concurrency
+	if concurrency == 0 {
+		concurrency = reauditDefaultConcurrency
+	}
+	// 复审与线上审核共用 TextSem/ImageSem，至少为线上流量保留一个并发
+	if limit := int(llm.TextWeight) - 1; concurrency > limit {
+		concurrency = limit
+	}
+
+	c := &model.ReauditCampaign{
+		TargetType:    req.TargetType,
+		Tag:           tag,
+		ContentStatus: req.ContentStatus,
+		StartAt:       req.StartAt,
+		EndAt:         req.EndAt,
+		MaxItems:      req.MaxItems,
+		Concurrency:   concurrency,
+		State:         model.ReauditRunning,
+		Remark:        strings.TrimSpace(req.Remark),
+		CreatedBy:     adminID,
+	}
+	if err := s.reauditRepo.CreateCampaign(ctx, c); err != nil {
+		return nil, err
+	}
+
+	go s.runCampaign(context.WithoutCancel(ctx), c.ID)
+	return toReauditCampaignDTO(c), nil
+}
+
+func (s *reauditServiceImpl) GetCampaigns(ctx context.Context, req *dto.ReauditQueryDTO) (*dto.ReauditCampaignListDTO, error) {
+	list, total, err := s.reauditRepo.GetCampaigns(ctx, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.ReauditCampaignDTO, 0, len(list))
+	for _, c := range list {
+		res = append(res, toReauditCampaignDTO(c))
+	}
+	return &dto.ReauditCampaignListDTO{List: res, Total: total}, nil
+}
+
+// GetCampaign 获取任务详情及预演差异汇总
+func (s *reauditServiceImpl) GetCampaign(ctx context.Context, id uint64) (*dto.ReauditCampaignDTO, error) {
+	c, err := s.getCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := s.reauditRepo.GetTransitions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toReauditCampaignDTO(c)
+	res.Transitions = make([]*dto.ReauditTransitionDTO, 0, len(transitions))
+	for _, t := range transitions {
+		res.Transitions = append(res.Transitions, &dto.ReauditTransitionDTO{
+			OldStatus: t.OldStatus,
+			NewStatus: t.NewStatus,
+			Count:     t.Count,
+		})
+	}
+	return res, nil
+}
+
+// GetCampaignItems 分页获取状态发生变化的内容
+func (s *reauditServiceImpl) GetCampaignItems(ctx context.Context, id uint64, req *dto.ReauditItemQueryDTO) (*dto.ReauditItemListDTO, error) {
+	if _, err := s.getCampaign(ctx, id); err != nil {
+		return nil, err
+	}
+	list, total, err := s.reauditRepo.GetItems(ctx, id, req.ApplyState, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.ReauditItemDTO, 0, len(list))
+	for _, item := range list {
+		res = append(res, &dto.ReauditItemDTO{
+			ID:         item.ID,
+			TargetID:   item.TargetID,
+			OldStatus:  item.OldStatus,
+			NewStatus:  item.NewStatus,
+			Reason:     item.Reason,
+			ApplyState: item.ApplyState,
+			CreatedAt:  item.CreatedAt,
+		})
+	}
+	return &dto.ReauditItemListDTO{List: res, Total: total}, nil
+}
+
+// PauseCampaign 暂停预演，当前批次处理完后停止
+func (s *reauditServiceImpl) PauseCampaign(ctx context.Context, id uint64) error {
+	if _, err := s.getCampaign(ctx, id); err != nil {
+		return err
+	}
+	ok, err := s.reauditRepo.TransitState(ctx, id, []int8{model.ReauditRunning}, model.ReauditPaused)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReauditStateInvalid
+	}
+	return nil
+}
+
+// ResumeCampaign 从上次的扫描游标继续预演；服务重启后遗留的运行中任务也可通过该接口重新拉起
+func (s *reauditServiceImpl) ResumeCampaign(ctx context.Context, id uint64) error {
+	if _, err := s.getCampaign(ctx, id); err != nil {
+		return err
+	}
+	from := []int8{model.ReauditRunning, model.ReauditPaused, model.ReauditFailed}
+	ok, err := s.reauditRepo.TransitState(ctx, id, from, model.ReauditRunning)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReauditStateInvalid
+	}
+
+	go s.runCampaign(context.WithoutCancel(ctx), id)
+	return nil
+}
+
+// ApplyCampaign 确认预演结果，在后台将状态变更写回内容
+func (s *reauditServiceImpl) ApplyCampaign(ctx context.Context, id uint64) error {
+	if _, err := s.getCampaign(ctx, id); err != nil {
+		return err
+	}
+	ok, err := s.reauditRepo.TransitState(ctx, id, []int8{model.ReauditCompleted, model.ReauditPaused}, model.ReauditApplying)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReauditStateInvalid
+	}
+
+	go s.applyCampaign(context.WithoutCancel(ctx), id)
+	return nil
+}
+
+func (s *reauditServiceImpl) getCampaign(ctx context.Context, id uint64) (*model.ReauditCampaign, error) {
+	c, err := s.reauditRepo.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrReauditNotFound
+	}
+	return c, nil
+}
+
+// runCampaign 分批扫描并复审，每批结束后保存游标；任务被暂停或达到条数上限时退出
+func (s *reauditServiceImpl) runCampaign(ctx context.Context, id uint64) {
+	lockKey := consts.ReauditLock + strconv.FormatUint(id, 10)
+	lockValue := uuid.NewString()
+	ok, err := redis.TryLock(ctx, lockKey, lockValue, reauditLockTTL, 0)
+	if err != nil || !ok {
+		log.WarnContext(ctx, "reaudit campaign already running", "campaignId", id, "err", err)
+		return
+	}
+	defer redis.UnLock(ctx, lockKey, lockValue)
+
+	for {
+		c, err := s.reauditRepo.GetCampaign(ctx, id)
+		if err != nil {
+			s.failCampaign(ctx, id, err)
+			return
+		}
+		if c == nil || c.State != model.ReauditRunning {
+			return
+		}
+
+		limit := reauditBatchSize
+		if c.MaxItems > 0 {
+			limit = min(limit, c.MaxItems-c.Scanned)
+		}
+		var subjects []*model.ReauditSubject
+		if limit > 0 {
+			subjects, err = s.reauditRepo.ScanSubjects(ctx, c, c.CursorID, limit)
+			if err != nil {
+				s.failCampaign(ctx, id, err)
+				return
+			}
+		}
+		if len(subjects) == 0 {
+			if err = s.reauditRepo.FinishCampaign(ctx, id, model.ReauditCompleted, ""); err != nil {
+				log.ErrorContext(ctx, "finish reaudit campaign failed", "campaignId", id, "err", err)
+			}
+			log.InfoContext(ctx, "reaudit campaign completed", "campaignId", id, "scanned", c.Scanned, "changed", c.Changed)
+			return
+		}
+
+		items, failed := s.auditBatch(ctx, c, subjects)
+		if err = s.reauditRepo.CreateItems(ctx, items); err != nil {
+			s.failCampaign(ctx, id, err)
+			return
+		}
+		lastID := subjects[len(subjects)-1].ID
+		if err = s.reauditRepo.SaveProgress(ctx, id, lastID, len(subjects), len(items), failed); err != nil {
+			s.failCampaign(ctx, id, err)
+			return
+		}
+		_ = redis.Expire(ctx, lockKey, reauditLockTTL)
+	}
+}
+
+// auditBatch 以任务并发度复审一批内容，返回状态发生变化的条目与失败数；
+// 模型调用经由 ContentLLMProcessor 受 TextSem/ImageSem 全局限流
+func (s *reauditServiceImpl) auditBatch(ctx context.Context, c *model.ReauditCampaign, subjects []*model.ReauditSubject) ([]*model.ReauditItem, int) {
+	var (
+		mu     sync.Mutex
+		failed int32
+		items  = make([]*model.ReauditItem, 0)
+	)
+
+	g := new(errgroup.Group)
+	g.SetLimit(c.Concurrency)
+	for _, sub := range subjects {
+		g.Go(func() error {
+			media := make([]*es.PostMediaES, 0, len(sub.Media))
+			for _, m := range sub.Media {
+				media = append(media, &es.PostMediaES{Type: m.MimeType, URL: m.MediaURL, Cover: m.CoverURL})
+			}
+
+			r, err := s.contentProcessor.Process(ctx, sub.Title, sub.Content, media, true)
+			if err != nil {
+				atomic.AddInt32(&failed, 1)
+				log.WarnContext(ctx, "reaudit item failed", "campaignId", c.ID, "targetId", sub.ID, "err", err)
+				return nil
+			}
+
+			// 无可审核内容时模型未给出结论，保持原状态
+			newStatus := int8(atomic.LoadInt32(&r.MaxStatus))
+			if newStatus == 0 || newStatus == sub.Status {
+				return nil
+			}
+			item := &model.ReauditItem{
+				CampaignID: c.ID,
+				TargetID:   sub.ID,
+				OldStatus:  sub.Status,
+				NewStatus:  newStatus,
+				Reason:     util.TruncateRunes(verdictReason(r, newStatus), 200),
+			}
+			mu.Lock()
+			items = append(items, item)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return items, int(failed)
+}
+
+// applyCampaign 逐批写回状态变更，内容状态在预演后已被修改的条目跳过
+func (s *reauditServiceImpl) applyCampaign(ctx context.Context, id uint64) {
+	lockKey := consts.ReauditLock + strconv.FormatUint(id, 10)
+	lockValue := uuid.NewString()
+	// 暂停后直接应用时，等待预演协程处理完当前批次并释放锁
+	ok, err := redis.TryLock(ctx, lockKey, lockValue, reauditLockTTL, -1)
+	if err != nil || !ok {
+		s.failCampaign(ctx, id, err)
+		return
+	}
+	defer redis.UnLock(ctx, lockKey, lockValue)
+
+	c, err := s.reauditRepo.GetCampaign(ctx, id)
+	if err != nil || c == nil {
+		s.failCampaign(ctx, id, err)
+		return
+	}
+
+	var lastID uint64
+	for {
+		items, err := s.reauditRepo.GetPendingItems(ctx, id, lastID, reauditApplyBatchSize)
+		if err != nil {
+			s.failCampaign(ctx, id, err)
+			return
+		}
+		if len(items) == 0 {
+			break
+		}
+		lastID = items[len(items)-1].ID
+
+		for _, item := range items {
+			if _, err = s.reauditRepo.ApplyItem(ctx, c.TargetType, item); err != nil {
+				s.failCampaign(ctx, id, err)
+				return
+			}
+		}
+		_ = redis.Expire(ctx, lockKey, reauditLockTTL)
+	}
+
+	if err = s.reauditRepo.FinishCampaign(ctx, id, model.ReauditApplied, ""); err != nil {
+		log.ErrorContext(ctx, "finish reaudit apply failed", "campaignId", id, "err", err)
+	}
+	log.InfoContext(ctx, "reaudit campaign applied", "campaignId", id)
+}
+
+func (s *reauditServiceImpl) failCampaign(ctx context.Context, id uint64, err error) {
+	msg := "campaign not found"
+	if err != nil {
+		msg = util.TruncateRunes(err.Error(), 500)
+	}
+	log.ErrorContext(ctx, "reaudit campaign failed", "campaignId", id, "err", err)
+	if finishErr := s.reauditRepo.FinishCampaign(ctx, id, model.ReauditFailed, msg); finishErr != nil {
+		log.ErrorContext(ctx, "mark reaudit campaign failed error", "campaignId", id, "err", finishErr)
+	}
+}
+
+// verdictReason 取与最终状态一致的首条模型原因
+func verdictReason(r *processor.Result, status int8) string {
+	r.Lock()
+	defer r.Unlock()
+	for _, v := range r.Verdicts {
+		if int8(v.Response.Status) == status && v.Response.Reason != "" {
+			return v.Response.Reason
+		}
+	}
+	return ""
+}
+
+func toReauditCampaignDTO(c *model.ReauditCampaign) *dto.ReauditCampaignDTO {
+	return &dto.ReauditCampaignDTO{
+		ID:            c.ID,
+		TargetType:    c.TargetType,
+		Tag:           c.Tag,
+		ContentStatus: c.ContentStatus,
+		StartAt:       c.StartAt,
+		EndAt:         c.EndAt,
+		MaxItems:      c.MaxItems,
+		Concurrency:   c.Concurrency,
+		State:         c.State,
+		Scanned:       c.Scanned,
+		Changed:       c.Changed,
+		Failed:        c.Failed,
+		Applied:       c.Applied,
+		Remark:        c.Remark,
+		Error:         c.Error,
+		CreatedBy:     c.CreatedBy,
+		CreatedAt:     c.CreatedAt,
+		FinishedAt:    c.FinishedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/recommend"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/repository"
+	"context"
+	log "log/slog"
+	"strconv"
+	"time"
+)
+
+// recordRecommendExposure 异步记录推荐流曝光事件
+func recordRecommendExposure(ctx context.Context, repo repository.RecommendEventRepo, assign *recommend.Assignment, userID uint64, postIDs []uint64) {
+	if repo == nil || userID == 0 || len(postIDs) == 0 {
+		return
+	}
+	now := time.Now()
+	events := make([]*model.RecommendEvent, 0, len(postIDs))
+	for _, id := range postIDs {
+		events = append(events, &model.RecommendEvent{
+			ExperimentID: assign.ExperimentID,
+			Variant:      assign.Variant,
+			UserID:       userID,
+			PostID:       id,
+			EventType:    model.RecommendEventExposure,
+			CreatedAt:    now,
+		})
+	}
+	go func() {
+		bgCtx := context.WithoutCancel(ctx)
+		if err := repo.BatchCreateEvents(bgCtx, events); err != nil {
+			log.WarnContext(bgCtx, "record recommend exposure failed", "err", err)
+		}
+	}()
+}
+
+// recordRecommendAction 帖子曾在推荐流中曝光给该用户时，按用户所在分组记录行为事件
+func recordRecommendAction(ctx context.Context, repo repository.RecommendEventRepo, userID, postID uint64, eventType int8, dwellMs int) {
+	if repo == nil || userID == 0 {
+		return
+	}
+	go func() {
+		bgCtx := context.WithoutCancel(ctx)
+		userIDStr := strconv.FormatUint(userID, 10)
+		postIDStr := strconv.FormatUint(postID, 10)
+		rdb := redis.GetRdbClient()
+
+		exposed, err := rdb.SIsMember(bgCtx, consts.UserViewedKey+userIDStr, postID).Result()
+		if err != nil || !exposed {
+			return
+		}
+
+		// 点击只记录一次
+		if eventType == model.RecommendEventClick {
+			ok, err := rdb.SetNX(bgCtx, consts.RecommendClickKey+userIDStr+":"+postIDStr, 1, 72*time.Hour).Result()
+			if err != nil || !ok {
+				return
+			}
+		}
+
+		assign := recommend.Assign(userID, "")
+		err = repo.BatchCreateEvents(bgCtx, []*model.RecommendEvent{{
+			ExperimentID: assign.ExperimentID,
+			Variant:      assign.Variant,
+			UserID:       userID,
+			PostID:       postID,
+			EventType:    eventType,
+			DwellMs:      dwellMs,
+			CreatedAt:    time.Now(),
+		}})
+		if err != nil {
+			log.WarnContext(bgCtx, "record recommend action failed", "err", err, "type", eventType)
+		}
+	}()
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"Cornerstone/internal/pkg/redis"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// newTestRedis 以内存 Redis 替换全局客户端，测试结束后恢复
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redisv9.NewClient(&redisv9.Options{Addr: mr.Addr()})
+
+	prev := redis.Rdb
+	redis.Rdb = client
+	t.Cleanup(func() {
+		redis.Rdb = prev
+		_ = client.Close()
+	})
+	return mr
+}
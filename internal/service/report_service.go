@@ -0,0 +1,400 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/mongo"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	log "log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	mongoDB "go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// reportAutoTakedownCount 笔记或评论累计举报人数达到该值时先行隐藏，等待人工复核
+	reportAutoTakedownCount = 50
+	// reportClaimTTL 认领超时时间，超时未处理的工单可被其他审核员重新认领
+	reportClaimTTL = 30 * time.Minute
+	// reportSnapshotLen 工单内容快照长度
+	reportSnapshotLen = 300
+)
+
+var reportTargetNames = map[int8]string{
+	model.ReportTargetPost:    "笔记",
+	model.ReportTargetComment: "评论",
+	model.ReportTargetUser:    "用户",
+	model.ReportTargetMessage: "私信",
+}
+
+type ReportService interface {
+	CreateReport(ctx context.Context, userID uint64, req *dto.ReportCreateDTO) error
+	GetReportQueue(ctx context.Context, auditorID uint64, req *dto.ReportQueueQueryDTO) (*dto.ReportCaseListDTO, error)
+	GetReportCase(ctx context.Context, caseID uint64) (*dto.ReportCaseDTO, error)
+	ClaimReportCase(ctx context.Context, auditorID, caseID uint64) error
+	ResolveReportCase(ctx context.Context, auditorID, caseID uint64, req *dto.ReportHandleDTO) error
+	DismissReportCase(ctx context.Context, auditorID, caseID uint64, req *dto.ReportHandleDTO) error
+}
+
+type reportServiceImpl struct {
+	reportRepo       repository.ReportRepo
+	postRepo         repository.PostRepo
+	postActionRepo   repository.PostActionRepo
+	userRepo         repository.UserRepo
+	userRolesRepo    repository.UserRolesRepo
+	conversationRepo repository.ConversationRepo
+	messageRepo      mongo.MessageRepo
+	sysBoxRepo       mongo.SysBoxRepo
+	mediaHashService MediaHashService
+	strikeService    StrikeService
+}
+
+func NewReportService(
+	reportRepo repository.ReportRepo,
+	postRepo repository.PostRepo,
+	postActionRepo repository.PostActionRepo,
+	userRepo repository.UserRepo,
+	userRolesRepo repository.UserRolesRepo,
+	conversationRepo repository.ConversationRepo,
+	messageRepo mongo.MessageRepo,
+	sysBoxRepo mongo.SysBoxRepo,
+	mediaHashService MediaHashService,
+	strikeService StrikeService,
+) ReportService {
+	return &reportServiceImpl{
+		reportRepo:       reportRepo,
+		postRepo:         postRepo,
+		postActionRepo:   postActionRepo,
+		userRepo:         userRepo,
+		userRolesRepo:    userRolesRepo,
+		conversationRepo: conversationRepo,
+		messageRepo:      messageRepo,
+		sysBoxRepo:       sysBoxRepo,
+		mediaHashService: mediaHashService,
+		strikeService:    strikeService,
+	}
+}
+
+// CreateReport 提交举报，同一对象未结案的举报合并为一个工单
+func (s *reportServiceImpl) CreateReport(ctx context.Context, userID uint64, req *dto.ReportCreateDTO) error {
+	reportCase, err := s.buildReportCase(ctx, userID, req)
+	if err != nil {
+		return err
+	}
+	if reportCase.OwnerID == userID {
+		return ErrReportSelf
+	}
+
+	res, err := s.reportRepo.CreateReport(ctx, reportCase, &model.Report{
+		ReporterID: userID,
+		Reason:     req.Reason,
+		Detail:     strings.TrimSpace(req.Detail),
+	})
+	if err != nil {
+		if isDuplicateError(err) {
+			return ErrActionDuplicate
+		}
+		return err
+	}
+
+	if res.ReportCount == reportAutoTakedownCount {
+		if err = s.takedownContent(ctx, res); err != nil {
+			log.ErrorContext(ctx, "report auto takedown failed", "caseId", res.ID, "err", err)
+		}
+	}
+	return nil
+}
+
+// GetReportQueue 获取举报工单队列，按优先级降序、创建时间升序
+func (s *reportServiceImpl) GetReportQueue(ctx context.Context, auditorID uint64, req *dto.ReportQueueQueryDTO) (*dto.ReportCaseListDTO, error) {
+	statuses := []int8{model.ReportCasePending, model.ReportCaseClaimed}
+	if req.Status != nil {
+		statuses = []int8{*req.Status}
+	}
+	var claimedBy uint64
+	if req.Mine {
+		claimedBy = auditorID
+	}
+
+	list, total, err := s.reportRepo.GetReportCases(ctx, statuses, req.TargetType, claimedBy, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.ReportCaseDTO, 0, len(list))
+	for _, c := range list {
+		res = append(res, toReportCaseDTO(c))
+	}
+	return &dto.ReportCaseListDTO{List: res, Total: total}, nil
+}
+
+// GetReportCase 获取工单详情及举报明细
+func (s *reportServiceImpl) GetReportCase(ctx context.Context, caseID uint64) (*dto.ReportCaseDTO, error) {
+	c, err := s.reportRepo.GetReportCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrReportCaseNotFound
+	}
+
+	res := toReportCaseDTO(c)
+	res.ReasonCounts = make(map[int8]int)
+	res.Reports = make([]*dto.ReportItemDTO, 0, len(c.Reports))
+	for _, r := range c.Reports {
+		res.ReasonCounts[r.Reason]++
+		res.Reports = append(res.Reports, &dto.ReportItemDTO{
+			ReporterID: r.ReporterID,
+			Reason:     r.Reason,
+			Detail:     r.Detail,
+			CreatedAt:  r.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return res, nil
+}
+
+// ClaimReportCase 认领工单
+func (s *reportServiceImpl) ClaimReportCase(ctx context.Context, auditorID, caseID uint64) error {
+	ok, err := s.reportRepo.ClaimReportCase(ctx, caseID, auditorID, time.Now().Add(-reportClaimTTL))
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+
+	c, err := s.reportRepo.GetReportCase(ctx, caseID)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return ErrReportCaseNotFound
+	}
+	return ErrReportCaseClaimed
+}
+
+// ResolveReportCase 举报成立，可选下架内容或封禁用户，并通知举报人
+func (s *reportServiceImpl) ResolveReportCase(ctx context.Context, auditorID, caseID uint64, req *dto.ReportHandleDTO) error {
+	c, err := s.getClaimedCase(ctx, auditorID, caseID)
+	if err != nil {
+		return err
+	}
+
+	if req.Takedown {
+		if err = s.takedownContent(ctx, c); err != nil {
+			return err
+		}
+	}
+	if err = s.closeReportCase(ctx, auditorID, c, model.ReportCaseResolved, req.Note); err != nil {
+		return err
+	}
+	if req.Takedown && (c.TargetType == model.ReportTargetPost || c.TargetType == model.ReportTargetComment) {
+		s.mediaHashService.BlockConfirmedMedia(ctx, auditorID, c.TargetType, c.TargetID, req.MediaURLs)
+	}
+	reason := fmt.Sprintf("被举报的%s经核实违规", reportTargetNames[c.TargetType])
+	if err = s.strikeService.AddStrike(ctx, c.OwnerID, model.StrikeSourceReport, c.ID, reason); err != nil {
+		log.ErrorContext(ctx, "add report strike failed", "caseId", c.ID, "ownerId", c.OwnerID, "err", err)
+	}
+
+	s.notifyReporters(ctx, c, fmt.Sprintf("你举报的%s经核实存在违规，已按社区规范处理，感谢你的反馈。", reportTargetNames[c.TargetType]))
+	return nil
+}
+
+// DismissReportCase 驳回举报并通知举报人
+func (s *reportServiceImpl) DismissReportCase(ctx context.Context, auditorID, caseID uint64, req *dto.ReportHandleDTO) error {
+	c, err := s.getClaimedCase(ctx, auditorID, caseID)
+	if err != nil {
+		return err
+	}
+	if err = s.closeReportCase(ctx, auditorID, c, model.ReportCaseDismissed, req.Note); err != nil {
+		return err
+	}
+
+	s.notifyReporters(ctx, c, fmt.Sprintf("你举报的%s经核实暂未发现违规，感谢你的反馈。", reportTargetNames[c.TargetType]))
+	return nil
+}
+
+// getClaimedCase 获取当前审核员已认领的工单
+func (s *reportServiceImpl) getClaimedCase(ctx context.Context, auditorID, caseID uint64) (*model.ReportCase, error) {
+	c, err := s.reportRepo.GetReportCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrReportCaseNotFound
+	}
+	if c.Status != model.ReportCaseClaimed || c.ClaimedBy != auditorID {
+		return nil, ErrReportCaseNotClaimed
+	}
+	return c, nil
+}
+
+// closeReportCase 结案，认领关系在此期间发生变化时返回错误
+func (s *reportServiceImpl) closeReportCase(ctx context.Context, auditorID uint64, c *model.ReportCase, status int8, note string) error {
+	ok, err := s.reportRepo.CloseReportCase(ctx, c.ID, auditorID, status, strings.TrimSpace(note))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReportCaseNotClaimed
+	}
+	c.Status = status
+	return nil
+}
+
+// buildReportCase 校验举报对象并生成工单快照
+func (s *reportServiceImpl) buildReportCase(ctx context.Context, userID uint64, req *dto.ReportCreateDTO) (*model.ReportCase, error) {
+	c := &model.ReportCase{
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Status:     model.ReportCasePending,
+	}
+
+	switch req.TargetType {
+	case model.ReportTargetPost:
+		post, err := s.postRepo.GetPost(ctx, req.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if post == nil {
+			return nil, ErrPostNotFound
+		}
+		c.OwnerID = post.UserID
+		c.Snapshot = util.TruncateRunes(post.Title+"\n"+post.Content, reportSnapshotLen)
+
+	case model.ReportTargetComment:
+		comment, err := s.postActionRepo.GetCommentByID(ctx, req.TargetID)
+		if err != nil || comment == nil || comment.Status != CommentStatusApproved {
+			return nil, ErrPostCommentNotFound
+		}
+		c.OwnerID = comment.UserID
+		c.Snapshot = util.TruncateRunes(comment.Content, reportSnapshotLen)
+
+	case model.ReportTargetUser:
+		user, err := s.userRepo.GetUserHomeInfoById(ctx, req.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, ErrUserNotFound
+		}
+		snapshot := user.Nickname
+		if user.Bio != nil && *user.Bio != "" {
+			snapshot += "\n" + *user.Bio
+		}
+		c.OwnerID = req.TargetID
+		c.Snapshot = util.TruncateRunes(snapshot, reportSnapshotLen)
+
+	case model.ReportTargetMessage:
+		if req.TargetSeq == 0 {
+			return nil, ErrParamInvalid
+		}
+		isMember, err := s.conversationRepo.IsMember(ctx, req.TargetID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrConversation
+		}
+		msg, err := s.messageRepo.GetMessageBySeq(ctx, req.TargetID, req.TargetSeq)
+		if err != nil {
+			if errors.Is(err, mongoDB.ErrNoDocuments) {
+				return nil, ErrParamInvalid
+			}
+			return nil, err
+		}
+		c.TargetSeq = req.TargetSeq
+		c.OwnerID = msg.SenderID
+		c.Snapshot = util.TruncateRunes(msg.Content, reportSnapshotLen)
+
+	default:
+		return nil, ErrParamInvalid
+	}
+	return c, nil
+}
+
+// takedownContent 下架被举报的笔记、评论，或封禁被举报用户；私信仅记录不处理
+func (s *reportServiceImpl) takedownContent(ctx context.Context, c *model.ReportCase) error {
+	switch c.TargetType {
+	case model.ReportTargetPost:
+		return s.postRepo.UpdatePostStatus(ctx, c.TargetID, llm.ContentSafeDeny)
+	case model.ReportTargetComment:
+		return s.postActionRepo.UpdateCommentStatus(ctx, c.TargetID, llm.ContentSafeDeny)
+	case model.ReportTargetUser:
+		roles, err := s.userRolesRepo.GetUserRoles(ctx, c.TargetID)
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			if strings.ToUpper(role.Name) == "ADMIN" {
+				return ErrUserBanAdmin
+			}
+		}
+		if _, err = s.userRepo.UpdateUserIsBan(ctx, c.TargetID, true); err != nil {
+			return err
+		}
+		if err = s.strikeService.RecordManualBan(ctx, c.TargetID, "被举报经核实违规"); err != nil {
+			return err
+		}
+		key := consts.UserAuthVersionKey + strconv.FormatUint(c.TargetID, 10)
+		return redis.SetWithExpiration(ctx, key, time.Now().Unix(), 7*24*time.Hour)
+	}
+	return nil
+}
+
+// notifyReporters 向工单内全部举报人发送处理结果通知
+func (s *reportServiceImpl) notifyReporters(ctx context.Context, c *model.ReportCase, content string) {
+	for _, r := range c.Reports {
+		err := sendSystemNotice(ctx, s.sysBoxRepo, &mongo.SysBoxModel{
+			ReceiverID: r.ReporterID,
+			Type:       mongo.SysBoxTypeReportResult,
+			TargetID:   c.ID,
+			Content:    content,
+			Payload: map[string]any{
+				"case_id":     c.ID,
+				"target_type": c.TargetType,
+				"target_id":   c.TargetID,
+				"target_seq":  c.TargetSeq,
+				"result":      c.Status,
+				"reason":      r.Reason,
+			},
+		})
+		if err != nil {
+			log.WarnContext(ctx, "send report result notice failed", "caseId", c.ID, "reporterId", r.ReporterID, "err", err)
+		}
+	}
+}
+
+func toReportCaseDTO(c *model.ReportCase) *dto.ReportCaseDTO {
+	res := &dto.ReportCaseDTO{
+		ID:          c.ID,
+		TargetType:  c.TargetType,
+		TargetID:    c.TargetID,
+		TargetSeq:   c.TargetSeq,
+		OwnerID:     c.OwnerID,
+		Snapshot:    c.Snapshot,
+		ReportCount: c.ReportCount,
+		Priority:    c.Priority,
+		Status:      c.Status,
+		ClaimedBy:   c.ClaimedBy,
+		HandledBy:   c.HandledBy,
+		HandleNote:  c.HandleNote,
+		CreatedAt:   c.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if c.ClaimedAt != nil {
+		res.ClaimedAt = c.ClaimedAt.UTC().Format(time.RFC3339)
+	}
+	if c.HandledAt != nil {
+		res.HandledAt = c.HandledAt.UTC().Format(time.RFC3339)
+	}
+	return res
+}
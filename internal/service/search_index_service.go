@@ -0,0 +1,526 @@
+package service
+
+import (
+	"Cornerstone/internal/api/config"
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	log "log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+)
+
+// 索引重建目标
+const (
+	ReindexTargetPosts = "posts"
+	ReindexTargetUsers = "users"
+)
+
+// 索引重建状态
+const (
+	ReindexStatusRunning   = "running"
+	ReindexStatusSucceeded = "succeeded"
+	ReindexStatusFailed    = "failed"
+)
+
+const (
+	// reindexBatchSize 每批回填数量
+	reindexBatchSize = 200
+	// reindexLockTTL 重建任务锁的过期时间，每写入一批续期一次，进程崩溃后锁很快释放
+	reindexLockTTL = 2 * time.Minute
+	// reindexProgressTTL 重建进度保留时间
+	reindexProgressTTL = 7 * 24 * time.Hour
+)
+
+type SearchIndexService interface {
+	StartReindex(ctx context.Context, target string) (*dto.ReindexProgressDTO, error)
+	GetReindexProgress(ctx context.Context, target string) (*dto.ReindexProgressDTO, error)
+	StartReconcile(ctx context.Context) (*dto.ReconcileReportDTO, error)
+	Reconcile(ctx context.Context) (*dto.ReconcileReportDTO, error)
+	GetReconcileReport(ctx context.Context) (*dto.ReconcileReportDTO, error)
+}
+
+// PostRedriver 将笔记重新投递到笔记处理流水线，由 Kafka 层实现
+type PostRedriver interface {
+	RedrivePost(ctx context.Context, post *model.Post) error
+}
+
+type searchIndexServiceImpl struct {
+	indexManager es.IndexManager
+	postESRepo   es.PostRepo
+	userESRepo   es.UserRepo
+	postDBRepo   repository.PostRepo
+	userDBRepo   repository.UserRepo
+	postRedriver PostRedriver
+}
+
+func NewSearchIndexService(indexManager es.IndexManager, postESRepo es.PostRepo, userESRepo es.UserRepo, postDBRepo repository.PostRepo, userDBRepo repository.UserRepo, postRedriver PostRedriver) SearchIndexService {
+	return &searchIndexServiceImpl{
+		indexManager: indexManager,
+		postESRepo:   postESRepo,
+		userESRepo:   userESRepo,
+		postDBRepo:   postDBRepo,
+		userDBRepo:   userDBRepo,
+		postRedriver: postRedriver,
+	}
+}
+
+// reindexTask 一次索引重建任务的运行状态
+type reindexTask struct {
+	progress   *dto.ReindexProgressDTO
+	oldIsAlias bool
+	lockValue  string
+}
+
+// StartReindex 创建新版本索引并在后台回填，完成后原子切换别名；同一目标同时只允许一个任务
+func (s *searchIndexServiceImpl) StartReindex(ctx context.Context, target string) (*dto.ReindexProgressDTO, error) {
+	alias, mappingFile, err := reindexTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	lockValue := uuid.NewString()
+	ok, err := redis.GetRdbClient().SetNX(ctx, consts.ESReindexLock+target, lockValue, reindexLockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReindexRunning
+	}
+
+	task, err := s.prepareReindex(ctx, target, alias, mappingFile)
+	if err != nil {
+		redis.UnLock(ctx, consts.ESReindexLock+target, lockValue)
+		return nil, err
+	}
+	task.lockValue = lockValue
+
+	go s.runReindex(context.WithoutCancel(ctx), task)
+
+	return task.progress, nil
+}
+
+// GetReindexProgress 获取最近一次重建任务的进度
+func (s *searchIndexServiceImpl) GetReindexProgress(ctx context.Context, target string) (*dto.ReindexProgressDTO, error) {
+	if _, _, err := reindexTarget(target); err != nil {
+		return nil, err
+	}
+	val, err := redis.GetValue(ctx, consts.ESReindexProgressKey+target)
+	if err != nil {
+		return nil, err
+	}
+	if val == "" {
+		return nil, nil
+	}
+	var progress dto.ReindexProgressDTO
+	if err = json.Unmarshal([]byte(val), &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// prepareReindex 解析当前索引、按 mapping 文件创建新版本索引，并判断是否需要重新向量化
+func (s *searchIndexServiceImpl) prepareReindex(ctx context.Context, target, alias, mappingFile string) (*reindexTask, error) {
+	mappingDir := config.Cfg.Elastic.MappingDir
+	if mappingDir == "" {
+		mappingDir = "migrations"
+	}
+	mapping, err := os.ReadFile(filepath.Join(mappingDir, mappingFile))
+	if err != nil {
+		return nil, err
+	}
+
+	oldIndex, oldIsAlias, err := s.indexManager.ResolveAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	vectorField := reindexVectorField(target)
+	oldDims, err := s.indexManager.GetVectorDims(ctx, oldIndex, vectorField)
+	if err != nil {
+		return nil, err
+	}
+	newDims, err := mappingVectorDims(mapping, vectorField)
+	if err != nil {
+		return nil, err
+	}
+	reembed := oldDims != newDims
+
+	newIndex, err := s.indexManager.CreateVersionedIndex(ctx, alias, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reindexTask{
+		progress: &dto.ReindexProgressDTO{
+			Target:    target,
+			Alias:     alias,
+			OldIndex:  oldIndex,
+			NewIndex:  newIndex,
+			Status:    ReindexStatusRunning,
+			Reembed:   reembed,
+			StartedAt: time.Now().Format(time.RFC3339),
+		},
+		oldIsAlias: oldIsAlias,
+	}, nil
+}
+
+// runReindex 开启双写 -> 回填 -> 刷新 -> 切换别名 -> 关闭双写
+func (s *searchIndexServiceImpl) runReindex(ctx context.Context, task *reindexTask) {
+	p := task.progress
+	defer redis.UnLock(ctx, consts.ESReindexLock+p.Target, task.lockValue)
+
+	err := s.executeReindex(ctx, task)
+
+	if clearErr := s.indexManager.ClearShadowIndex(ctx, p.Alias); clearErr != nil {
+		log.ErrorContext(ctx, "clear shadow index failed", "alias", p.Alias, "err", clearErr)
+	}
+
+	p.FinishedAt = time.Now().Format(time.RFC3339)
+	if err != nil {
+		p.Status = ReindexStatusFailed
+		p.Error = err.Error()
+		log.ErrorContext(ctx, "reindex failed", "target", p.Target, "new_index", p.NewIndex, "err", err)
+	} else {
+		p.Status = ReindexStatusSucceeded
+		log.InfoContext(ctx, "reindex succeeded", "target", p.Target, "old_index", p.OldIndex, "new_index", p.NewIndex)
+	}
+	s.saveProgress(ctx, p)
+}
+
+func (s *searchIndexServiceImpl) executeReindex(ctx context.Context, task *reindexTask) error {
+	p := task.progress
+	if err := s.indexManager.SetShadowIndex(ctx, p.Alias, p.NewIndex); err != nil {
+		return err
+	}
+	s.saveProgress(ctx, p)
+
+	// 等待所有写入方的影子索引缓存过期，确保回填开始后的写入都已双写
+	time.Sleep(es.ShadowRefreshInterval * 2)
+
+	var err error
+	switch p.Target {
+	case ReindexTargetPosts:
+		err = s.backfillPosts(ctx, task)
+	case ReindexTargetUsers:
+		err = s.backfillUsers(ctx, task)
+	}
+	if err != nil {
+		return err
+	}
+	// 有文档未写入新索引时不切换，旧索引继续提供服务，避免这些文档从搜索中消失
+	if p.Failed > 0 {
+		return fmt.Errorf("%d documents failed to backfill, alias not swapped", p.Failed)
+	}
+
+	if err = s.indexManager.RefreshIndex(ctx, p.NewIndex); err != nil {
+		return err
+	}
+	if err = s.indexManager.SwapAlias(ctx, p.Alias, p.OldIndex, p.NewIndex, task.oldIsAlias); err != nil {
+		return err
+	}
+
+	// 切换成功后清理被替换及此前重建失败的版本索引，清理失败不影响本次重建结果
+	deleted, err := s.indexManager.DeleteStaleIndices(ctx, p.Alias, p.NewIndex)
+	if err != nil {
+		log.WarnContext(ctx, "delete stale indices failed", "alias", p.Alias, "err", err)
+	} else if len(deleted) > 0 {
+		log.InfoContext(ctx, "stale indices deleted", "alias", p.Alias, "indices", deleted)
+	}
+	return nil
+}
+
+// backfillPosts 以 MySQL 为准回填帖子；AI 标签、摘要与向量从旧索引复制，维度变化时重新向量化。
+// 旧索引中不存在的帖子（审核中或漏同步）跳过，由 Kafka 或对账任务补齐
+func (s *searchIndexServiceImpl) backfillPosts(ctx context.Context, task *reindexTask) error {
+	p := task.progress
+	total, err := s.postDBRepo.CountAllPosts(ctx)
+	if err != nil {
+		return err
+	}
+	p.Total = total
+
+	var lastID uint64
+	for {
+		posts, err := s.postDBRepo.ScanPosts(ctx, lastID, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return nil
+		}
+		lastID = posts[len(posts)-1].ID
+
+		ids := make([]uint64, 0, len(posts))
+		userIDs := make([]uint64, 0, len(posts))
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+			userIDs = append(userIDs, post.UserID)
+		}
+
+		oldDocs, err := s.indexManager.MGet(ctx, p.OldIndex, ids)
+		if err != nil {
+			return err
+		}
+		users, err := s.userDBRepo.GetUserSimpleInfoByIds(ctx, userIDs)
+		if err != nil {
+			return err
+		}
+		userMap := make(map[uint64]*model.UserDetail, len(users))
+		for _, u := range users {
+			userMap[u.UserID] = u
+		}
+
+		docs := make([]*es.BulkDoc, 0, len(posts))
+		for _, post := range posts {
+			p.Processed++
+			old, ok := oldDocs[post.ID]
+			if post.IsDeleted || !ok {
+				p.Skipped++
+				continue
+			}
+
+			doc, err := buildReindexPost(post, old, userMap[post.UserID])
+			if err != nil {
+				p.Failed++
+				log.WarnContext(ctx, "build reindex post failed", "post_id", post.ID, "err", err)
+				continue
+			}
+			if p.Reembed && len(doc.ContentVector) > 0 {
+				vector, err := llm.GetVector(ctx, &llm.Content{Title: &doc.Title, Content: doc.PlainContent}, doc.AITags, doc.AISummary)
+				if err != nil {
+					p.Failed++
+					continue
+				}
+				doc.ContentVector = vector
+				p.Reembedded++
+			}
+			docs = append(docs, &es.BulkDoc{ID: post.ID, Version: old.Version, Doc: doc})
+		}
+
+		if err = s.flushBatch(ctx, task, docs); err != nil {
+			return err
+		}
+	}
+}
+
+// backfillUsers 以 MySQL 为准回填用户，沿用旧索引的外部版本号，旧索引不存在时以更新时间为版本
+func (s *searchIndexServiceImpl) backfillUsers(ctx context.Context, task *reindexTask) error {
+	p := task.progress
+	total, err := s.userDBRepo.CountAllUsers(ctx)
+	if err != nil {
+		return err
+	}
+	p.Total = total
+
+	var lastID uint64
+	for {
+		users, err := s.userDBRepo.ScanUsers(ctx, lastID, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		lastID = users[len(users)-1].ID
+
+		ids := make([]uint64, 0, len(users))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		oldDocs, err := s.indexManager.MGet(ctx, p.OldIndex, ids)
+		if err != nil {
+			return err
+		}
+
+		docs := make([]*es.BulkDoc, 0, len(users))
+		for _, u := range users {
+			p.Processed++
+			if u.IsDelete {
+				p.Skipped++
+				continue
+			}
+			doc := buildReindexUser(u)
+			version := u.UpdatedAt.UnixMilli()
+			if old, ok := oldDocs[u.ID]; ok && old.Version > 0 {
+				version = old.Version
+				if !p.Reembed {
+					copyCreatorProfile(doc, old)
+				}
+			}
+			// 创作者向量维度变化时由画像任务在切换后重新生成
+			if p.Reembed {
+				_ = redis.SAdd(ctx, consts.UserCreatorDirtyKey, u.ID)
+				p.Reembedded++
+			}
+			docs = append(docs, &es.BulkDoc{ID: u.ID, Version: version, Doc: doc})
+		}
+
+		if err = s.flushBatch(ctx, task, docs); err != nil {
+			return err
+		}
+	}
+}
+
+// flushBatch 写入一批文档，续期任务锁与双写标记并保存进度
+func (s *searchIndexServiceImpl) flushBatch(ctx context.Context, task *reindexTask, docs []*es.BulkDoc) error {
+	p := task.progress
+	// 锁已失效说明可能有新任务接管，立即中止避免两个任务交替写入
+	ok, err := redis.RenewLock(ctx, consts.ESReindexLock+p.Target, task.lockValue, reindexLockTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReindexLockLost
+	}
+
+	failed, err := s.indexManager.BulkIndex(ctx, p.NewIndex, docs)
+	if err != nil {
+		return err
+	}
+	p.Failed += int64(failed)
+	p.Indexed += int64(len(docs) - failed)
+
+	if err = s.indexManager.SetShadowIndex(ctx, p.Alias, p.NewIndex); err != nil {
+		return err
+	}
+	if p.Total > 0 {
+		p.Percent = float64(p.Processed) * 100 / float64(p.Total)
+	}
+	s.saveProgress(ctx, p)
+	return nil
+}
+
+func (s *searchIndexServiceImpl) saveProgress(ctx context.Context, p *dto.ReindexProgressDTO) {
+	bs, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+	if err = redis.SetWithExpiration(ctx, consts.ESReindexProgressKey+p.Target, string(bs), reindexProgressTTL); err != nil {
+		log.WarnContext(ctx, "save reindex progress failed", "target", p.Target, "err", err)
+	}
+}
+
+// buildReindexPost 以旧索引文档为基础，用 MySQL 数据覆盖内容、状态、计数与作者信息
+func buildReindexPost(post *model.Post, old *es.VersionedDoc, user *model.UserDetail) (*es.PostES, error) {
+	doc := &es.PostES{}
+	if err := json.Unmarshal(old.Source, doc); err != nil {
+		return nil, err
+	}
+
+	media := make([]es.PostMediaES, 0, len(post.MediaList))
+	for _, m := range post.MediaList {
+		media = append(media, es.PostMediaES{
+			Type:     m.MimeType,
+			URL:      m.MediaURL,
+			Cover:    m.CoverURL,
+			Width:    m.Width,
+			Height:   m.Height,
+			Duration: m.Duration,
+		})
+	}
+
+	doc.ID = post.ID
+	doc.UserID = post.UserID
+	doc.Status = int(post.Status)
+	doc.Title = post.Title
+	doc.Content = post.Content
+	doc.PlainContent = post.PlainContent
+	doc.Media = media
+	doc.MediaTypes = es.BuildMediaTypes(media)
+	doc.LikesCount = post.LikesCount
+	doc.CommentsCount = post.CommentsCount
+	doc.CollectsCount = post.CollectsCount
+	doc.CreatedAt = post.CreatedAt
+	doc.UpdatedAt = post.UpdatedAt
+	if user != nil {
+		doc.UserNickname = user.Nickname
+		doc.UserAvatar = user.AvatarURL
+	}
+	doc.FillNormalized()
+	return doc, nil
+}
+
+func buildReindexUser(u *model.User) *es.UserES {
+	d := u.UserDetail
+	doc := &es.UserES{
+		ID:             u.ID,
+		Nickname:       d.Nickname,
+		Bio:            d.Bio,
+		AvatarURL:      d.AvatarURL,
+		FollowersCount: int(d.FollowersCount),
+		FollowingCount: int(d.FollowingCount),
+	}
+	if d.Gender != nil {
+		doc.Gender = int(*d.Gender)
+	}
+	if d.Region != nil {
+		doc.Region = *d.Region
+	}
+	if d.Birthday != nil {
+		if t, err := time.Parse(time.DateOnly, *d.Birthday); err == nil {
+			doc.Birthday = t
+		}
+	}
+	doc.FillNormalized()
+	return doc
+}
+
+func reindexTarget(target string) (string, string, error) {
+	switch target {
+	case ReindexTargetPosts:
+		return es.PostIndex, es.PostMappingFile, nil
+	case ReindexTargetUsers:
+		return es.UserIndex, es.UserMappingFile, nil
+	default:
+		return "", "", ErrParamInvalid
+	}
+}
+
+// reindexVectorField 各索引中需要随维度变化重新生成的向量字段
+func reindexVectorField(target string) string {
+	if target == ReindexTargetUsers {
+		return "creator_vector"
+	}
+	return "content_vector"
+}
+
+// copyCreatorProfile 沿用旧文档中的创作者画像
+func copyCreatorProfile(doc *es.UserES, old *es.VersionedDoc) {
+	var current es.UserES
+	if err := json.Unmarshal(old.Source, &current); err != nil {
+		return
+	}
+	doc.CreatorTags = current.CreatorTags
+	doc.CreatorVector = current.CreatorVector
+}
+
+// mappingVectorDims 读取 mapping 文件中向量字段的维度
+func mappingVectorDims(mapping []byte, field string) (int, error) {
+	var m struct {
+		Mappings struct {
+			Properties map[string]struct {
+				Dims int `json:"dims"`
+			} `json:"properties"`
+		} `json:"mappings"`
+	}
+	if err := json.Unmarshal(mapping, &m); err != nil {
+		return 0, fmt.Errorf("invalid mapping file: %w", err)
+	}
+	prop, ok := m.Mappings.Properties[field]
+	if !ok {
+		return 0, errors.New("mapping has no " + field)
+	}
+	return prop.Dims, nil
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/llm"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"context"
+	log "log/slog"
+	"slices"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+)
+
+// 差异类型
+const (
+	DriftMissing   = "missing"
+	DriftOrphan    = "orphan"
+	DriftStatus    = "status"
+	DriftCounts    = "counts"
+	DriftNickname  = "nickname"
+	DriftAvatar    = "avatar"
+	DriftContent   = "content"
+	DriftUpdatedAt = "updated_at"
+	DriftPending   = "pending"
+)
+
+// 修复动作
+const (
+	DriftActionIndex   = "index"
+	DriftActionDelete  = "delete"
+	DriftActionSkip    = "skip"
+	DriftActionRedrive = "redrive"
+)
+
+const (
+	// reconcileLockTTL 对账任务锁的最长持有时间
+	reconcileLockTTL = 6 * time.Hour
+	// reconcileGrace 最近更新的笔记可能仍在 Kafka 中处理（含 LLM 审核与向量化），跳过避免与实时写入冲突
+	reconcileGrace = 30 * time.Minute
+	// reconcileSampleSize 报告中保留的差异样本数
+	reconcileSampleSize = 100
+)
+
+// StartReconcile 后台发起一次对账
+func (s *searchIndexServiceImpl) StartReconcile(ctx context.Context) (*dto.ReconcileReportDTO, error) {
+	lockValue := uuid.NewString()
+	ok, err := redis.TryLock(ctx, consts.ESReconcileLock, lockValue, reconcileLockTTL, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReconcileRunning
+	}
+
+	report := newReconcileReport()
+	s.saveReconcileReport(ctx, report)
+	go s.runReconcile(context.WithoutCancel(ctx), report, lockValue)
+
+	return report, nil
+}
+
+// Reconcile 同步执行一次对账，已有任务运行时直接返回
+func (s *searchIndexServiceImpl) Reconcile(ctx context.Context) (*dto.ReconcileReportDTO, error) {
+	lockValue := uuid.NewString()
+	ok, err := redis.TryLock(ctx, consts.ESReconcileLock, lockValue, reconcileLockTTL, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReconcileRunning
+	}
+
+	report := newReconcileReport()
+	s.runReconcile(ctx, report, lockValue)
+	return report, nil
+}
+
+// GetReconcileReport 获取最近一次对账报告
+func (s *searchIndexServiceImpl) GetReconcileReport(ctx context.Context) (*dto.ReconcileReportDTO, error) {
+	val, err := redis.GetValue(ctx, consts.ESReconcileReportKey)
+	if err != nil {
+		return nil, err
+	}
+	if val == "" {
+		return nil, nil
+	}
+	var report dto.ReconcileReportDTO
+	if err = json.Unmarshal([]byte(val), &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (s *searchIndexServiceImpl) runReconcile(ctx context.Context, report *dto.ReconcileReportDTO, lockValue string) {
+	defer redis.UnLock(ctx, consts.ESReconcileLock, lockValue)
+
+	err := s.reconcilePosts(ctx, report)
+	if err == nil {
+		err = s.reconcileUsers(ctx, report)
+	}
+
+	report.FinishedAt = time.Now().Format(time.RFC3339)
+	if err != nil {
+		report.Status = ReindexStatusFailed
+		report.Error = err.Error()
+		log.ErrorContext(ctx, "reconcile failed", "err", err)
+	} else {
+		report.Status = ReindexStatusSucceeded
+	}
+	s.saveReconcileReport(ctx, report)
+
+	if report.Posts.Drifted > 0 || report.Users.Drifted > 0 {
+		log.WarnContext(ctx, "es drift detected",
+			"post_drifted", report.Posts.Drifted, "post_repaired", report.Posts.Repaired, "post_kinds", report.Posts.Kinds,
+			"user_drifted", report.Users.Drifted, "user_repaired", report.Users.Repaired, "user_kinds", report.Users.Kinds)
+	} else {
+		log.InfoContext(ctx, "es reconcile finished without drift",
+			"post_scanned", report.Posts.Scanned, "user_scanned", report.Users.Scanned)
+	}
+}
+
+// reconcilePosts 按 ID 区间遍历笔记并与索引比对。修复沿用 IndexPost 的外部版本语义：
+// 以本批读取 MySQL 前的时间戳为版本号，期间到达的实时写入版本更大，不会被覆盖
+func (s *searchIndexServiceImpl) reconcilePosts(ctx context.Context, report *dto.ReconcileReportDTO) error {
+	r := report.Posts
+	var lastID uint64
+	for {
+		scanAt := time.Now()
+		posts, err := s.postDBRepo.ScanPosts(ctx, lastID, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return s.deleteOrphans(ctx, r, es.PostIndex, lastID, 0, nil, s.postESRepo.DeletePost)
+		}
+
+		ids := make([]uint64, 0, len(posts))
+		userIDs := make([]uint64, 0, len(posts))
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+			userIDs = append(userIDs, post.UserID)
+		}
+
+		oldDocs, err := s.indexManager.MGet(ctx, es.PostIndex, ids)
+		if err != nil {
+			return err
+		}
+		users, err := s.userDBRepo.GetUserSimpleInfoByIds(ctx, userIDs)
+		if err != nil {
+			return err
+		}
+		userMap := make(map[uint64]*model.UserDetail, len(users))
+		for _, u := range users {
+			userMap[u.UserID] = u
+		}
+
+		for _, post := range posts {
+			r.Scanned++
+			old := oldDocs[post.ID]
+			if post.UpdatedAt.After(scanAt.Add(-reconcileGrace)) || (old != nil && old.Version >= scanAt.UnixMilli()) {
+				r.Skipped++
+				continue
+			}
+			s.reconcilePost(ctx, r, post, old, userMap[post.UserID], scanAt.UnixMilli())
+		}
+
+		batchLast := posts[len(posts)-1].ID
+		if err = s.deleteOrphans(ctx, r, es.PostIndex, lastID, batchLast, ids, s.postESRepo.DeletePost); err != nil {
+			return err
+		}
+		lastID = batchLast
+		s.saveReconcileReport(ctx, report)
+	}
+}
+
+func (s *searchIndexServiceImpl) reconcilePost(ctx context.Context, r *dto.ReconcileTargetDTO, post *model.Post, old *es.VersionedDoc, user *model.UserDetail, version int64) {
+	kinds, action, err := classifyPostDrift(post, old, user)
+	switch {
+	case err != nil:
+		recordDrift(r, post.ID, kinds, action, err)
+		return
+	case action == DriftActionDelete:
+		recordDrift(r, post.ID, kinds, action, s.postESRepo.DeletePost(ctx, post.ID))
+		return
+	case action == DriftActionRedrive:
+		recordDrift(r, post.ID, kinds, action, s.postRedriver.RedrivePost(ctx, post))
+		return
+	case action == "":
+		return
+	}
+
+	// 丢失的笔记以空文档为基础按 MySQL 重建，不重新审核，避免推翻人工或申诉结论
+	missing := old == nil
+	if missing {
+		old = &es.VersionedDoc{ID: post.ID, Source: json.RawMessage("{}")}
+	}
+	doc, err := buildReindexPost(post, old, user)
+	if err != nil {
+		recordDrift(r, post.ID, kinds, DriftActionSkip, err)
+		return
+	}
+	if missing {
+		doc.UserTags = util.ExtractTags(doc.PlainContent)
+		if tags, err := s.postDBRepo.GetPostTagNames(ctx, post.ID); err == nil && len(tags) > 0 {
+			doc.MainTag = tags[0]
+		}
+	}
+
+	// 丢失或内容变更的已发布笔记需要重新生成向量，AI 标签与摘要沿用旧文档
+	if (missing || slices.Contains(kinds, DriftContent)) && doc.Status == consts.PostStatusNormal {
+		vector, err := llm.GetVector(ctx, &llm.Content{Title: &doc.Title, Content: doc.PlainContent}, doc.AITags, doc.AISummary)
+		if err != nil {
+			log.WarnContext(ctx, "reconcile post vector failed", "post_id", post.ID, "err", err)
+		} else {
+			doc.ContentVector = vector
+		}
+	}
+
+	err = s.postESRepo.IndexPost(ctx, doc, version)
+	recordDrift(r, post.ID, kinds, DriftActionIndex, err)
+	// 已发布笔记集合变化时刷新作者画像
+	if err == nil && (missing || slices.Contains(kinds, DriftStatus)) {
+		_ = redis.SAdd(ctx, consts.UserCreatorDirtyKey, post.UserID)
+	}
+}
+
+// classifyPostDrift 判定笔记与索引文档的差异及修复动作，无差异时动作为空。
+// 仍待审核的笔记重新投递到 Kafka 完成审核与打标，其余笔记直接按 MySQL 修复索引
+func classifyPostDrift(post *model.Post, old *es.VersionedDoc, user *model.UserDetail) ([]string, string, error) {
+	if post.IsDeleted {
+		if old != nil {
+			return []string{DriftOrphan}, DriftActionDelete, nil
+		}
+		return nil, "", nil
+	}
+	if post.Status == consts.PostStatusPending {
+		if old == nil {
+			return []string{DriftMissing}, DriftActionRedrive, nil
+		}
+		return []string{DriftPending}, DriftActionRedrive, nil
+	}
+	if old == nil {
+		return []string{DriftMissing}, DriftActionIndex, nil
+	}
+
+	current := &es.PostES{}
+	if err := json.Unmarshal(old.Source, current); err != nil {
+		return nil, DriftActionSkip, err
+	}
+	kinds := diffPost(post, current, user)
+	if len(kinds) == 0 {
+		return nil, "", nil
+	}
+	return kinds, DriftActionIndex, nil
+}
+
+// reconcileUsers 按 ID 区间遍历用户并与索引比对
+func (s *searchIndexServiceImpl) reconcileUsers(ctx context.Context, report *dto.ReconcileReportDTO) error {
+	r := report.Users
+	var lastID uint64
+	for {
+		scanAt := time.Now()
+		users, err := s.userDBRepo.ScanUsers(ctx, lastID, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return s.deleteOrphans(ctx, r, es.UserIndex, lastID, 0, nil, s.userESRepo.DeleteUser)
+		}
+
+		ids := make([]uint64, 0, len(users))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		oldDocs, err := s.indexManager.MGet(ctx, es.UserIndex, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			r.Scanned++
+			old := oldDocs[u.ID]
+			if old != nil && old.Version >= scanAt.UnixMilli() {
+				r.Skipped++
+				continue
+			}
+			s.reconcileUser(ctx, r, u, old, scanAt.UnixMilli())
+		}
+
+		batchLast := users[len(users)-1].ID
+		if err = s.deleteOrphans(ctx, r, es.UserIndex, lastID, batchLast, ids, s.userESRepo.DeleteUser); err != nil {
+			return err
+		}
+		lastID = batchLast
+		s.saveReconcileReport(ctx, report)
+	}
+}
+
+func (s *searchIndexServiceImpl) reconcileUser(ctx context.Context, r *dto.ReconcileTargetDTO, u *model.User, old *es.VersionedDoc, version int64) {
+	if u.IsDelete {
+		if old != nil {
+			recordDrift(r, u.ID, []string{DriftOrphan}, DriftActionDelete, s.userESRepo.DeleteUser(ctx, u.ID))
+		}
+		return
+	}
+
+	doc := buildReindexUser(u)
+	var kinds []string
+	if old == nil {
+		kinds = []string{DriftMissing}
+		_ = redis.SAdd(ctx, consts.UserCreatorDirtyKey, u.ID)
+	} else {
+		current := &es.UserES{}
+		if err := json.Unmarshal(old.Source, current); err != nil {
+			recordDrift(r, u.ID, nil, DriftActionSkip, err)
+			return
+		}
+		kinds = diffUser(doc, current)
+		if len(kinds) == 0 {
+			return
+		}
+		doc.CreatorTags = current.CreatorTags
+		doc.CreatorVector = current.CreatorVector
+	}
+
+	recordDrift(r, u.ID, kinds, DriftActionIndex, s.userESRepo.IndexUser(ctx, doc, version))
+}
+
+// deleteOrphans 删除索引中 (afterID, maxID] 区间内 MySQL 不存在的文档，maxID 为 0 时处理剩余全部
+func (s *searchIndexServiceImpl) deleteOrphans(ctx context.Context, r *dto.ReconcileTargetDTO, index string, afterID, maxID uint64, dbIDs []uint64, deleteFn func(context.Context, uint64) error) error {
+	for {
+		esIDs, err := s.indexManager.RangeIDs(ctx, index, afterID, maxID, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, id := range esIDs {
+			if !slices.Contains(dbIDs, id) {
+				recordDrift(r, id, []string{DriftOrphan}, DriftActionDelete, deleteFn(ctx, id))
+			}
+		}
+		if len(esIDs) < reindexBatchSize {
+			return nil
+		}
+		afterID = esIDs[len(esIDs)-1]
+	}
+}
+
+func diffPost(post *model.Post, doc *es.PostES, user *model.UserDetail) []string {
+	var kinds []string
+	if int(post.Status) != doc.Status {
+		kinds = append(kinds, DriftStatus)
+	}
+	if post.LikesCount != doc.LikesCount || post.CommentsCount != doc.CommentsCount || post.CollectsCount != doc.CollectsCount {
+		kinds = append(kinds, DriftCounts)
+	}
+	if user != nil && user.Nickname != doc.UserNickname {
+		kinds = append(kinds, DriftNickname)
+	}
+	if user != nil && user.AvatarURL != doc.UserAvatar {
+		kinds = append(kinds, DriftAvatar)
+	}
+	if post.Title != doc.Title || post.PlainContent != doc.PlainContent {
+		kinds = append(kinds, DriftContent)
+	}
+	if !post.UpdatedAt.Truncate(time.Second).Equal(doc.UpdatedAt.Truncate(time.Second)) {
+		kinds = append(kinds, DriftUpdatedAt)
+	}
+	return kinds
+}
+
+func diffUser(expected, doc *es.UserES) []string {
+	var kinds []string
+	if expected.Nickname != doc.Nickname {
+		kinds = append(kinds, DriftNickname)
+	}
+	if expected.AvatarURL != doc.AvatarURL {
+		kinds = append(kinds, DriftAvatar)
+	}
+	if expected.FollowersCount != doc.FollowersCount || expected.FollowingCount != doc.FollowingCount {
+		kinds = append(kinds, DriftCounts)
+	}
+	return kinds
+}
+
+// recordDrift 记录一条差异及其修复结果
+func recordDrift(r *dto.ReconcileTargetDTO, id uint64, kinds []string, action string, err error) {
+	r.Drifted++
+	for _, kind := range kinds {
+		r.Kinds[kind]++
+	}
+
+	drift := &dto.ReconcileDriftDTO{ID: id, Kinds: kinds, Action: action}
+	switch {
+	case err != nil:
+		r.Failed++
+		drift.Error = err.Error()
+	case action != DriftActionSkip:
+		r.Repaired++
+	}
+	if len(r.Samples) < reconcileSampleSize {
+		r.Samples = append(r.Samples, drift)
+	}
+}
+
+func (s *searchIndexServiceImpl) saveReconcileReport(ctx context.Context, report *dto.ReconcileReportDTO) {
+	bs, err := json.Marshal(report)
+	if err != nil {
+		return
+	}
+	if err = redis.SetWithExpiration(ctx, consts.ESReconcileReportKey, string(bs), reindexProgressTTL); err != nil {
+		log.WarnContext(ctx, "save reconcile report failed", "err", err)
+	}
+}
+
+func newReconcileReport() *dto.ReconcileReportDTO {
+	return &dto.ReconcileReportDTO{
+		Status:    ReindexStatusRunning,
+		Posts:     &dto.ReconcileTargetDTO{Kinds: map[string]int64{}, Samples: []*dto.ReconcileDriftDTO{}},
+		Users:     &dto.ReconcileTargetDTO{Kinds: map[string]int64{}, Samples: []*dto.ReconcileDriftDTO{}},
+		StartedAt: time.Now().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/es"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+func newDriftPost(status int8) *model.Post {
+	updatedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	return &model.Post{
+		ID:            1,
+		UserID:        2,
+		Title:         "标题",
+		PlainContent:  "正文",
+		Status:        status,
+		LikesCount:    3,
+		CommentsCount: 4,
+		CollectsCount: 5,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+// indexedDoc 构造与笔记一致的索引文档，mutate 用于制造差异
+func indexedDoc(t *testing.T, post *model.Post, user *model.UserDetail, mutate func(*es.PostES)) *es.VersionedDoc {
+	t.Helper()
+	doc := &es.PostES{
+		ID:            post.ID,
+		UserID:        post.UserID,
+		Status:        int(post.Status),
+		Title:         post.Title,
+		PlainContent:  post.PlainContent,
+		LikesCount:    post.LikesCount,
+		CommentsCount: post.CommentsCount,
+		CollectsCount: post.CollectsCount,
+		UpdatedAt:     post.UpdatedAt.Add(300 * time.Millisecond),
+		UserNickname:  user.Nickname,
+		UserAvatar:    user.AvatarURL,
+	}
+	if mutate != nil {
+		mutate(doc)
+	}
+	source, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &es.VersionedDoc{ID: post.ID, Version: 1, Source: source}
+}
+
+func TestClassifyPostDrift(t *testing.T) {
+	user := &model.UserDetail{UserID: 2, Nickname: "作者", AvatarURL: "a.png"}
+	normal := newDriftPost(consts.PostStatusNormal)
+	deleted := newDriftPost(consts.PostStatusNormal)
+	deleted.IsDeleted = true
+	pending := newDriftPost(consts.PostStatusPending)
+	denied := newDriftPost(consts.PostStatusDeny)
+
+	cases := []struct {
+		name       string
+		post       *model.Post
+		old        *es.VersionedDoc
+		wantKinds  []string
+		wantAction string
+	}{
+		{"in sync", normal, indexedDoc(t, normal, user, nil), nil, ""},
+		{"deleted and indexed", deleted, indexedDoc(t, deleted, user, nil), []string{DriftOrphan}, DriftActionDelete},
+		{"deleted and absent", deleted, nil, nil, ""},
+		{"pending and absent", pending, nil, []string{DriftMissing}, DriftActionRedrive},
+		{"pending and indexed", pending, indexedDoc(t, pending, user, nil), []string{DriftPending}, DriftActionRedrive},
+		{"published and absent", normal, nil, []string{DriftMissing}, DriftActionIndex},
+		{"denied and absent", denied, nil, []string{DriftMissing}, DriftActionIndex},
+		{"status", denied, indexedDoc(t, denied, user, func(d *es.PostES) { d.Status = int(consts.PostStatusNormal) }), []string{DriftStatus}, DriftActionIndex},
+		{"counts", normal, indexedDoc(t, normal, user, func(d *es.PostES) { d.LikesCount++ }), []string{DriftCounts}, DriftActionIndex},
+		{"author", normal, indexedDoc(t, normal, user, func(d *es.PostES) { d.UserNickname, d.UserAvatar = "旧昵称", "old.png" }), []string{DriftNickname, DriftAvatar}, DriftActionIndex},
+		{"content", normal, indexedDoc(t, normal, user, func(d *es.PostES) { d.Title = "旧标题" }), []string{DriftContent}, DriftActionIndex},
+		{"updated at", normal, indexedDoc(t, normal, user, func(d *es.PostES) { d.UpdatedAt = d.UpdatedAt.Add(-time.Minute) }), []string{DriftUpdatedAt}, DriftActionIndex},
+	}
+	for _, c := range cases {
+		kinds, action, err := classifyPostDrift(c.post, c.old, user)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if !slices.Equal(kinds, c.wantKinds) || action != c.wantAction {
+			t.Errorf("%s: got (%v, %q), want (%v, %q)", c.name, kinds, action, c.wantKinds, c.wantAction)
+		}
+	}
+}
+
+func TestClassifyPostDriftBadSource(t *testing.T) {
+	post := newDriftPost(consts.PostStatusNormal)
+	kinds, action, err := classifyPostDrift(post, &es.VersionedDoc{ID: post.ID, Source: []byte("{")}, nil)
+	if err == nil || action != DriftActionSkip || kinds != nil {
+		t.Fatalf("got (%v, %q, %v), want skip with error", kinds, action, err)
+	}
+}
+
+func TestClassifyPostDriftWithoutUser(t *testing.T) {
+	post := newDriftPost(consts.PostStatusNormal)
+	user := &model.UserDetail{Nickname: "作者", AvatarURL: "a.png"}
+	old := indexedDoc(t, post, user, func(d *es.PostES) { d.UserNickname = "旧昵称" })
+
+	// 作者资料缺失时不判定昵称与头像差异
+	kinds, action, err := classifyPostDrift(post, old, nil)
+	if err != nil || kinds != nil || action != "" {
+		t.Fatalf("got (%v, %q, %v), want no drift", kinds, action, err)
+	}
+}
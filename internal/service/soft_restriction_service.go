@@ -0,0 +1,225 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/repository"
+	"context"
+	log "log/slog"
+	"strconv"
+	"time"
+
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// softRestrictionKeys 各类软处置在 Redis 中的有序集合，成员为对象ID，分值为到期时间戳
+var softRestrictionKeys = map[int8]string{
+	model.SoftRestrictionShadowBan:   consts.ShadowBanUsersKey,
+	model.SoftRestrictionLimitedPost: consts.LimitedPostsKey,
+}
+
+type SoftRestrictionService interface {
+	ShadowBanUser(ctx context.Context, operatorID uint64, userID uint64, req *dto.SoftRestrictionCreateDTO) error
+	LiftShadowBan(ctx context.Context, userID uint64) error
+	LimitPost(ctx context.Context, operatorID uint64, postID uint64, req *dto.SoftRestrictionCreateDTO) error
+	LiftPostLimit(ctx context.Context, postID uint64) error
+	GetRestrictions(ctx context.Context, req *dto.SoftRestrictionQueryDTO) (*dto.SoftRestrictionListDTO, error)
+	SyncCache(ctx context.Context) error
+}
+
+type softRestrictionServiceImpl struct {
+	softRestrictionRepo repository.SoftRestrictionRepo
+	userRepo            repository.UserRepo
+	postRepo            repository.PostRepo
+}
+
+func NewSoftRestrictionService(
+	softRestrictionRepo repository.SoftRestrictionRepo,
+	userRepo repository.UserRepo,
+	postRepo repository.PostRepo,
+) SoftRestrictionService {
+	return &softRestrictionServiceImpl{
+		softRestrictionRepo: softRestrictionRepo,
+		userRepo:            userRepo,
+		postRepo:            postRepo,
+	}
+}
+
+// ShadowBanUser 影子封禁用户，其内容仅本人可见，不通知用户
+func (s *softRestrictionServiceImpl) ShadowBanUser(ctx context.Context, operatorID uint64, userID uint64, req *dto.SoftRestrictionCreateDTO) error {
+	if userID == operatorID {
+		return ErrUserBanSelf
+	}
+	user, err := s.userRepo.GetUserById(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	return s.apply(ctx, operatorID, model.SoftRestrictionShadowBan, userID, req)
+}
+
+func (s *softRestrictionServiceImpl) LiftShadowBan(ctx context.Context, userID uint64) error {
+	return s.lift(ctx, model.SoftRestrictionShadowBan, userID)
+}
+
+// LimitPost 限流笔记，仅从推荐流中排除
+func (s *softRestrictionServiceImpl) LimitPost(ctx context.Context, operatorID uint64, postID uint64, req *dto.SoftRestrictionCreateDTO) error {
+	post, err := s.postRepo.GetPostByAllStatus(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return ErrPostNotFound
+	}
+	return s.apply(ctx, operatorID, model.SoftRestrictionLimitedPost, postID, req)
+}
+
+func (s *softRestrictionServiceImpl) LiftPostLimit(ctx context.Context, postID uint64) error {
+	return s.lift(ctx, model.SoftRestrictionLimitedPost, postID)
+}
+
+// GetRestrictions 审核端分页查看软处置记录
+func (s *softRestrictionServiceImpl) GetRestrictions(ctx context.Context, req *dto.SoftRestrictionQueryDTO) (*dto.SoftRestrictionListDTO, error) {
+	list, total, err := s.softRestrictionRepo.GetRestrictions(ctx, req.Kind, req.Active, time.Now(), req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.SoftRestrictionDTO, 0, len(list))
+	for _, r := range list {
+		item := &dto.SoftRestrictionDTO{
+			ID:        r.ID,
+			Kind:      r.Kind,
+			TargetID:  r.TargetID,
+			Reason:    r.Reason,
+			CreatedBy: r.CreatedBy,
+			ExpiresAt: r.ExpiresAt.UTC().Format(time.RFC3339),
+			CreatedAt: r.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if r.LiftedAt != nil {
+			liftedAt := r.LiftedAt.UTC().Format(time.RFC3339)
+			item.LiftedAt = &liftedAt
+		}
+		res = append(res, item)
+	}
+	return &dto.SoftRestrictionListDTO{List: res, Total: total}, nil
+}
+
+// SyncCache 按数据库重建软处置缓存，清理已到期成员并修正缓存丢失
+func (s *softRestrictionServiceImpl) SyncCache(ctx context.Context) error {
+	now := time.Now()
+	for kind, key := range softRestrictionKeys {
+		list, err := s.softRestrictionRepo.GetAllActiveRestrictions(ctx, kind, now)
+		if err != nil {
+			return err
+		}
+
+		pipe := redis.GetRdbClient().TxPipeline()
+		pipe.Del(ctx, key)
+		for _, r := range list {
+			pipe.ZAdd(ctx, key, redisv9.Z{Score: float64(r.ExpiresAt.Unix()), Member: strconv.FormatUint(r.TargetID, 10)})
+		}
+		if _, err = pipe.Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply 施加软处置，对象已有生效中的处置时改写到期时间
+func (s *softRestrictionServiceImpl) apply(ctx context.Context, operatorID uint64, kind int8, targetID uint64, req *dto.SoftRestrictionCreateDTO) error {
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(req.ExpiresIn) * time.Hour)
+
+	active, err := s.softRestrictionRepo.GetActiveRestriction(ctx, kind, targetID, now)
+	if err != nil {
+		return err
+	}
+	if active != nil {
+		err = s.softRestrictionRepo.ExtendRestriction(ctx, active.ID, expiresAt, req.Reason, operatorID)
+	} else {
+		err = s.softRestrictionRepo.CreateRestriction(ctx, &model.SoftRestriction{
+			Kind:      kind,
+			TargetID:  targetID,
+			Reason:    req.Reason,
+			CreatedBy: operatorID,
+			ExpiresAt: expiresAt,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	log.InfoContext(ctx, "soft restriction applied", "kind", kind, "targetId", targetID, "operatorId", operatorID, "expiresAt", expiresAt)
+	return redis.ZAdd(ctx, softRestrictionKeys[kind], float64(expiresAt.Unix()), strconv.FormatUint(targetID, 10))
+}
+
+func (s *softRestrictionServiceImpl) lift(ctx context.Context, kind int8, targetID uint64) error {
+	ok, err := s.softRestrictionRepo.LiftRestriction(ctx, kind, targetID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSoftRestrictionNotFound
+	}
+	return redis.GetRdbClient().ZRem(ctx, softRestrictionKeys[kind], strconv.FormatUint(targetID, 10)).Err()
+}
+
+// getSoftRestrictedIDs 读取某类生效中的软处置对象，读取失败时不做排除
+func getSoftRestrictedIDs(ctx context.Context, kind int8) []uint64 {
+	members, err := redis.GetRdbClient().ZRangeByScore(ctx, softRestrictionKeys[kind], &redisv9.ZRangeBy{
+		Min: "(" + strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		log.WarnContext(ctx, "get soft restrictions failed", "kind", kind, "err", err)
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(members))
+	for _, m := range members {
+		if id, err := strconv.ParseUint(m, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// getShadowBannedUsers 获取对查看者隐藏的影子封禁用户，查看者本人不受影响
+func getShadowBannedUsers(ctx context.Context, viewerID uint64) []uint64 {
+	ids := getSoftRestrictedIDs(ctx, model.SoftRestrictionShadowBan)
+	res := ids[:0]
+	for _, id := range ids {
+		if id != viewerID {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// isShadowBanned 用户当前是否处于影子封禁
+func isShadowBanned(ctx context.Context, userID uint64) bool {
+	score, err := redis.GetRdbClient().ZScore(ctx, consts.ShadowBanUsersKey, strconv.FormatUint(userID, 10)).Result()
+	if err != nil {
+		return false
+	}
+	return int64(score) > time.Now().Unix()
+}
+
+// shadowExclusion 搜索、最新流与标签页的排除条件
+func shadowExclusion(ctx context.Context, viewerID uint64) *es.Exclusion {
+	return &es.Exclusion{UserIDs: getShadowBannedUsers(ctx, viewerID)}
+}
+
+// recommendExclusion 推荐流额外排除限流笔记
+func recommendExclusion(ctx context.Context, viewerID uint64) *es.Exclusion {
+	return &es.Exclusion{
+		UserIDs: getShadowBannedUsers(ctx, viewerID),
+		PostIDs: getSoftRestrictedIDs(ctx, model.SoftRestrictionLimitedPost),
+	}
+}
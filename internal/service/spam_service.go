@@ -0,0 +1,347 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	"hash/fnv"
+	log "log/slog"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+const (
+	spamLikeWindow        = 5 * time.Minute    // 短时点赞统计窗口
+	spamFollowWindow      = 24 * time.Hour     // 关注后在此时间内取关计为一次反复关注
+	spamCommentWindow     = 24 * time.Hour     // 重复评论与链接评论统计窗口
+	spamNewAccountAge     = 72 * time.Hour     // 注册不足该时长视为新账号
+	spamMinDuplicateRunes = 6                  // 过短的评论（如“好看”）不参与重复判定
+	spamReviewScore       = 50                 // 风险分达到该值进入复核列表
+	spamClearedTTL        = 7 * 24 * time.Hour // 复核放行后的免检时长
+	spamRiskSyncInterval  = time.Minute        // 同一用户风险记录的最小落库间隔
+)
+
+// spamLinkPattern 评论中的外链与域名
+var spamLinkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+|[a-z0-9-]+\.(com|cn|net|org|top|xyz|cc|io|vip|shop)\b`)
+
+// spamSignalRules 各信号在 low 到 high 之间线性计分，达到 high 记满 weight 分，总分封顶 100；
+// 单一信号打满即触发轻度限频，多个信号叠加才会进入重度限频
+var spamSignalRules = []struct {
+	value     func(*model.SpamSignals) int
+	low, high int
+	weight    int
+}{
+	{value: func(s *model.SpamSignals) int { return s.BurstLikes }, low: 20, high: 60, weight: 60},
+	{value: func(s *model.SpamSignals) int { return s.FollowChurn }, low: 10, high: 50, weight: 60},
+	{value: func(s *model.SpamSignals) int { return s.DuplicateComments }, low: 1, high: 6, weight: 60},
+	{value: func(s *model.SpamSignals) int { return s.NewAccountLinks }, low: 0, high: 3, weight: 60},
+}
+
+// spamThrottleRules 风险分对应的限频等级与持续时间，按分值降序匹配
+var spamThrottleRules = []struct {
+	score int
+	level int8
+	ttl   time.Duration
+}{
+	{score: 80, level: model.SpamThrottleHeavy, ttl: 2 * time.Hour},
+	{score: 50, level: model.SpamThrottleLight, ttl: 30 * time.Minute},
+}
+
+type SpamService interface {
+	ObserveLike(ctx context.Context, userID, postID uint64, at time.Time)
+	ObserveFollow(ctx context.Context, followerID, followingID uint64, followed bool, at time.Time)
+	ObserveComment(ctx context.Context, userID, postID, commentID uint64, content string, at time.Time)
+	GetRisks(ctx context.Context, req *dto.SpamRiskQueryDTO) (*dto.SpamRiskListDTO, error)
+	ClearRisk(ctx context.Context, reviewerID, userID uint64) error
+	ConfirmRisk(ctx context.Context, reviewerID, userID uint64, req *dto.SoftRestrictionCreateDTO) error
+}
+
+type spamServiceImpl struct {
+	spamRiskRepo       repository.SpamRiskRepo
+	userRepo           repository.UserRepo
+	softRestrictionSvc SoftRestrictionService
+}
+
+func NewSpamService(
+	spamRiskRepo repository.SpamRiskRepo,
+	userRepo repository.UserRepo,
+	softRestrictionSvc SoftRestrictionService,
+) SpamService {
+	return &spamServiceImpl{
+		spamRiskRepo:       spamRiskRepo,
+		userRepo:           userRepo,
+		softRestrictionSvc: softRestrictionSvc,
+	}
+}
+
+// ObserveLike 记录点赞，同一笔记重复消费只计一次
+func (s *spamServiceImpl) ObserveLike(ctx context.Context, userID, postID uint64, at time.Time) {
+	key := consts.SpamLikeKey + strconv.FormatUint(userID, 10)
+	if err := redis.ZAdd(ctx, key, float64(at.UnixMilli()), strconv.FormatUint(postID, 10)); err != nil {
+		log.WarnContext(ctx, "spam observe like failed", "userId", userID, "err", err)
+		return
+	}
+	s.evaluate(ctx, userID)
+}
+
+// ObserveFollow 记录关注，关注后短时间内取关计为一次反复关注
+func (s *spamServiceImpl) ObserveFollow(ctx context.Context, followerID, followingID uint64, followed bool, at time.Time) {
+	rdb := redis.GetRdbClient()
+	uid := strconv.FormatUint(followerID, 10)
+	followKey := consts.SpamFollowKey + uid
+	target := strconv.FormatUint(followingID, 10)
+
+	if followed {
+		pipe := rdb.Pipeline()
+		pipe.ZAdd(ctx, followKey, redisv9.Z{Score: float64(at.UnixMilli()), Member: target})
+		pipe.ZRemRangeByScore(ctx, followKey, "-inf", strconv.FormatInt(time.Now().Add(-spamFollowWindow).UnixMilli(), 10))
+		pipe.Expire(ctx, followKey, spamFollowWindow)
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.WarnContext(ctx, "spam observe follow failed", "userId", followerID, "err", err)
+		}
+		return
+	}
+
+	followedAt, err := rdb.ZScore(ctx, followKey, target).Result()
+	if err != nil || at.UnixMilli()-int64(followedAt) > spamFollowWindow.Milliseconds() {
+		return
+	}
+	member := target + ":" + strconv.FormatInt(int64(followedAt), 10)
+	if err = redis.ZAdd(ctx, consts.SpamChurnKey+uid, float64(at.UnixMilli()), member); err != nil {
+		log.WarnContext(ctx, "spam observe unfollow failed", "userId", followerID, "err", err)
+		return
+	}
+	s.evaluate(ctx, followerID)
+}
+
+// ObserveComment 记录评论，同一文本出现在多篇笔记下计为重复评论，新账号发布的外链评论单独计数
+func (s *spamServiceImpl) ObserveComment(ctx context.Context, userID, postID, commentID uint64, content string, at time.Time) {
+	uid := strconv.FormatUint(userID, 10)
+	member := strconv.FormatUint(commentID, 10)
+	score := float64(at.UnixMilli())
+	observed := false
+
+	if text := util.FoldForMatch(content); utf8.RuneCountInString(text) >= spamMinDuplicateRunes {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(text))
+		textKey := consts.SpamCommentTextKey + uid + ":" + strconv.FormatUint(h.Sum64(), 16)
+
+		pipe := redis.GetRdbClient().Pipeline()
+		pipe.SAdd(ctx, textKey, postID)
+		pipe.Expire(ctx, textKey, spamCommentWindow)
+		postCount := pipe.SCard(ctx, textKey)
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.WarnContext(ctx, "spam observe comment text failed", "userId", userID, "err", err)
+		} else if postCount.Val() >= 2 {
+			_ = redis.ZAdd(ctx, consts.SpamDuplicateKey+uid, score, member)
+			observed = true
+		}
+	}
+
+	if spamLinkPattern.MatchString(content) && s.isNewAccount(ctx, userID) {
+		_ = redis.ZAdd(ctx, consts.SpamLinkKey+uid, score, member)
+		observed = true
+	}
+
+	if observed {
+		s.evaluate(ctx, userID)
+	}
+}
+
+// GetRisks 审核端分页获取垃圾行为复核列表
+func (s *spamServiceImpl) GetRisks(ctx context.Context, req *dto.SpamRiskQueryDTO) (*dto.SpamRiskListDTO, error) {
+	list, total, err := s.spamRiskRepo.GetRisks(ctx, req.State, req.MinScore, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.SpamRiskDTO, 0, len(list))
+	for _, r := range list {
+		item := &dto.SpamRiskDTO{
+			UserID:            r.UserID,
+			Score:             r.Score,
+			PeakScore:         r.PeakScore,
+			BurstLikes:        r.BurstLikes,
+			FollowChurn:       r.FollowChurn,
+			DuplicateComments: r.DuplicateComments,
+			NewAccountLinks:   r.NewAccountLinks,
+			ThrottleLevel:     r.ThrottleLevel,
+			State:             r.State,
+			ReviewedBy:        r.ReviewedBy,
+			CreatedAt:         r.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt:         r.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if r.ReviewedAt != nil {
+			reviewedAt := r.ReviewedAt.UTC().Format(time.RFC3339)
+			item.ReviewedAt = &reviewedAt
+		}
+		res = append(res, item)
+	}
+	return &dto.SpamRiskListDTO{List: res, Total: total}, nil
+}
+
+// ClearRisk 复核为误判：解除限频并在免检期内不再计分
+func (s *spamServiceImpl) ClearRisk(ctx context.Context, reviewerID, userID uint64) error {
+	ok, err := s.spamRiskRepo.ReviewRisk(ctx, userID, model.SpamRiskCleared, reviewerID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSpamRiskNotFound
+	}
+
+	uid := strconv.FormatUint(userID, 10)
+	_ = redis.DeleteKey(ctx, consts.SpamThrottleKey+uid)
+	return redis.SetWithExpiration(ctx, consts.SpamClearedKey+uid, reviewerID, spamClearedTTL)
+}
+
+// ConfirmRisk 复核为垃圾行为：对用户施加影子封禁，限频继续生效至到期
+func (s *spamServiceImpl) ConfirmRisk(ctx context.Context, reviewerID, userID uint64, req *dto.SoftRestrictionCreateDTO) error {
+	risk, err := s.spamRiskRepo.GetRisk(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if risk == nil || risk.State != model.SpamRiskPending {
+		return ErrSpamRiskNotFound
+	}
+
+	if req.Reason == "" {
+		req.Reason = "垃圾行为"
+	}
+	if err = s.softRestrictionSvc.ShadowBanUser(ctx, reviewerID, userID, req); err != nil {
+		return err
+	}
+	_, err = s.spamRiskRepo.ReviewRisk(ctx, userID, model.SpamRiskConfirmed, reviewerID, time.Now())
+	return err
+}
+
+// evaluate 重新统计用户各信号窗口并计分，按分值限频，超过复核线时落库
+func (s *spamServiceImpl) evaluate(ctx context.Context, userID uint64) {
+	uid := strconv.FormatUint(userID, 10)
+	if cleared, _ := redis.Exists(ctx, consts.SpamClearedKey+uid); cleared {
+		return
+	}
+
+	signals, err := s.collectSignals(ctx, uid)
+	if err != nil {
+		log.WarnContext(ctx, "spam collect signals failed", "userId", userID, "err", err)
+		return
+	}
+	score := spamScore(signals)
+	level := s.throttle(ctx, uid, score)
+	if score < spamReviewScore {
+		return
+	}
+
+	ok, err := redis.GetRdbClient().SetNX(ctx, consts.SpamRiskSyncKey+uid, 1, spamRiskSyncInterval).Result()
+	if err != nil || !ok {
+		return
+	}
+	log.WarnContext(ctx, "spam risk detected", "userId", userID, "score", score, "throttleLevel", level, "signals", signals)
+
+	err = s.spamRiskRepo.UpsertRisk(ctx, &model.SpamRisk{
+		UserID:            userID,
+		Score:             score,
+		PeakScore:         score,
+		BurstLikes:        signals.BurstLikes,
+		FollowChurn:       signals.FollowChurn,
+		DuplicateComments: signals.DuplicateComments,
+		NewAccountLinks:   signals.NewAccountLinks,
+		ThrottleLevel:     level,
+		State:             model.SpamRiskPending,
+	})
+	if err != nil {
+		log.ErrorContext(ctx, "save spam risk failed", "userId", userID, "err", err)
+	}
+}
+
+// collectSignals 清理窗口外的记录并统计窗口内的次数
+func (s *spamServiceImpl) collectSignals(ctx context.Context, uid string) (*model.SpamSignals, error) {
+	now := time.Now()
+	windows := []struct {
+		key    string
+		window time.Duration
+	}{
+		{consts.SpamLikeKey + uid, spamLikeWindow},
+		{consts.SpamChurnKey + uid, spamFollowWindow},
+		{consts.SpamDuplicateKey + uid, spamCommentWindow},
+		{consts.SpamLinkKey + uid, spamCommentWindow},
+	}
+
+	pipe := redis.GetRdbClient().Pipeline()
+	counts := make([]*redisv9.IntCmd, len(windows))
+	for i, w := range windows {
+		cutoff := strconv.FormatInt(now.Add(-w.window).UnixMilli(), 10)
+		pipe.ZRemRangeByScore(ctx, w.key, "-inf", "("+cutoff)
+		counts[i] = pipe.ZCount(ctx, w.key, cutoff, "+inf")
+		pipe.Expire(ctx, w.key, w.window)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return &model.SpamSignals{
+		BurstLikes:        int(counts[0].Val()),
+		FollowChurn:       int(counts[1].Val()),
+		DuplicateComments: int(counts[2].Val()),
+		NewAccountLinks:   int(counts[3].Val()),
+	}, nil
+}
+
+// throttle 按风险分设置限频，已有更高等级时保持不变
+func (s *spamServiceImpl) throttle(ctx context.Context, uid string, score int) int8 {
+	key := consts.SpamThrottleKey + uid
+	current, _ := redis.GetValue(ctx, key)
+	currentLevel, _ := strconv.Atoi(current)
+
+	for _, rule := range spamThrottleRules {
+		if score < rule.score {
+			continue
+		}
+		if int(rule.level) <= currentLevel {
+			return int8(currentLevel)
+		}
+		if err := redis.SetWithExpiration(ctx, key, rule.level, rule.ttl); err != nil {
+			log.WarnContext(ctx, "set spam throttle failed", "userId", uid, "err", err)
+		}
+		return rule.level
+	}
+	return int8(currentLevel)
+}
+
+// isNewAccount 注册时间不足 spamNewAccountAge 的账号
+func (s *spamServiceImpl) isNewAccount(ctx context.Context, userID uint64) bool {
+	user, err := s.userRepo.GetUserById(ctx, userID)
+	if err != nil || user == nil {
+		return false
+	}
+	return time.Since(user.CreatedAt) < spamNewAccountAge
+}
+
+// spamScore 汇总各信号得分
+func spamScore(signals *model.SpamSignals) int {
+	var score float64
+	for _, rule := range spamSignalRules {
+		v := rule.value(signals)
+		if v <= rule.low {
+			continue
+		}
+		ratio := float64(v-rule.low) / float64(rule.high-rule.low)
+		if ratio > 1 {
+			ratio = 1
+		}
+		score += ratio * float64(rule.weight)
+	}
+	if score > 100 {
+		score = 100
+	}
+	return int(score)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/repository"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fakeSpamRiskRepo struct {
+	repository.SpamRiskRepo
+	upserts []*model.SpamRisk
+}
+
+func (f *fakeSpamRiskRepo) UpsertRisk(_ context.Context, risk *model.SpamRisk) error {
+	f.upserts = append(f.upserts, risk)
+	return nil
+}
+
+func newTestSpamService(t *testing.T) (*spamServiceImpl, *fakeSpamRiskRepo) {
+	t.Helper()
+	newTestRedis(t)
+	risks := &fakeSpamRiskRepo{}
+	users := &fakeUserRepo{users: map[uint64]*model.User{}}
+	return NewSpamService(risks, users, nil).(*spamServiceImpl), risks
+}
+
+func throttleLevel(t *testing.T, userID uint64) int {
+	t.Helper()
+	value, _ := redis.GetValue(context.Background(), consts.SpamThrottleKey+strconv.FormatUint(userID, 10))
+	level, _ := strconv.Atoi(value)
+	return level
+}
+
+func TestSpamScore(t *testing.T) {
+	cases := []struct {
+		name    string
+		signals model.SpamSignals
+		want    int
+	}{
+		{"quiet", model.SpamSignals{}, 0},
+		{"below low", model.SpamSignals{BurstLikes: 20, FollowChurn: 10, DuplicateComments: 1}, 0},
+		{"half likes", model.SpamSignals{BurstLikes: 40}, 30},
+		{"likes saturated", model.SpamSignals{BurstLikes: 200}, 60},
+		{"single link", model.SpamSignals{NewAccountLinks: 1}, 20},
+		{"capped", model.SpamSignals{BurstLikes: 60, FollowChurn: 50}, 100},
+	}
+	for _, c := range cases {
+		if got := spamScore(&c.signals); got != c.want {
+			t.Errorf("%s: spamScore = %d, want %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestObserveLikeThrottles(t *testing.T) {
+	svc, risks := newTestSpamService(t)
+	ctx := context.Background()
+	const userID = 9
+	now := time.Now()
+
+	for i := 1; i <= 40; i++ {
+		svc.ObserveLike(ctx, userID, uint64(i), now)
+	}
+	if level := throttleLevel(t, userID); level != 0 {
+		t.Fatalf("throttle after 40 likes = %d, want none", level)
+	}
+
+	// 重复消费同一笔记不重复计数
+	for i := 0; i < 30; i++ {
+		svc.ObserveLike(ctx, userID, 1, now)
+	}
+	if level := throttleLevel(t, userID); level != 0 {
+		t.Fatalf("throttle after duplicate likes = %d, want none", level)
+	}
+
+	for i := 41; i <= 60; i++ {
+		svc.ObserveLike(ctx, userID, uint64(i), now)
+	}
+	if level := throttleLevel(t, userID); level != int(model.SpamThrottleLight) {
+		t.Fatalf("throttle after 60 likes = %d, want light", level)
+	}
+	// 首次越过复核线时落库，落库间隔内不再重复写入
+	if len(risks.upserts) != 1 {
+		t.Fatalf("risk upserts = %d, want 1", len(risks.upserts))
+	}
+	if r := risks.upserts[0]; r.Score < spamReviewScore || r.State != model.SpamRiskPending || r.ThrottleLevel != model.SpamThrottleLight {
+		t.Fatalf("risk = %+v, want pending light-throttled record above review score", r)
+	}
+}
+
+func TestObserveLikeIgnoresOldLikes(t *testing.T) {
+	svc, _ := newTestSpamService(t)
+	ctx := context.Background()
+	const userID = 9
+	old := time.Now().Add(-spamLikeWindow - time.Minute)
+
+	for i := 1; i <= 100; i++ {
+		svc.ObserveLike(ctx, userID, uint64(i), old)
+	}
+	if level := throttleLevel(t, userID); level != 0 {
+		t.Fatalf("throttle for likes outside the window = %d, want none", level)
+	}
+}
+
+func TestSpamThrottleEscalatesAndNeverDowngrades(t *testing.T) {
+	svc, _ := newTestSpamService(t)
+	ctx := context.Background()
+	const userID = 9
+	now := time.Now()
+
+	for i := 1; i <= 60; i++ {
+		svc.ObserveLike(ctx, userID, uint64(i), now)
+	}
+	// 同一文本出现在多篇笔记下，叠加重复评论信号升级为重度限频
+	for i := 1; i <= 6; i++ {
+		svc.ObserveComment(ctx, userID, uint64(i), uint64(100+i), "关注我领取免费福利", now)
+	}
+	if level := throttleLevel(t, userID); level != int(model.SpamThrottleHeavy) {
+		t.Fatalf("throttle = %d, want heavy", level)
+	}
+
+	if level := svc.throttle(ctx, strconv.FormatUint(userID, 10), 55); level != model.SpamThrottleHeavy {
+		t.Fatalf("throttle for a lower score = %d, want heavy kept", level)
+	}
+	if level := throttleLevel(t, userID); level != int(model.SpamThrottleHeavy) {
+		t.Fatalf("stored throttle = %d, want heavy kept", level)
+	}
+}
+
+func TestSpamShortCommentsNotDuplicates(t *testing.T) {
+	svc, _ := newTestSpamService(t)
+	ctx := context.Background()
+	const userID = 9
+
+	for i := 1; i <= 20; i++ {
+		svc.ObserveComment(ctx, userID, uint64(i), uint64(100+i), "好看！", time.Now())
+	}
+	signals, err := svc.collectSignals(ctx, strconv.FormatUint(userID, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signals.DuplicateComments != 0 {
+		t.Fatalf("duplicate comments = %d, want 0 for short comments", signals.DuplicateComments)
+	}
+}
+
+func TestSpamClearedUserNotThrottled(t *testing.T) {
+	svc, risks := newTestSpamService(t)
+	ctx := context.Background()
+	const userID = 9
+
+	if err := redis.SetWithExpiration(ctx, consts.SpamClearedKey+strconv.FormatUint(userID, 10), 1, spamClearedTTL); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 100; i++ {
+		svc.ObserveLike(ctx, userID, uint64(i), time.Now())
+	}
+	if level := throttleLevel(t, userID); level != 0 {
+		t.Fatalf("throttle for cleared user = %d, want none", level)
+	}
+	if len(risks.upserts) != 0 {
+		t.Fatalf("risk upserts for cleared user = %d, want 0", len(risks.upserts))
+	}
+}
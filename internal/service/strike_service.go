@@ -0,0 +1,388 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/mongo"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/pkg/util"
+	"Cornerstone/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	log "log/slog"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+)
+
+const (
+	// strikeWindow 违规次数统计窗口
+	strikeWindow = 90 * 24 * time.Hour
+	// strikeListLimit 违规记录查询上限
+	strikeListLimit = 50
+)
+
+// penaltyRule 违规次数达到 Strikes 时触发的处罚，Duration 为 0 表示永久
+type penaltyRule struct {
+	Strikes  int64
+	Level    int8
+	Duration time.Duration
+}
+
+// penaltyRules 按违规次数从高到低排列
+var penaltyRules = []penaltyRule{
+	{Strikes: 5, Level: model.PenaltyPermanent},
+	{Strikes: 4, Level: model.PenaltyBan, Duration: 7 * 24 * time.Hour},
+	{Strikes: 3, Level: model.PenaltyMute, Duration: 24 * time.Hour},
+	{Strikes: 2, Level: model.PenaltyCooldown, Duration: 3 * 24 * time.Hour},
+}
+
+var strikeSourceNames = map[int8]string{
+	model.StrikeSourcePostAudit:    "发布的笔记未通过审核",
+	model.StrikeSourceCommentAudit: "发布的评论未通过审核",
+	model.StrikeSourceReport:       "被举报的内容经核实违规",
+}
+
+var penaltyNames = map[int8]string{
+	model.PenaltyCooldown:  "发布冷却",
+	model.PenaltyMute:      "禁言",
+	model.PenaltyBan:       "封禁",
+	model.PenaltyPermanent: "永久封禁",
+}
+
+type StrikeService interface {
+	AddStrike(ctx context.Context, userID uint64, source int8, targetID uint64, reason string) error
+	StrikeDeniedPost(ctx context.Context, postID uint64) error
+	RevokeStrike(ctx context.Context, source int8, targetID uint64) error
+	GetMyStrikes(ctx context.Context, userID uint64) (*dto.UserStrikeSummaryDTO, error)
+	ExpirePenalties(ctx context.Context) error
+	RecordManualBan(ctx context.Context, userID uint64, reason string) error
+	LiftAllPenalties(ctx context.Context, userID uint64) error
+}
+
+type strikeServiceImpl struct {
+	strikeRepo repository.StrikeRepo
+	userRepo   repository.UserRepo
+	postRepo   repository.PostRepo
+	sysBoxRepo mongo.SysBoxRepo
+}
+
+func NewStrikeService(strikeRepo repository.StrikeRepo, userRepo repository.UserRepo, postRepo repository.PostRepo, sysBoxRepo mongo.SysBoxRepo) StrikeService {
+	return &strikeServiceImpl{
+		strikeRepo: strikeRepo,
+		userRepo:   userRepo,
+		postRepo:   postRepo,
+		sysBoxRepo: sysBoxRepo,
+	}
+}
+
+// AddStrike 记录一次违规，并按统计窗口内的违规次数升级处罚；同一来源对象重复调用不会重复计数
+func (s *strikeServiceImpl) AddStrike(ctx context.Context, userID uint64, source int8, targetID uint64, reason string) error {
+	if userID == 0 {
+		return nil
+	}
+	strike := &model.UserStrike{
+		UserID:   userID,
+		Source:   source,
+		TargetID: targetID,
+		Reason:   util.TruncateRunes(reason, 200),
+	}
+	created, err := s.strikeRepo.CreateStrike(ctx, strike)
+	if err != nil || !created {
+		return err
+	}
+
+	lockKey := consts.StrikeLock + strconv.FormatUint(userID, 10)
+	lockVal := uuid.NewString()
+	ok, err := redis.TryLock(ctx, lockKey, lockVal, 10*time.Second, 25)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("acquire strike lock timeout")
+	}
+	defer redis.UnLock(ctx, lockKey, lockVal)
+
+	now := time.Now()
+	count, err := s.strikeRepo.CountActiveStrikes(ctx, userID, now.Add(-strikeWindow))
+	if err != nil {
+		return err
+	}
+	s.notifyUser(ctx, userID, strike.ID, fmt.Sprintf("你%s，已记录 1 次违规，近 90 天累计 %d 次。多次违规将被限制发布或封禁账号。", strikeSourceNames[source], count), map[string]any{
+		"source":    source,
+		"target_id": targetID,
+		"count":     count,
+	})
+
+	return s.escalate(ctx, userID, count, strike.Reason, now)
+}
+
+// StrikeDeniedPost 审核员人工拒绝笔记时为作者记录违规
+func (s *strikeServiceImpl) StrikeDeniedPost(ctx context.Context, postID uint64) error {
+	post, err := s.postRepo.GetPostByAllStatus(ctx, postID)
+	if err != nil || post == nil {
+		return err
+	}
+	return s.AddStrike(ctx, post.UserID, model.StrikeSourcePostAudit, post.ID, "笔记《"+util.TruncateRunes(post.Title, 50)+"》人工审核未通过")
+}
+
+// RevokeStrike 申诉通过后撤销对应违规，已生效的处罚不回溯，仅影响后续计数
+func (s *strikeServiceImpl) RevokeStrike(ctx context.Context, source int8, targetID uint64) error {
+	strike, err := s.strikeRepo.RevokeStrike(ctx, source, targetID)
+	if err != nil || strike == nil {
+		return err
+	}
+	s.notifyUser(ctx, strike.UserID, strike.ID, "你的申诉已通过，对应的 1 次违规记录已撤销。", map[string]any{
+		"source":    source,
+		"target_id": targetID,
+		"revoked":   true,
+	})
+	return nil
+}
+
+// GetMyStrikes 获取用户违规记录与当前生效的处罚
+func (s *strikeServiceImpl) GetMyStrikes(ctx context.Context, userID uint64) (*dto.UserStrikeSummaryDTO, error) {
+	now := time.Now()
+	count, err := s.strikeRepo.CountActiveStrikes(ctx, userID, now.Add(-strikeWindow))
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.strikeRepo.GetStrikesByUser(ctx, userID, strikeListLimit)
+	if err != nil {
+		return nil, err
+	}
+	penalty, err := s.strikeRepo.GetActivePenalty(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.UserStrikeSummaryDTO{
+		ActiveCount: count,
+		Strikes:     make([]*dto.UserStrikeDTO, 0, len(list)),
+	}
+	for _, st := range list {
+		res.Strikes = append(res.Strikes, &dto.UserStrikeDTO{
+			ID:        st.ID,
+			Source:    st.Source,
+			TargetID:  st.TargetID,
+			Reason:    st.Reason,
+			Revoked:   st.Revoked,
+			CreatedAt: st.CreatedAt,
+		})
+	}
+	if penalty != nil {
+		res.Penalty = &dto.UserPenaltyDTO{
+			Level:     penalty.Level,
+			Reason:    penalty.Reason,
+			ExpiresAt: penalty.ExpiresAt,
+			CreatedAt: penalty.CreatedAt,
+		}
+	}
+	return res, nil
+}
+
+// ExpirePenalties 解除已到期的处罚，限时封禁到期后恢复登录
+func (s *strikeServiceImpl) ExpirePenalties(ctx context.Context) error {
+	now := time.Now()
+	list, err := s.strikeRepo.GetExpiredPenalties(ctx, now, 200)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range list {
+		ok, err := s.strikeRepo.LiftPenalty(ctx, p.ID, now)
+		if err != nil {
+			log.ErrorContext(ctx, "lift penalty failed", "penaltyId", p.ID, "err", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		active, err := s.syncRestriction(ctx, p.UserID, now)
+		if err != nil {
+			log.ErrorContext(ctx, "sync user restriction failed", "userId", p.UserID, "err", err)
+			continue
+		}
+		if p.Level >= model.PenaltyBan && (active == nil || active.Level < model.PenaltyBan) {
+			if _, err = s.userRepo.UpdateUserIsBan(ctx, p.UserID, false); err != nil {
+				log.ErrorContext(ctx, "unban user failed", "userId", p.UserID, "err", err)
+				continue
+			}
+		}
+		if active == nil {
+			s.notifyUser(ctx, p.UserID, p.ID, fmt.Sprintf("你的账号%s已到期解除，请遵守社区规范。", penaltyNames[p.Level]), map[string]any{
+				"level":  p.Level,
+				"lifted": true,
+			})
+		}
+	}
+	return nil
+}
+
+// RecordManualBan 人工封禁记为永久处罚，限时封禁到期时不会解除，仅管理员解封可撤销
+func (s *strikeServiceImpl) RecordManualBan(ctx context.Context, userID uint64, reason string) error {
+	now := time.Now()
+	active, err := s.strikeRepo.GetActivePenalty(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+	if active != nil && active.Level == model.PenaltyPermanent {
+		return nil
+	}
+	if err = s.strikeRepo.CreatePenalty(ctx, &model.UserPenalty{
+		UserID: userID,
+		Level:  model.PenaltyPermanent,
+		Reason: reason,
+	}); err != nil {
+		return err
+	}
+	_, err = s.syncRestriction(ctx, userID, now)
+	return err
+}
+
+// LiftAllPenalties 管理员解封时解除用户全部生效中的处罚
+func (s *strikeServiceImpl) LiftAllPenalties(ctx context.Context, userID uint64) error {
+	now := time.Now()
+	for {
+		p, err := s.strikeRepo.GetActivePenalty(ctx, userID, now)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			break
+		}
+		if _, err = s.strikeRepo.LiftPenalty(ctx, p.ID, now); err != nil {
+			return err
+		}
+	}
+	return redis.DeleteKey(ctx, consts.UserRestrictionKey+strconv.FormatUint(userID, 10))
+}
+
+// escalate 按违规次数匹配处罚，仅在高于当前生效处罚时升级
+func (s *strikeServiceImpl) escalate(ctx context.Context, userID uint64, count int64, reason string, now time.Time) error {
+	var rule *penaltyRule
+	for i := range penaltyRules {
+		if count >= penaltyRules[i].Strikes {
+			rule = &penaltyRules[i]
+			break
+		}
+	}
+	if rule == nil {
+		return nil
+	}
+
+	active, err := s.strikeRepo.GetActivePenalty(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+	if active != nil && active.Level >= rule.Level {
+		return nil
+	}
+
+	if rule.Level >= model.PenaltyBan {
+		user, err := s.userRepo.GetUserById(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return nil
+		}
+		// 已被管理员手动封禁的账号不再叠加限时封禁，避免到期后被自动解封
+		if user.IsBan && (active == nil || active.Level < model.PenaltyBan) {
+			return nil
+		}
+	}
+
+	penalty := &model.UserPenalty{
+		UserID:      userID,
+		Level:       rule.Level,
+		StrikeCount: int(count),
+		Reason:      reason,
+	}
+	if rule.Duration > 0 {
+		expiresAt := now.Add(rule.Duration)
+		penalty.ExpiresAt = &expiresAt
+	}
+	if err = s.strikeRepo.CreatePenalty(ctx, penalty); err != nil {
+		return err
+	}
+	if _, err = s.syncRestriction(ctx, userID, now); err != nil {
+		return err
+	}
+
+	if rule.Level >= model.PenaltyBan {
+		if _, err = s.userRepo.UpdateUserIsBan(ctx, userID, true); err != nil {
+			return err
+		}
+		key := consts.UserAuthVersionKey + strconv.FormatUint(userID, 10)
+		if err = redis.SetWithExpiration(ctx, key, now.Unix(), 7*24*time.Hour); err != nil {
+			return err
+		}
+	}
+
+	s.notifyUser(ctx, userID, penalty.ID, penaltyNotice(penalty, count), map[string]any{
+		"level":      penalty.Level,
+		"count":      count,
+		"expires_at": penalty.ExpiresAt,
+	})
+	return nil
+}
+
+// syncRestriction 将当前生效的最高处罚写入 Redis，缓存随处罚到期自动失效
+func (s *strikeServiceImpl) syncRestriction(ctx context.Context, userID uint64, now time.Time) (*model.UserPenalty, error) {
+	key := consts.UserRestrictionKey + strconv.FormatUint(userID, 10)
+	active, err := s.strikeRepo.GetActivePenalty(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if active == nil {
+		return nil, redis.DeleteKey(ctx, key)
+	}
+
+	restriction := model.UserRestriction{Level: active.Level}
+	var ttl time.Duration
+	if active.ExpiresAt != nil {
+		restriction.ExpiresAt = active.ExpiresAt.Unix()
+		ttl = active.ExpiresAt.Sub(now)
+	}
+	data, err := json.Marshal(restriction)
+	if err != nil {
+		return nil, err
+	}
+	return active, redis.SetWithExpiration(ctx, key, string(data), ttl)
+}
+
+func (s *strikeServiceImpl) notifyUser(ctx context.Context, userID, targetID uint64, content string, payload map[string]any) {
+	err := sendSystemNotice(ctx, s.sysBoxRepo, &mongo.SysBoxModel{
+		ReceiverID: userID,
+		Type:       mongo.SysBoxTypeStrike,
+		TargetID:   targetID,
+		Content:    content,
+		Payload:    payload,
+	})
+	if err != nil {
+		log.WarnContext(ctx, "send strike notice failed", "userId", userID, "err", err)
+	}
+}
+
+func penaltyNotice(p *model.UserPenalty, count int64) string {
+	var until string
+	if p.ExpiresAt != nil {
+		until = p.ExpiresAt.Format("2006-01-02 15:04")
+	}
+	switch p.Level {
+	case model.PenaltyCooldown:
+		return fmt.Sprintf("由于你近 90 天累计违规 %d 次，账号进入发布冷却期，%s 前每 10 分钟仅可发布一次笔记、评论或私信。", count, until)
+	case model.PenaltyMute:
+		return fmt.Sprintf("由于你近 90 天累计违规 %d 次，账号已被禁言 24 小时，%s 前无法发布笔记、评论或私信。", count, until)
+	case model.PenaltyBan:
+		return fmt.Sprintf("由于你近 90 天累计违规 %d 次，账号已被封禁 7 天，将于 %s 自动解封。", count, until)
+	default:
+		return fmt.Sprintf("由于你近 90 天累计违规 %d 次，账号已被永久封禁。", count)
+	}
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"Cornerstone/internal/model"
+	"Cornerstone/internal/pkg/consts"
+	"Cornerstone/internal/pkg/mongo"
+	"Cornerstone/internal/pkg/redis"
+	"Cornerstone/internal/repository"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+type fakeStrikeRepo struct {
+	strikes   []*model.UserStrike
+	penalties []*model.UserPenalty
+}
+
+func (f *fakeStrikeRepo) CreateStrike(_ context.Context, strike *model.UserStrike) (bool, error) {
+	for _, st := range f.strikes {
+		if st.Source == strike.Source && st.TargetID == strike.TargetID {
+			return false, nil
+		}
+	}
+	strike.ID = uint64(len(f.strikes) + 1)
+	if strike.CreatedAt.IsZero() {
+		strike.CreatedAt = time.Now()
+	}
+	f.strikes = append(f.strikes, strike)
+	return true, nil
+}
+
+func (f *fakeStrikeRepo) RevokeStrike(_ context.Context, source int8, targetID uint64) (*model.UserStrike, error) {
+	for _, st := range f.strikes {
+		if st.Source == source && st.TargetID == targetID && !st.Revoked {
+			st.Revoked = true
+			return st, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeStrikeRepo) CountActiveStrikes(_ context.Context, userID uint64, since time.Time) (int64, error) {
+	var count int64
+	for _, st := range f.strikes {
+		if st.UserID == userID && !st.Revoked && !st.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeStrikeRepo) GetStrikesByUser(_ context.Context, userID uint64, limit int) ([]*model.UserStrike, error) {
+	var list []*model.UserStrike
+	for i := len(f.strikes) - 1; i >= 0 && len(list) < limit; i-- {
+		if f.strikes[i].UserID == userID {
+			list = append(list, f.strikes[i])
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeStrikeRepo) CreatePenalty(_ context.Context, penalty *model.UserPenalty) error {
+	penalty.ID = uint64(len(f.penalties) + 1)
+	penalty.CreatedAt = time.Now()
+	f.penalties = append(f.penalties, penalty)
+	return nil
+}
+
+func (f *fakeStrikeRepo) GetActivePenalty(_ context.Context, userID uint64, now time.Time) (*model.UserPenalty, error) {
+	var active *model.UserPenalty
+	for _, p := range f.penalties {
+		if p.UserID != userID || p.LiftedAt != nil || (p.ExpiresAt != nil && !p.ExpiresAt.After(now)) {
+			continue
+		}
+		if active == nil || p.Level > active.Level {
+			active = p
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeStrikeRepo) GetExpiredPenalties(_ context.Context, now time.Time, limit int) ([]*model.UserPenalty, error) {
+	var list []*model.UserPenalty
+	for _, p := range f.penalties {
+		if p.LiftedAt == nil && p.ExpiresAt != nil && !p.ExpiresAt.After(now) && len(list) < limit {
+			list = append(list, p)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeStrikeRepo) LiftPenalty(_ context.Context, id uint64, now time.Time) (bool, error) {
+	for _, p := range f.penalties {
+		if p.ID == id && p.LiftedAt == nil {
+			p.LiftedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepo
+	users map[uint64]*model.User
+}
+
+func (f *fakeUserRepo) GetUserById(_ context.Context, id uint64) (*model.User, error) {
+	return f.users[id], nil
+}
+
+func (f *fakeUserRepo) UpdateUserIsBan(_ context.Context, id uint64, isBan bool) (int64, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return 0, nil
+	}
+	u.IsBan = isBan
+	return 1, nil
+}
+
+type fakeSysBoxRepo struct {
+	mongo.SysBoxRepo
+	notices []*mongo.SysBoxModel
+}
+
+func (f *fakeSysBoxRepo) CreateNotification(_ context.Context, msg *mongo.SysBoxModel) error {
+	f.notices = append(f.notices, msg)
+	return nil
+}
+
+type strikeFixture struct {
+	svc     *strikeServiceImpl
+	strikes *fakeStrikeRepo
+	users   *fakeUserRepo
+	sysBox  *fakeSysBoxRepo
+}
+
+func newStrikeFixture(t *testing.T, users ...*model.User) *strikeFixture {
+	t.Helper()
+	newTestRedis(t)
+	f := &strikeFixture{
+		strikes: &fakeStrikeRepo{},
+		users:   &fakeUserRepo{users: make(map[uint64]*model.User)},
+		sysBox:  &fakeSysBoxRepo{},
+	}
+	for _, u := range users {
+		f.users.users[u.ID] = u
+	}
+	f.svc = NewStrikeService(f.strikes, f.users, nil, f.sysBox).(*strikeServiceImpl)
+	return f
+}
+
+// restriction 读取 Redis 中缓存的用户处罚
+func (f *strikeFixture) restriction(t *testing.T, userID uint64) *model.UserRestriction {
+	t.Helper()
+	value, err := redis.GetValue(context.Background(), consts.UserRestrictionKey+strconv.FormatUint(userID, 10))
+	if err != nil || value == "" {
+		return nil
+	}
+	var r model.UserRestriction
+	if err = json.Unmarshal([]byte(value), &r); err != nil {
+		t.Fatalf("unmarshal restriction: %v", err)
+	}
+	return &r
+}
+
+func TestAddStrikeEscalation(t *testing.T) {
+	const userID = 7
+	f := newStrikeFixture(t, &model.User{ID: userID})
+	ctx := context.Background()
+
+	wantLevels := []int8{0, model.PenaltyCooldown, model.PenaltyMute, model.PenaltyBan, model.PenaltyPermanent}
+	for i, want := range wantLevels {
+		if err := f.svc.AddStrike(ctx, userID, model.StrikeSourceCommentAudit, uint64(100+i), "违规评论"); err != nil {
+			t.Fatalf("strike %d: %v", i+1, err)
+		}
+		active, _ := f.strikes.GetActivePenalty(ctx, userID, time.Now())
+		r := f.restriction(t, userID)
+		if want == 0 {
+			if active != nil || r != nil {
+				t.Fatalf("strike %d: penalty = %+v, restriction = %+v, want none", i+1, active, r)
+			}
+			continue
+		}
+		if active == nil || active.Level != want {
+			t.Fatalf("strike %d: active penalty = %+v, want level %d", i+1, active, want)
+		}
+		if active.StrikeCount != i+1 {
+			t.Fatalf("strike %d: penalty strike count = %d", i+1, active.StrikeCount)
+		}
+		if r == nil || r.Level != want {
+			t.Fatalf("strike %d: restriction = %+v, want level %d", i+1, r, want)
+		}
+	}
+
+	if !f.users.users[userID].IsBan {
+		t.Fatal("user should be banned after reaching the ban threshold")
+	}
+	if f.restriction(t, userID).ExpiresAt != 0 {
+		t.Fatal("permanent restriction should not expire")
+	}
+}
+
+func TestAddStrikeCountsOncePerTarget(t *testing.T) {
+	const userID = 7
+	f := newStrikeFixture(t, &model.User{ID: userID})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := f.svc.AddStrike(ctx, userID, model.StrikeSourcePostAudit, 1, "违规笔记"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count, _ := f.strikes.CountActiveStrikes(ctx, userID, time.Now().Add(-strikeWindow))
+	if count != 1 {
+		t.Fatalf("active strikes = %d, want 1", count)
+	}
+	if len(f.sysBox.notices) != 1 {
+		t.Fatalf("notices = %d, want 1", len(f.sysBox.notices))
+	}
+	if len(f.strikes.penalties) != 0 {
+		t.Fatalf("penalties = %d, want 0", len(f.strikes.penalties))
+	}
+}
+
+func TestAddStrikeIgnoresStrikesOutsideWindow(t *testing.T) {
+	const userID = 7
+	f := newStrikeFixture(t, &model.User{ID: userID})
+	ctx := context.Background()
+
+	f.strikes.strikes = append(f.strikes.strikes, &model.UserStrike{
+		ID:        1,
+		UserID:    userID,
+		Source:    model.StrikeSourceReport,
+		TargetID:  1,
+		CreatedAt: time.Now().Add(-strikeWindow - time.Hour),
+	})
+	if err := f.svc.AddStrike(ctx, userID, model.StrikeSourceReport, 2, "举报成立"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.strikes.penalties) != 0 {
+		t.Fatalf("penalties = %+v, want none for a single strike inside the window", f.strikes.penalties)
+	}
+}
+
+func TestAddStrikeKeepsManualBan(t *testing.T) {
+	const userID = 7
+	f := newStrikeFixture(t, &model.User{ID: userID, IsBan: true})
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		if err := f.svc.AddStrike(ctx, userID, model.StrikeSourceReport, uint64(i+1), "举报成立"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 人工封禁的账号不叠加会到期自动解封的限时封禁
+	for _, p := range f.strikes.penalties {
+		if p.Level >= model.PenaltyBan {
+			t.Fatalf("unexpected ban penalty %+v for manually banned user", p)
+		}
+	}
+}
+
+func TestRevokeStrike(t *testing.T) {
+	const userID = 7
+	f := newStrikeFixture(t, &model.User{ID: userID})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := f.svc.AddStrike(ctx, userID, model.StrikeSourcePostAudit, uint64(i+1), "违规笔记"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notices := len(f.sysBox.notices)
+
+	if err := f.svc.RevokeStrike(ctx, model.StrikeSourcePostAudit, 2); err != nil {
+		t.Fatal(err)
+	}
+	count, _ := f.strikes.CountActiveStrikes(ctx, userID, time.Now().Add(-strikeWindow))
+	if count != 1 {
+		t.Fatalf("active strikes after revoke = %d, want 1", count)
+	}
+	if len(f.sysBox.notices) != notices+1 {
+		t.Fatalf("revoke should notify the user once, got %d new notices", len(f.sysBox.notices)-notices)
+	}
+	// 已生效的处罚不回溯
+	if active, _ := f.strikes.GetActivePenalty(ctx, userID, time.Now()); active == nil || active.Level != model.PenaltyCooldown {
+		t.Fatalf("active penalty after revoke = %+v, want cooldown kept", active)
+	}
+
+	// 重复撤销与撤销不存在的记录均无副作用
+	if err := f.svc.RevokeStrike(ctx, model.StrikeSourcePostAudit, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.svc.RevokeStrike(ctx, model.StrikeSourceReport, 99); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.sysBox.notices) != notices+1 {
+		t.Fatalf("no-op revokes should not notify, got %d new notices", len(f.sysBox.notices)-notices)
+	}
+
+	// 撤销后的计数决定后续升级：再记一次违规累计 2 次，不会升级到禁言
+	if err := f.svc.AddStrike(ctx, userID, model.StrikeSourcePostAudit, 3, "违规笔记"); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := f.strikes.GetActivePenalty(ctx, userID, time.Now()); active.Level != model.PenaltyCooldown {
+		t.Fatalf("active penalty = %d, want cooldown", active.Level)
+	}
+}
@@ -2,11 +2,15 @@ package service
 
 import (
 	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/consts"
 	"Cornerstone/internal/pkg/minio"
 	"Cornerstone/internal/pkg/mongo"
+	"Cornerstone/internal/pkg/redis"
 	"Cornerstone/internal/repository"
 	"context"
 	"errors"
+	log "log/slog"
+	"strconv"
 	"time"
 
 	"github.com/jinzhu/copier"
@@ -107,3 +111,20 @@ func (s *sysBoxServiceImpl) MarkRead(ctx context.Context, userID uint64, msgID s
 func (s *sysBoxServiceImpl) MarkAllRead(ctx context.Context, userID uint64) error {
 	return s.sysBoxRepo.MarkAllAsRead(ctx, userID)
 }
+
+// sendSystemNotice 写入系统通知并推送未读数更新，推送失败不影响通知落库
+func sendSystemNotice(ctx context.Context, repo mongo.SysBoxRepo, notice *mongo.SysBoxModel) error {
+	notice.CreatedAt = time.Now()
+	if err := repo.CreateNotification(ctx, notice); err != nil {
+		return err
+	}
+
+	channelName := consts.SysBoxUnreadNotifyChannel + strconv.FormatUint(notice.ReceiverID, 10)
+	if err := redis.Publish(ctx, channelName, map[string]any{
+		"type":        "unread_count_update",
+		"receiver_id": notice.ReceiverID,
+	}); err != nil {
+		log.WarnContext(ctx, "publish unread count update failed", "receiverId", notice.ReceiverID, "err", err)
+	}
+	return nil
+}
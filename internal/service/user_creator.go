@@ -0,0 +1,50 @@
+package service
+
+import (
+	"Cornerstone/internal/api/dto"
+	"Cornerstone/internal/pkg/llm"
+	"context"
+)
+
+// GetSimilarCreators 按创作者向量推荐相似创作者，尚未生成画像时返回空列表
+func (s *UserServiceImpl) GetSimilarCreators(ctx context.Context, userID uint64, size int) ([]*dto.UserDTO, error) {
+	user, err := s.userESRepo.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if len(user.CreatorVector) == 0 {
+		return []*dto.UserDTO{}, nil
+	}
+
+	similar, err := s.userESRepo.SimilarCreators(ctx, userID, user.CreatorVector, size)
+	if err != nil {
+		return nil, err
+	}
+	return s.batchToUserDTOFromES(similar)
+}
+
+// RefreshCreatorProfile 重新聚合已发布笔记的标签并生成创作者向量
+func (s *UserServiceImpl) RefreshCreatorProfile(ctx context.Context, userID uint64) error {
+	user, err := s.userESRepo.GetUserById(ctx, userID)
+	if err != nil || user == nil {
+		return err
+	}
+
+	tags, err := s.postESRepo.AggregateCreatorTags(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	bio := ""
+	if user.Bio != nil {
+		bio = *user.Bio
+	}
+	vector, err := llm.GetCreatorVector(ctx, bio, tags.MainTags, tags.AITags)
+	if err != nil {
+		return err
+	}
+	return s.userESRepo.UpdateCreatorProfile(ctx, userID, tags.All(), vector)
+}
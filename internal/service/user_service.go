@@ -5,6 +5,7 @@ import (
 	"Cornerstone/internal/model"
 	"Cornerstone/internal/pkg/consts"
 	"Cornerstone/internal/pkg/es"
+	"Cornerstone/internal/pkg/llm"
 	"Cornerstone/internal/pkg/minio"
 	"Cornerstone/internal/pkg/redis"
 	"Cornerstone/internal/pkg/security"
@@ -14,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/goccy/go-json"
 	"github.com/google/uuid"
@@ -41,6 +43,9 @@ type UserService interface {
 	UnBanUser(ctx context.Context, id uint64) error
 	CancelUser(ctx context.Context, id uint64, token string) error
 	SearchUser(ctx context.Context, keyword string, page, pageSize int) ([]*dto.UserDTO, error)
+	SuggestUser(ctx context.Context, keyword string) ([]string, error)
+	GetSimilarCreators(ctx context.Context, userID uint64, size int) ([]*dto.UserDTO, error)
+	RefreshCreatorProfile(ctx context.Context, userID uint64) error
 	InvalidateUser(ctx context.Context, userID uint64) error
 }
 
@@ -49,14 +54,18 @@ type UserServiceImpl struct {
 	roleRepo      repository.RoleRepo
 	userRolesRepo repository.UserRolesRepo
 	userESRepo    es.UserRepo
+	postESRepo    es.PostRepo
+	strikeService StrikeService
 }
 
-func NewUserService(userRepo repository.UserRepo, roleRepo repository.RoleRepo, userRolesRepo repository.UserRolesRepo, userESRepo es.UserRepo) UserService {
+func NewUserService(userRepo repository.UserRepo, roleRepo repository.RoleRepo, userRolesRepo repository.UserRolesRepo, userESRepo es.UserRepo, postESRepo es.PostRepo, strikeService StrikeService) UserService {
 	return &UserServiceImpl{
 		userRepo:      userRepo,
 		roleRepo:      roleRepo,
 		userRolesRepo: userRolesRepo,
 		userESRepo:    userESRepo,
+		postESRepo:    postESRepo,
+		strikeService: strikeService,
 	}
 }
 
@@ -325,7 +334,7 @@ func (s *UserServiceImpl) GetUserByCondition(ctx context.Context, dto *dto.GetUs
 	} else if dto.Phone != nil {
 		user, err = s.userRepo.GetUserByPhone(ctx, *dto.Phone)
 	} else if dto.Nickname != nil {
-		searchUserList, _, err := s.userESRepo.SearchUser(ctx, *dto.Nickname, dto.Page-1, dto.PageSize)
+		searchUserList, _, err := s.userESRepo.SearchUser(ctx, *dto.Nickname, nil, dto.Page-1, dto.PageSize)
 		if err != nil {
 			return nil, err
 		}
@@ -540,11 +549,19 @@ func (s *UserServiceImpl) BanUser(ctx context.Context, banID uint64, userID uint
 	if err != nil {
 		return err
 	}
+	// 记为永久处罚，避免此前触发的限时封禁到期时自动解封
+	if err = s.strikeService.RecordManualBan(ctx, banID, "管理员封禁"); err != nil {
+		return err
+	}
 	return s.InvalidateUser(ctx, banID)
 }
 
 func (s *UserServiceImpl) UnBanUser(ctx context.Context, id uint64) error {
-	return s.changeUserIsBanStatus(ctx, id, false)
+	if err := s.changeUserIsBanStatus(ctx, id, false); err != nil {
+		return err
+	}
+	// 手动解封同时解除违规累计触发的处罚
+	return s.strikeService.LiftAllPenalties(ctx, id)
 }
 
 func (s *UserServiceImpl) CancelUser(ctx context.Context, id uint64, token string) error {
@@ -569,13 +586,32 @@ func (s *UserServiceImpl) CancelUser(ctx context.Context, id uint64, token strin
 	return s.Logout(ctx, token)
 }
 
+const (
+	// userSearchVectorTimeout 搜人语义召回的向量获取超时，超时后只返回关键词结果
+	userSearchVectorTimeout = 150 * time.Millisecond
+	// userSearchVectorMinRunes 短关键词多为昵称片段，语义召回无意义，直接跳过
+	userSearchVectorMinRunes = 3
+)
+
 func (s *UserServiceImpl) SearchUser(ctx context.Context, keyword string, page, pageSize int) ([]*dto.UserDTO, error) {
 	from := (page - 1) * pageSize
 	if from < 0 {
 		from = 0
 	}
 
-	esUsers, total, err := s.userESRepo.SearchUser(ctx, keyword, from, pageSize)
+	// 语义召回为增强项，向量获取失败或超时时退化为关键词检索
+	var vector []float32
+	if useUserSearchVector(keyword) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, userSearchVectorTimeout)
+		defer cancel()
+		if v, vErr := llm.GetVectorByString(timeoutCtx, keyword); vErr != nil {
+			log.WarnContext(ctx, "llm get vector failed or timeout", "err", vErr)
+		} else {
+			vector = v
+		}
+	}
+
+	esUsers, total, err := s.userESRepo.SearchUser(ctx, keyword, vector, from, pageSize)
 	if err != nil {
 		log.ErrorContext(ctx, "service search user error", "err", err, "keyword", keyword)
 		return nil, err
@@ -588,6 +624,25 @@ func (s *UserServiceImpl) SearchUser(ctx context.Context, keyword string, page,
 	return s.batchToUserDTOFromES(esUsers)
 }
 
+// useUserSearchVector 关键词过短或为纯数字（按 ID 查人）时不做语义召回
+func useUserSearchVector(keyword string) bool {
+	if utf8.RuneCountInString(keyword) < userSearchVectorMinRunes {
+		return false
+	}
+	_, err := strconv.ParseUint(keyword, 10, 64)
+	return err != nil
+}
+
+// SuggestUser 搜人输入补全
+func (s *UserServiceImpl) SuggestUser(ctx context.Context, keyword string) ([]string, error) {
+	suggestions, err := s.userESRepo.GetSuggestions(ctx, keyword)
+	if err != nil {
+		log.ErrorContext(ctx, "service suggest user error", "err", err, "keyword", keyword)
+		return nil, err
+	}
+	return suggestions, nil
+}
+
 func (s *UserServiceImpl) InvalidateUser(ctx context.Context, userID uint64) error {
 	key := consts.UserAuthVersionKey + strconv.FormatUint(userID, 10)
 	return redis.SetWithExpiration(ctx, key, time.Now().Unix(), 7*24*time.Hour)
@@ -26,6 +26,9 @@ type ApplicationContainer struct {
 	Router       *gin.Engine
 	CronMgr      *cron.Manager
 	KafkaManager *kafka.ConsumerManager
+	PostRedriver *kafka.PostRedriver
+	Blocklist    *processor.Blocklist
+	MediaHashes  *processor.MediaHashBlocklist
 }
 
 func BuildApplication(
@@ -46,6 +49,21 @@ func BuildApplication(
 	postMetricsRepo := repository.NewPostMetricRepository(db)
 	userInterestRepo := repository.NewUserInterestRepository(db)
 	conversationRepo := repository.NewConversationRepo(db)
+	recommendEventRepo := repository.NewRecommendEventRepo(db)
+	tagRepo := repository.NewTagRepo(db)
+	reportRepo := repository.NewReportRepo(db)
+	appealRepo := repository.NewAppealRepo(db)
+	moderationRepo := repository.NewModerationRepo(db)
+	blockRuleRepo := repository.NewBlockRuleRepo(db)
+	mediaHashRepo := repository.NewMediaHashRepo(db)
+	strikeRepo := repository.NewStrikeRepo(db)
+	reauditRepo := repository.NewReauditRepo(db)
+	softRestrictionRepo := repository.NewSoftRestrictionRepo(db)
+	spamRiskRepo := repository.NewSpamRiskRepo(db)
+	postCommentControlRepo := repository.NewPostCommentControlRepo(db)
+	postCommentEditRepo := repository.NewPostCommentEditRepo(db)
+	reactionRepo := repository.NewReactionRepo(db)
+	postDiscussionSummaryRepo := repository.NewPostDiscussionSummaryRepo(db)
 
 	// Mongo 实例
 	messageMongoRepo := mongo.NewMessageRepo(mongoConn)
@@ -55,40 +73,73 @@ func BuildApplication(
 	// ES 实例
 	userESRepo := es.NewUserRepo(elasticClient)
 	postESRepo := es.NewPostRepo(elasticClient)
+	indexManager := es.NewIndexManager(elasticClient)
 
 	// Agent
 	toolHandler := llm.NewToolHandler(postESRepo)
 	agent := llm.NewAgent(toolHandler, agentMessageRepo)
 
 	// Processor
-	contentProcesser := processor.NewContentLLMProcessor()
+	blocklist := processor.NewBlocklist(blockRuleRepo)
+	mediaHashBlocklist := processor.NewMediaHashBlocklist(mediaHashRepo)
+	contentProcesser := processor.NewContentLLMProcessor(blocklist, mediaHashBlocklist)
+
+	// Kafka 生产者，对账时重新投递仍待审核的笔记
+	postRedriver, err := kafka.NewPostRedriver(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Service 实例
-	userService := service.NewUserService(userRepo, roleRepo, userRolesRepo, userESRepo)
+	strikeService := service.NewStrikeService(strikeRepo, userRepo, postRepo, sysBoxRepo)
+	userService := service.NewUserService(userRepo, roleRepo, userRolesRepo, userESRepo, postESRepo, strikeService)
 	userRolesService := service.NewUserRolesService(userRolesRepo)
 	userFollowService := service.NewUserFollowService(userRepo, userFollowRepo)
 	userMetricsService := service.NewUserMetricsService(userMetricsRepo, userFollowRepo)
 	userContentMetricsService := service.NewUserContentMetricService(userContentMetricsRepo, postRepo, postActionRepo)
 	smsService := service.NewSmsService()
-	postService := service.NewPostService(postESRepo, postRepo, userInterestRepo)
-	postActionService := service.NewPostActionService(postActionRepo, postRepo, userRepo)
-	postMetricsService := service.NewPostMetricService(postMetricsRepo, postRepo)
+	reactionService := service.NewReactionService(reactionRepo, postActionRepo, postRepo)
+	postActionService := service.NewPostActionService(postActionRepo, postRepo, userRepo, recommendEventRepo, postCommentControlRepo, postCommentEditRepo, userFollowRepo, reactionService)
+	discussionSummaryService := service.NewDiscussionSummaryService(postDiscussionSummaryRepo, postActionRepo, postCommentControlRepo, postRepo, postActionService)
+	postService := service.NewPostService(postESRepo, postRepo, userInterestRepo, recommendEventRepo, userFollowRepo, discussionSummaryService)
+	postMetricsService := service.NewPostMetricService(postMetricsRepo, postRepo, recommendEventRepo)
 	IMService := service.NewIMService(userRepo, conversationRepo, messageMongoRepo)
 	sysBoxService := service.NewSysBoxService(sysBoxRepo, userRepo)
+	onboardingService := service.NewOnboardingService(tagRepo, userInterestRepo, postESRepo)
+	searchIndexService := service.NewSearchIndexService(indexManager, postESRepo, userESRepo, postRepo, userRepo, postRedriver)
+	mediaHashService := service.NewMediaHashService(mediaHashRepo, postRepo, postActionRepo, moderationRepo, mediaHashBlocklist)
+	reportService := service.NewReportService(reportRepo, postRepo, postActionRepo, userRepo, userRolesRepo, conversationRepo, messageMongoRepo, sysBoxRepo, mediaHashService, strikeService)
+	appealService := service.NewAppealService(appealRepo, reportRepo, postRepo, postActionRepo, sysBoxRepo, postService, mediaHashService, strikeService)
+	moderationService := service.NewModerationService(moderationRepo)
+	blockRuleService := service.NewBlockRuleService(blockRuleRepo)
+	reauditService := service.NewReauditService(reauditRepo, contentProcesser)
+	softRestrictionService := service.NewSoftRestrictionService(softRestrictionRepo, userRepo, postRepo)
+	spamService := service.NewSpamService(spamRiskRepo, userRepo, softRestrictionService)
 
 	handlers := &api.HandlersGroup{
 		AgentHandler:             handler.NewAgentHandler(agent),
 		UserHandler:              handler.NewUserHandler(userService, userRolesService, smsService),
 		UserFollowHandler:        handler.NewUserFollowHandler(userFollowService),
 		UserMetricHandler:        handler.NewUserMetricsHandler(userMetricsService),
-		PostHandler:              handler.NewPostHandler(postService),
-		PostActionHandler:        handler.NewPostActionHandler(postService, postActionService),
+		PostHandler:              handler.NewPostHandler(postService, mediaHashService, strikeService),
+		PostActionHandler:        handler.NewPostActionHandler(postService, postActionService, reactionService),
 		PostMetricHandler:        handler.NewPostMetricHandler(postMetricsService),
 		UserContentMetricHandler: handler.NewUserContentMetricHandler(userContentMetricsService),
 		IMHandler:                handler.NewIMHandler(IMService),
 		WSHandler:                handler.NewWsHandler(IMService),
 		SysBoxHandler:            handler.NewSysBoxHandler(sysBoxService),
-		MediaHandler:             handler.NewMediaHandler(),
+		MediaHandler:             handler.NewMediaHandler(mediaHashService),
+		OnboardingHandler:        handler.NewOnboardingHandler(onboardingService),
+		SearchIndexHandler:       handler.NewSearchIndexHandler(searchIndexService),
+		ReportHandler:            handler.NewReportHandler(reportService),
+		AppealHandler:            handler.NewAppealHandler(appealService),
+		ModerationHandler:        handler.NewModerationHandler(moderationService),
+		BlockRuleHandler:         handler.NewBlockRuleHandler(blockRuleService),
+		MediaHashHandler:         handler.NewMediaHashHandler(mediaHashService),
+		StrikeHandler:            handler.NewStrikeHandler(strikeService),
+		ReauditHandler:           handler.NewReauditHandler(reauditService),
+		SoftRestrictionHandler:   handler.NewSoftRestrictionHandler(softRestrictionService),
+		SpamHandler:              handler.NewSpamHandler(spamService),
 	}
 
 	router := api.SetupRouter(handlers)
@@ -99,12 +150,19 @@ func BuildApplication(
 	userInterestJOb := job.NewUserInterestJob(userInterestRepo)
 	postCommentJob := job.NewPostCommentJob(postActionService)
 	mediaCleanJob := job.NewMediaCleanupJob()
-	cronMgr := cron.NewCronManager(userMetricsJob, postMetricsJob, userInterestJOb, postCommentJob, mediaCleanJob)
+	hotSearchJob := job.NewHotSearchJob(postService)
+	searchReconcileJob := job.NewSearchReconcileJob(searchIndexService)
+	creatorProfileJob := job.NewCreatorProfileJob(userService)
+	penaltyExpireJob := job.NewPenaltyExpireJob(strikeService)
+	softRestrictionSyncJob := job.NewSoftRestrictionSyncJob(softRestrictionService)
+	reactionJob := job.NewReactionJob(reactionService)
+	cronMgr := cron.NewCronManager(userMetricsJob, postMetricsJob, userInterestJOb, postCommentJob, mediaCleanJob, hotSearchJob, searchReconcileJob, creatorProfileJob, penaltyExpireJob, softRestrictionSyncJob, reactionJob)
 
 	// Kafka 消费者管理
 	kafkaMgr, err := kafka.NewConsumerManager(cfg, contentProcesser, userESRepo, postESRepo, sysBoxRepo,
-		userRepo, postActionRepo, userFollowRepo, postRepo)
+		userRepo, postActionRepo, postCommentEditRepo, userFollowRepo, postRepo, moderationRepo, strikeService, spamService)
 	if err != nil {
+		_ = postRedriver.Close()
 		return nil, err
 	}
 
@@ -113,5 +171,8 @@ func BuildApplication(
 		Router:       router,
 		CronMgr:      cronMgr,
 		KafkaManager: kafkaMgr,
+		PostRedriver: postRedriver,
+		Blocklist:    blocklist,
+		MediaHashes:  mediaHashBlocklist,
 	}, nil
 }
@@ -0,0 +1,15 @@
+CREATE TABLE `recommend_events`
+(
+    `id`            BIGINT      NOT NULL AUTO_INCREMENT COMMENT '事件ID',
+    `experiment_id` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '实验ID，空字符串表示未进入实验',
+    `variant`       VARCHAR(64) NOT NULL DEFAULT 'default' COMMENT '实验分组',
+    `user_id`       BIGINT      NOT NULL COMMENT '用户ID',
+    `post_id`       BIGINT      NOT NULL COMMENT '笔记ID',
+    `event_type`    TINYINT     NOT NULL COMMENT '事件类型: 1-曝光, 2-点击, 3-点赞, 4-停留',
+    `dwell_ms`      INT         NOT NULL DEFAULT 0 COMMENT '停留时长(毫秒)，仅停留事件有效',
+    `created_at`    DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '事件时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_experiment_created` (`experiment_id`, `created_at`),
+    KEY `idx_user_post` (`user_id`, `post_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='推荐实验曝光与行为事件表';
@@ -0,0 +1,11 @@
+CREATE TABLE `onboarding_tags`
+(
+    `tag_id`     BIGINT   NOT NULL COMMENT '标签ID (主标签)',
+    `sort_order` INT      NOT NULL DEFAULT 0 COMMENT '展示顺序，越小越靠前',
+    `enabled`    TINYINT  NOT NULL DEFAULT 1 COMMENT '是否展示: 0-隐藏, 1-展示',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`tag_id`),
+    KEY `idx_sort_order` (`sort_order`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='新用户兴趣引导标签表 (管理员维护)';
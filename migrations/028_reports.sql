@@ -0,0 +1,39 @@
+CREATE TABLE `report_cases`
+(
+    `id`           BIGINT        NOT NULL AUTO_INCREMENT COMMENT '工单ID',
+    `target_type`  TINYINT       NOT NULL COMMENT '举报对象类型: 1-笔记, 2-评论, 3-用户, 4-私信',
+    `target_id`    BIGINT        NOT NULL COMMENT '举报对象ID (私信为会话ID)',
+    `target_seq`   BIGINT        NOT NULL DEFAULT 0 COMMENT '私信消息序号，其他类型为0',
+    `owner_id`     BIGINT        NOT NULL DEFAULT 0 COMMENT '被举报内容的作者ID',
+    `snapshot`     VARCHAR(1000) NOT NULL DEFAULT '' COMMENT '首次举报时的内容快照',
+    `report_count` INT           NOT NULL DEFAULT 0 COMMENT '举报人数',
+    `priority`     INT           NOT NULL DEFAULT 0 COMMENT '优先级，按举报原因权重累加',
+    `status`       TINYINT       NOT NULL DEFAULT 0 COMMENT '状态: 0-待处理, 1-处理中, 2-已处置, 3-已驳回',
+    `claimed_by`   BIGINT        NOT NULL DEFAULT 0 COMMENT '认领审核员ID',
+    `claimed_at`   DATETIME      NULL COMMENT '认领时间',
+    `handled_by`   BIGINT        NOT NULL DEFAULT 0 COMMENT '处理审核员ID',
+    `handle_note`  VARCHAR(500)  NOT NULL DEFAULT '' COMMENT '处理备注',
+    `handled_at`   DATETIME      NULL COMMENT '处理时间',
+    `created_at`   DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`   DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `open_flag`    TINYINT       GENERATED ALWAYS AS (IF(`status` IN (0, 1), 1, NULL)) VIRTUAL COMMENT '未结案为1，结案后为NULL，用于约束同一对象只有一个未结案工单',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_open_target` (`target_type`, `target_id`, `target_seq`, `open_flag`),
+    KEY `idx_target` (`target_type`, `target_id`, `target_seq`, `status`),
+    KEY `idx_status_priority` (`status`, `priority`, `created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='举报审核工单表，同一对象未结案的举报合并为一个工单';
+
+CREATE TABLE `reports`
+(
+    `id`          BIGINT       NOT NULL AUTO_INCREMENT COMMENT '举报ID',
+    `case_id`     BIGINT       NOT NULL COMMENT '所属工单ID',
+    `reporter_id` BIGINT       NOT NULL COMMENT '举报人ID',
+    `reason`      TINYINT      NOT NULL COMMENT '举报原因: 1-垃圾广告, 2-色情低俗, 3-暴力血腥, 4-人身攻击, 5-违法违规, 6-虚假信息, 7-侵权, 99-其他',
+    `detail`      VARCHAR(500) NOT NULL DEFAULT '' COMMENT '补充说明',
+    `created_at`  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '举报时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_case_reporter` (`case_id`, `reporter_id`),
+    KEY `idx_reporter` (`reporter_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='用户举报明细表';
@@ -0,0 +1,21 @@
+CREATE TABLE `appeals`
+(
+    `id`           BIGINT        NOT NULL AUTO_INCREMENT COMMENT '申诉ID',
+    `target_type`  TINYINT       NOT NULL COMMENT '申诉对象类型: 1-笔记, 2-评论',
+    `target_id`    BIGINT        NOT NULL COMMENT '申诉对象ID',
+    `user_id`      BIGINT        NOT NULL COMMENT '申诉人 (内容作者) ID',
+    `message`      VARCHAR(500)  NOT NULL COMMENT '申诉理由',
+    `snapshot`     VARCHAR(1000) NOT NULL DEFAULT '' COMMENT '申诉时的内容快照',
+    `llm_decision` VARCHAR(2000) NOT NULL DEFAULT '' COMMENT '模型审核结论 JSON，结论过期或人工下架时为空',
+    `status`       TINYINT       NOT NULL DEFAULT 0 COMMENT '状态: 0-待处理, 1-已通过, 2-已驳回',
+    `handled_by`   BIGINT        NOT NULL DEFAULT 0 COMMENT '处理审核员ID',
+    `handle_note`  VARCHAR(500)  NOT NULL DEFAULT '' COMMENT '处理备注',
+    `handled_at`   DATETIME      NULL COMMENT '处理时间',
+    `created_at`   DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`   DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_target` (`target_type`, `target_id`),
+    KEY `idx_user` (`user_id`, `created_at`),
+    KEY `idx_status_created` (`status`, `created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='被拒绝内容的作者申诉表';
@@ -0,0 +1,22 @@
+CREATE TABLE `moderation_verdicts`
+(
+    `id`             BIGINT        NOT NULL AUTO_INCREMENT COMMENT '记录ID',
+    `target_type`    TINYINT       NOT NULL COMMENT '审核对象类型: 1-笔记, 2-评论',
+    `target_id`      BIGINT        NOT NULL COMMENT '审核对象ID',
+    `audit_id`       VARCHAR(36)   NOT NULL COMMENT '审核批次ID，同一次审核的子结论共用',
+    `source`         VARCHAR(20)   NOT NULL COMMENT '审核来源: text, image_batch, video_frame, audio',
+    `refs`           JSON          NULL COMMENT '送审媒体地址，视频帧为 视频地址#帧序号',
+    `input`          VARCHAR(1000) NOT NULL DEFAULT '' COMMENT '送审文本摘录，音频为转写文本',
+    `status`         TINYINT       NOT NULL COMMENT '审核结论: 1-通过, 2-疑似, 3-拒绝',
+    `reason`         VARCHAR(500)  NOT NULL DEFAULT '' COMMENT '模型给出的判定原因',
+    `confidence`     DOUBLE        NOT NULL DEFAULT 0 COMMENT '模型给出的置信度',
+    `model`          VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '模型名称',
+    `prompt_version` VARCHAR(64)   NOT NULL DEFAULT '' COMMENT '提示词版本',
+    `latency_ms`     INT           NOT NULL DEFAULT 0 COMMENT '模型调用耗时 (含排队)',
+    `raw`            TEXT          NULL COMMENT '模型原始返回',
+    `created_at`     DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_target` (`target_type`, `target_id`, `created_at`),
+    KEY `idx_audit` (`audit_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='模型内容审核记录表，每次模型调用一条';
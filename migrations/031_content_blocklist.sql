@@ -0,0 +1,16 @@
+CREATE TABLE `content_blocklist`
+(
+    `id`          BIGINT       NOT NULL AUTO_INCREMENT COMMENT '规则ID',
+    `pattern`     VARCHAR(200) NOT NULL COMMENT '屏蔽词或正则表达式',
+    `match_type`  TINYINT      NOT NULL DEFAULT 1 COMMENT '匹配方式: 1-关键词, 2-正则',
+    `action`      TINYINT      NOT NULL DEFAULT 3 COMMENT '命中处理: 2-转人工复核, 3-直接拒绝',
+    `fold_pinyin` TINYINT(1)   NOT NULL DEFAULT 0 COMMENT '关键词是否按拼音匹配谐音变体',
+    `enabled`     TINYINT(1)   NOT NULL DEFAULT 1 COMMENT '是否启用',
+    `remark`      VARCHAR(200) NOT NULL DEFAULT '' COMMENT '备注',
+    `created_by`  BIGINT       NOT NULL DEFAULT 0 COMMENT '创建人ID',
+    `created_at`  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_type_pattern` (`match_type`, `pattern`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='内容审核屏蔽词表';
@@ -0,0 +1,31 @@
+CREATE TABLE `media_hashes`
+(
+    `id`         BIGINT          NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `media_url`  VARCHAR(512)    NOT NULL COMMENT '媒体地址 (MinIO 对象名)',
+    `frame`      INT             NOT NULL DEFAULT 0 COMMENT '帧序号: 0-图片本身, N-视频第 N 个抽帧',
+    `phash`      BIGINT UNSIGNED NOT NULL COMMENT '感知哈希 pHash',
+    `dhash`      BIGINT UNSIGNED NOT NULL COMMENT '差异哈希 dHash',
+    `created_at` DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_media_frame` (`media_url`, `frame`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='上传图片与视频抽帧的感知哈希';
+
+CREATE TABLE `media_hash_blocklist`
+(
+    `id`          BIGINT          NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `phash`       BIGINT UNSIGNED NOT NULL COMMENT '感知哈希 pHash',
+    `dhash`       BIGINT UNSIGNED NOT NULL COMMENT '差异哈希 dHash',
+    `source`      TINYINT         NOT NULL DEFAULT 1 COMMENT '来源: 1-审核员添加, 2-拒绝确认后自动添加',
+    `media_url`   VARCHAR(512)    NOT NULL DEFAULT '' COMMENT '来源媒体地址',
+    `frame`       INT             NOT NULL DEFAULT 0 COMMENT '来源帧序号',
+    `target_type` TINYINT         NOT NULL DEFAULT 0 COMMENT '来源内容类型: 0-无, 1-笔记, 2-评论',
+    `target_id`   BIGINT          NOT NULL DEFAULT 0 COMMENT '来源内容ID',
+    `remark`      VARCHAR(200)    NOT NULL DEFAULT '' COMMENT '备注',
+    `created_by`  BIGINT          NOT NULL DEFAULT 0 COMMENT '创建人ID，自动添加时为确认拒绝的审核员',
+    `created_at`  DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_hash` (`phash`, `dhash`),
+    KEY `idx_created` (`created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='违规图片感知哈希黑名单';
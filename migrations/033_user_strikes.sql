@@ -0,0 +1,30 @@
+CREATE TABLE `user_strikes`
+(
+    `id`         BIGINT       NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id`    BIGINT       NOT NULL COMMENT '违规用户ID',
+    `source`     TINYINT      NOT NULL COMMENT '来源: 1-笔记审核拒绝, 2-评论审核拒绝, 3-举报成立',
+    `target_id`  BIGINT       NOT NULL COMMENT '来源对象ID: 笔记ID、评论ID或举报工单ID',
+    `reason`     VARCHAR(200) NOT NULL DEFAULT '' COMMENT '违规原因',
+    `revoked`    TINYINT(1)   NOT NULL DEFAULT 0 COMMENT '是否已撤销 (申诉通过)',
+    `created_at` DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_source_target` (`source`, `target_id`),
+    KEY `idx_user_created` (`user_id`, `created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='用户违规记录表';
+
+CREATE TABLE `user_penalties`
+(
+    `id`           BIGINT       NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id`      BIGINT       NOT NULL COMMENT '处罚用户ID',
+    `level`        TINYINT      NOT NULL COMMENT '处罚等级: 1-发布冷却, 2-禁言, 3-封禁, 4-永久封禁',
+    `strike_count` INT          NOT NULL DEFAULT 0 COMMENT '触发处罚时的有效违规次数',
+    `reason`       VARCHAR(200) NOT NULL DEFAULT '' COMMENT '触发处罚的违规原因',
+    `expires_at`   DATETIME     NULL COMMENT '到期时间，永久封禁为空',
+    `lifted_at`    DATETIME     NULL COMMENT '解除时间',
+    `created_at`   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_user_level` (`user_id`, `lifted_at`),
+    KEY `idx_expires` (`lifted_at`, `expires_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='用户违规处罚表';
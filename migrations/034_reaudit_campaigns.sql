@@ -0,0 +1,42 @@
+CREATE TABLE `reaudit_campaigns`
+(
+    `id`             BIGINT       NOT NULL AUTO_INCREMENT COMMENT '任务ID',
+    `target_type`    TINYINT      NOT NULL COMMENT '复审对象: 1-笔记, 2-评论',
+    `tag`            VARCHAR(50)  NOT NULL DEFAULT '' COMMENT '按笔记标签筛选，仅笔记有效',
+    `content_status` TINYINT      NOT NULL DEFAULT 0 COMMENT '按审核状态筛选，0 表示通过与警告',
+    `start_at`       DATETIME     NULL COMMENT '内容创建时间下限',
+    `end_at`         DATETIME     NULL COMMENT '内容创建时间上限',
+    `max_items`      INT          NOT NULL DEFAULT 0 COMMENT '最多复审条数，0 表示不限',
+    `concurrency`    TINYINT      NOT NULL DEFAULT 2 COMMENT '并发复审数',
+    `state`          TINYINT      NOT NULL DEFAULT 1 COMMENT '任务状态: 1-运行中, 2-已暂停, 3-已完成, 4-应用中, 5-已应用, 6-失败',
+    `cursor_id`      BIGINT       NOT NULL DEFAULT 0 COMMENT '已扫描的最大内容ID，用于断点续跑',
+    `scanned`        INT          NOT NULL DEFAULT 0 COMMENT '已复审条数',
+    `changed`        INT          NOT NULL DEFAULT 0 COMMENT '状态发生变化的条数',
+    `failed`         INT          NOT NULL DEFAULT 0 COMMENT '复审失败条数',
+    `applied`        INT          NOT NULL DEFAULT 0 COMMENT '已应用的变更条数',
+    `remark`         VARCHAR(200) NOT NULL DEFAULT '' COMMENT '备注，如策略变更说明',
+    `error`          VARCHAR(500) NOT NULL DEFAULT '' COMMENT '失败原因',
+    `created_by`     BIGINT       NOT NULL DEFAULT 0 COMMENT '创建人ID',
+    `created_at`     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at`     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    `finished_at`    DATETIME     NULL COMMENT '复审完成时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_state` (`state`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='内容复审任务表';
+
+CREATE TABLE `reaudit_items`
+(
+    `id`          BIGINT       NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `campaign_id` BIGINT       NOT NULL COMMENT '复审任务ID',
+    `target_id`   BIGINT       NOT NULL COMMENT '笔记ID或评论ID',
+    `old_status`  TINYINT      NOT NULL COMMENT '复审前状态',
+    `new_status`  TINYINT      NOT NULL COMMENT '复审结果状态',
+    `reason`      VARCHAR(200) NOT NULL DEFAULT '' COMMENT '模型给出的原因',
+    `apply_state` TINYINT      NOT NULL DEFAULT 0 COMMENT '应用状态: 0-待应用, 1-已应用, 2-已跳过(内容状态已变化)',
+    `created_at`  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_campaign_target` (`campaign_id`, `target_id`),
+    KEY `idx_campaign_apply` (`campaign_id`, `apply_state`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='内容复审状态变更表';
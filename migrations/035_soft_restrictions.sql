@@ -0,0 +1,16 @@
+CREATE TABLE `soft_restrictions`
+(
+    `id`         BIGINT       NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `kind`       TINYINT      NOT NULL COMMENT '处置类型: 1-影子封禁用户, 2-限流笔记',
+    `target_id`  BIGINT       NOT NULL COMMENT '处置对象ID: 用户ID或笔记ID',
+    `reason`     VARCHAR(200) NOT NULL DEFAULT '' COMMENT '处置原因',
+    `created_by` BIGINT       NOT NULL COMMENT '操作审核员ID',
+    `expires_at` DATETIME     NOT NULL COMMENT '到期时间',
+    `lifted_at`  DATETIME     NULL COMMENT '提前解除时间',
+    `created_at` DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `updated_at` DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_kind_target` (`kind`, `target_id`, `lifted_at`),
+    KEY `idx_kind_expires` (`kind`, `lifted_at`, `expires_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='软处置记录表';
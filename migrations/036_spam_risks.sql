@@ -0,0 +1,21 @@
+CREATE TABLE `spam_risks`
+(
+    `id`                 BIGINT   NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id`            BIGINT   NOT NULL COMMENT '用户ID',
+    `score`              INT      NOT NULL DEFAULT 0 COMMENT '最近一次风险分 (0-100)',
+    `peak_score`         INT      NOT NULL DEFAULT 0 COMMENT '历史最高风险分',
+    `burst_likes`        INT      NOT NULL DEFAULT 0 COMMENT '短时点赞数',
+    `follow_churn`       INT      NOT NULL DEFAULT 0 COMMENT '关注后短时取关数',
+    `duplicate_comments` INT      NOT NULL DEFAULT 0 COMMENT '跨笔记重复评论数',
+    `new_account_links`  INT      NOT NULL DEFAULT 0 COMMENT '新账号发布的含链接评论数',
+    `throttle_level`     TINYINT  NOT NULL DEFAULT 0 COMMENT '限频等级: 0-无, 1-轻度, 2-重度',
+    `state`              TINYINT  NOT NULL DEFAULT 1 COMMENT '审核状态: 1-待复核, 2-误判已放行, 3-确认垃圾行为',
+    `reviewed_by`        BIGINT   NOT NULL DEFAULT 0 COMMENT '复核审核员ID',
+    `reviewed_at`        DATETIME NULL COMMENT '复核时间',
+    `created_at`         DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '首次命中时间',
+    `updated_at`         DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最近命中时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_user` (`user_id`),
+    KEY `idx_state_score` (`state`, `score`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='垃圾行为风险表';
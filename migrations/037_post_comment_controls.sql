@@ -0,0 +1,23 @@
+CREATE TABLE `post_comment_settings`
+(
+    `post_id`      BIGINT   NOT NULL COMMENT '笔记ID',
+    `comment_mode` TINYINT  NOT NULL DEFAULT 0 COMMENT '评论权限: 0-所有人, 1-仅粉丝, 2-关闭评论',
+    `created_at`   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`post_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='笔记评论设置表';
+
+CREATE TABLE `post_comment_marks`
+(
+    `id`         BIGINT   NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `post_id`    BIGINT   NOT NULL COMMENT '笔记ID',
+    `comment_id` BIGINT   NOT NULL COMMENT '评论ID',
+    `user_id`    BIGINT   NOT NULL COMMENT '评论者ID，隐藏后仍对其可见',
+    `kind`       TINYINT  NOT NULL COMMENT '标记类型: 1-作者置顶, 2-作者隐藏',
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_comment_kind` (`comment_id`, `kind`),
+    KEY `idx_post_kind` (`post_id`, `kind`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='笔记作者评论标记表';
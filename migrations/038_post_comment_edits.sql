@@ -0,0 +1,14 @@
+CREATE TABLE `post_comment_edits`
+(
+    `id`               BIGINT        NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `comment_id`       BIGINT        NOT NULL COMMENT '评论ID',
+    `user_id`          BIGINT        NOT NULL COMMENT '评论者ID',
+    `previous_content` VARCHAR(1000) NOT NULL COMMENT '编辑前内容',
+    `content`          VARCHAR(1000) NOT NULL COMMENT '编辑后内容',
+    `status`           TINYINT       NOT NULL DEFAULT 0 COMMENT '审核状态: 0-待审核, 1-已生效, 2-未通过',
+    `reviewed_at`      DATETIME      NULL COMMENT '审核完成时间',
+    `created_at`       DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '提交编辑时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_comment_status` (`comment_id`, `status`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='评论编辑历史表';
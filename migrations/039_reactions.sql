@@ -0,0 +1,25 @@
+CREATE TABLE `reactions`
+(
+    `id`          BIGINT      NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `target_type` TINYINT     NOT NULL COMMENT '回应对象类型: 1-笔记, 2-评论',
+    `target_id`   BIGINT      NOT NULL COMMENT '回应对象ID',
+    `user_id`     BIGINT      NOT NULL COMMENT '回应用户ID',
+    `emoji`       VARCHAR(32) NOT NULL COMMENT '表情代码',
+    `created_at`  DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`  DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_target_user` (`target_type`, `target_id`, `user_id`),
+    KEY `idx_user_target` (`user_id`, `target_type`, `target_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='表情回应表';
+
+CREATE TABLE `reaction_counts`
+(
+    `target_type` TINYINT     NOT NULL COMMENT '回应对象类型: 1-笔记, 2-评论',
+    `target_id`   BIGINT      NOT NULL COMMENT '回应对象ID',
+    `emoji`       VARCHAR(32) NOT NULL COMMENT '表情代码',
+    `count`       INT         NOT NULL DEFAULT 0 COMMENT '回应数，由定时任务从 Redis 回写',
+    `updated_at`  DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`target_type`, `target_id`, `emoji`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='表情回应计数表';
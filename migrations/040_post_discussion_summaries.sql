@@ -0,0 +1,12 @@
+CREATE TABLE `post_discussion_summaries`
+(
+    `post_id`       BIGINT       NOT NULL COMMENT '笔记ID',
+    `overview`      VARCHAR(500) NOT NULL DEFAULT '' COMMENT '评论区概述',
+    `viewpoints`    JSON         NOT NULL COMMENT '主要观点及代表评论ID',
+    `comment_count` INT          NOT NULL DEFAULT 0 COMMENT '生成时的评论数，用于判断是否需要刷新',
+    `model`         VARCHAR(64)  NOT NULL DEFAULT '' COMMENT '生成所用模型',
+    `created_at`    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最近一次生成时间',
+    PRIMARY KEY (`post_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='笔记评论区讨论总结表';
@@ -0,0 +1,2 @@
+ALTER TABLE `post_comments`
+    ADD COLUMN `edit_pending` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否有待审核的编辑' AFTER `is_deleted`;